@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/config"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	apiKeyRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/api_key"
+	apiKeyService "github.com/m04kA/SMC-LoyaltySystemService/internal/service/apikey"
+)
+
+const apiKeyUsage = `Usage:
+  apikey issue -name <service> -scopes <scope,...>   выпустить ключ (значение выводится один раз)
+  apikey revoke -prefix <prefix>                     отозвать ключ по префиксу
+  apikey list                                        список ключей
+
+Scopes: %s
+`
+
+// runAPIKeyCommand выполняет административную подкоманду apikey и возвращает код завершения
+func runAPIKeyCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, apiKeyUsage, strings.Join(scopeNames(), ", "))
+		return 2
+	}
+
+	cfg, err := config.Load("config.toml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	db, err := sql.Open("postgres", cfg.Database.DSN())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	svc := apiKeyService.NewService(apiKeyRepo.NewRepository(db))
+
+	switch args[0] {
+	case "issue":
+		return issueAPIKey(ctx, svc, args[1:])
+	case "revoke":
+		return revokeAPIKey(ctx, svc, args[1:])
+	case "list":
+		return listAPIKeys(ctx, svc)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown apikey command %q\n\n", args[0])
+		fmt.Fprintf(os.Stderr, apiKeyUsage, strings.Join(scopeNames(), ", "))
+		return 2
+	}
+}
+
+func issueAPIKey(ctx context.Context, svc *apiKeyService.Service, args []string) int {
+	flags := flag.NewFlagSet("apikey issue", flag.ContinueOnError)
+	name := flags.String("name", "", "имя внутреннего сервиса (например, booking)")
+	scopes := flags.String("scopes", "", "права через запятую: "+strings.Join(scopeNames(), ","))
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	rawKey, key, err := svc.Issue(ctx, *name, strings.Split(*scopes, ","))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to issue API key: %v\n", err)
+		if errors.Is(err, apiKeyService.ErrInvalidInput) {
+			return 2
+		}
+		return 1
+	}
+
+	fmt.Printf("API key issued: id=%d, name=%s, prefix=%s, scopes=%s\n", key.ID, key.Name, key.Prefix, joinScopes(key.Scopes))
+	fmt.Println("Store the key now, it cannot be shown again:")
+	fmt.Println(rawKey)
+	return 0
+}
+
+func revokeAPIKey(ctx context.Context, svc *apiKeyService.Service, args []string) int {
+	flags := flag.NewFlagSet("apikey revoke", flag.ContinueOnError)
+	prefix := flags.String("prefix", "", "префикс ключа из вывода apikey list")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	key, err := svc.Revoke(ctx, *prefix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to revoke API key: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("API key revoked: id=%d, name=%s, prefix=%s\n", key.ID, key.Name, key.Prefix)
+	return 0
+}
+
+func listAPIKeys(ctx context.Context, svc *apiKeyService.Service) int {
+	keys, err := svc.List(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list API keys: %v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tREVOKED")
+	for _, key := range keys {
+		revoked := "-"
+		if key.RevokedAt != nil {
+			revoked = key.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, key.Prefix, joinScopes(key.Scopes), key.CreatedAt.Format(time.RFC3339), revoked)
+	}
+	w.Flush()
+
+	return 0
+}
+
+func scopeNames() []string {
+	return scopesToStrings(domain.APIKeyScopes)
+}
+
+func joinScopes(scopes []domain.APIKeyScope) string {
+	return strings.Join(scopesToStrings(scopes), ",")
+}
+
+func scopesToStrings(scopes []domain.APIKeyScope) []string {
+	names := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		names = append(names, string(scope))
+	}
+	return names
+}
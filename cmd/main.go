@@ -9,26 +9,67 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// База часовых поясов встроена в бинарник: в образе alpine нет tzdata, а часовые пояса акций задаются по IANA
+	_ "time/tzdata"
 
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/accrue_points"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/cancel_config_schedule"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/change_card_status"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/configure_loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/create_campaign"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/create_loyalty_card"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/delete_campaign"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/get_happy_hours"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/get_loyalty_card"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/get_loyalty_config"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/list_audit_events"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/list_campaigns"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/list_company_loyalty_cards"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/list_config_schedules"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/list_config_versions"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/list_user_loyalty_cards"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/record_visit"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/redeem_points"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/restore_config_version"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/save_happy_hours"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers/verify_loyalty_card"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/middleware"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/config"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	apiKeyRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/api_key"
+	auditEventRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/audit_event"
+	loyaltyCampaignRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_campaign"
 	loyaltyCardRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_card"
 	loyaltyConfigRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_config"
+	loyaltyHappyHoursRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_happy_hours"
+	loyaltyTransactionRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_transaction"
+	loyaltyVisitRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_visit"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+	apiKeyService "github.com/m04kA/SMC-LoyaltySystemService/internal/service/apikey"
 	loyaltyService "github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	loyaltyModels "github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/workers/card_expiry"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/workers/company_reconciliation"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/workers/config_activator"
 	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/jwtauth"
 	"github.com/m04kA/SMC-LoyaltySystemService/pkg/logger"
 	"github.com/m04kA/SMC-LoyaltySystemService/pkg/metrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/qrtoken"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/simpletxmanager"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/txmanager"
 )
 
 func main() {
+	// Административные подкоманды выполняются вместо запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		os.Exit(runAPIKeyCommand(os.Args[2:]))
+	}
+
 	// Загружаем конфигурацию
 	cfg, err := config.Load("config.toml")
 	if err != nil {
@@ -77,15 +118,47 @@ func main() {
 		cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
 
 	// Инициализируем клиент SellerService
-	sellerClient := sellerservice.NewClient(
-		cfg.SellerService.BaseURL,
-		time.Duration(cfg.SellerService.Timeout)*time.Second,
-		log,
-	)
-	log.Info("SellerService client initialized (base_url=%s)", cfg.SellerService.BaseURL)
+	var sellerMetrics sellerservice.Metrics
+	if metricsCollector != nil {
+		sellerMetrics = metricsCollector
+	}
+
+	var sellerClient sellerservice.API = sellerservice.NewClient(sellerservice.Config{
+		BaseURL:                 cfg.SellerService.BaseURL,
+		Timeout:                 time.Duration(cfg.SellerService.Timeout) * time.Second,
+		MaxRetries:              cfg.SellerService.MaxRetries,
+		RetryBaseDelay:          time.Duration(cfg.SellerService.RetryBaseDelay) * time.Millisecond,
+		RetryMaxDelay:           time.Duration(cfg.SellerService.RetryMaxDelay) * time.Millisecond,
+		BreakerFailureThreshold: cfg.SellerService.BreakerFailureThreshold,
+		BreakerOpenTimeout:      time.Duration(cfg.SellerService.BreakerOpenTimeout) * time.Second,
+		Timezone:                cfg.SellerService.Timezone,
+		ServiceName:             cfg.Metrics.ServiceName,
+	}, sellerMetrics, log)
+	log.Info("SellerService client initialized (base_url=%s, max_retries=%d)", cfg.SellerService.BaseURL, cfg.SellerService.MaxRetries)
+
+	if cfg.SellerService.CacheEnabled {
+		var cacheMetrics sellerservice.CacheMetrics
+		if metricsCollector != nil {
+			cacheMetrics = metricsCollector
+		}
+
+		sellerClient = sellerservice.NewCachedClient(sellerClient, sellerservice.CacheConfig{
+			TTL:         time.Duration(cfg.SellerService.CacheTTL) * time.Second,
+			NegativeTTL: time.Duration(cfg.SellerService.CacheNegativeTTL) * time.Second,
+			MaxEntries:  cfg.SellerService.CacheMaxEntries,
+			ServiceName: cfg.Metrics.ServiceName,
+		}, cacheMetrics)
+		log.Info("SellerService company cache enabled (ttl=%ds, negative_ttl=%ds, max_entries=%d)",
+			cfg.SellerService.CacheTTL, cfg.SellerService.CacheNegativeTTL, cfg.SellerService.CacheMaxEntries)
+	}
+
+	// Инициализируем подпись QR-токенов карт
+	qrSigner := qrtoken.NewSigner(cfg.QR.SecretKey, time.Duration(cfg.QR.TTL)*time.Second)
 
 	// Инициализируем репозитории и сервисы (с метриками или без)
 	var loyaltySvc *loyaltyService.Service
+	var apiKeySvc *apiKeyService.Service
+	var configRepository *loyaltyConfigRepo.Repository
 
 	if cfg.Metrics.Enabled {
 		wrappedDB = dbmetrics.WrapWithDefault(db, metricsCollector, cfg.Metrics.ServiceName, stopMetricsCh)
@@ -93,21 +166,119 @@ func main() {
 
 		// Инициализируем репозитории с обёрткой метрик
 		cardRepository := loyaltyCardRepo.NewRepository(wrappedDB)
-		configRepository := loyaltyConfigRepo.NewRepository(wrappedDB)
-
-		loyaltySvc = loyaltyService.NewService(cardRepository, configRepository, sellerClient)
+		configRepository = loyaltyConfigRepo.NewRepository(wrappedDB)
+		campaignRepository := loyaltyCampaignRepo.NewRepository(wrappedDB)
+		happyHoursRepository := loyaltyHappyHoursRepo.NewRepository(wrappedDB)
+		transactionRepository := loyaltyTransactionRepo.NewRepository(wrappedDB)
+		visitRepository := loyaltyVisitRepo.NewRepository(wrappedDB)
+		auditRepository := auditEventRepo.NewRepository(wrappedDB)
+		apiKeyRepository := apiKeyRepo.NewRepository(wrappedDB)
+		txManager := txmanager.NewTransactionManager(wrappedDB)
+
+		loyaltySvc = loyaltyService.NewService(cardRepository, configRepository, campaignRepository, happyHoursRepository, transactionRepository, visitRepository, auditRepository, sellerClient, qrSigner, txManager, log)
+		apiKeySvc = apiKeyService.NewService(apiKeyRepository)
 	} else {
 		// Инициализируем репозитории без метрик
 		cardRepository := loyaltyCardRepo.NewRepository(db)
-		configRepository := loyaltyConfigRepo.NewRepository(db)
+		configRepository = loyaltyConfigRepo.NewRepository(db)
+		campaignRepository := loyaltyCampaignRepo.NewRepository(db)
+		happyHoursRepository := loyaltyHappyHoursRepo.NewRepository(db)
+		transactionRepository := loyaltyTransactionRepo.NewRepository(db)
+		visitRepository := loyaltyVisitRepo.NewRepository(db)
+		auditRepository := auditEventRepo.NewRepository(db)
+		apiKeyRepository := apiKeyRepo.NewRepository(db)
+		txManager := simpletxmanager.NewTransactionManager(db)
+
+		loyaltySvc = loyaltyService.NewService(cardRepository, configRepository, campaignRepository, happyHoursRepository, transactionRepository, visitRepository, auditRepository, sellerClient, qrSigner, txManager, log)
+		apiKeySvc = apiKeyService.NewService(apiKeyRepository)
+	}
+
+	// Запускаем фоновый процесс истечения карт
+	var cardExpiryWorker *card_expiry.Worker
+	if cfg.CardExpiry.Enabled {
+		var expiryMetrics card_expiry.Metrics
+		if metricsCollector != nil {
+			expiryMetrics = metricsCollector
+		}
 
-		loyaltySvc = loyaltyService.NewService(cardRepository, configRepository, sellerClient)
+		cardExpiryWorker = card_expiry.NewWorker(loyaltySvc, expiryMetrics, log, card_expiry.Config{
+			Interval:    time.Duration(cfg.CardExpiry.Interval) * time.Second,
+			BatchSize:   cfg.CardExpiry.BatchSize,
+			ServiceName: cfg.Metrics.ServiceName,
+		})
+		cardExpiryWorker.Start()
+		log.Info("Card expiry worker started (interval=%ds, batch_size=%d)", cfg.CardExpiry.Interval, cfg.CardExpiry.BatchSize)
+	}
+
+	// Запускаем фоновый процесс сверки компаний с SellerService
+	var reconciliationWorker *company_reconciliation.Worker
+	if cfg.CompanyReconciliation.Enabled {
+		var reconciliationMetrics company_reconciliation.Metrics
+		if metricsCollector != nil {
+			reconciliationMetrics = metricsCollector
+		}
+
+		reconciliationWorker = company_reconciliation.NewWorker(configRepository, loyaltySvc, sellerClient, reconciliationMetrics, log, company_reconciliation.Config{
+			Interval:    time.Duration(cfg.CompanyReconciliation.Interval) * time.Second,
+			BatchSize:   cfg.CompanyReconciliation.BatchSize,
+			ServiceName: cfg.Metrics.ServiceName,
+		})
+		reconciliationWorker.Start()
+		log.Info("Company reconciliation worker started (interval=%ds, batch_size=%d)", cfg.CompanyReconciliation.Interval, cfg.CompanyReconciliation.BatchSize)
+	}
+
+	// Запускаем фоновый процесс переноса запланированных изменений программ лояльности
+	var configActivatorWorker *config_activator.Worker
+	if cfg.ConfigActivator.Enabled {
+		var activatorMetrics config_activator.Metrics
+		if metricsCollector != nil {
+			activatorMetrics = metricsCollector
+		}
+
+		configActivatorWorker = config_activator.NewWorker(loyaltySvc, activatorMetrics, log, config_activator.Config{
+			Interval:    time.Duration(cfg.ConfigActivator.Interval) * time.Second,
+			ServiceName: cfg.Metrics.ServiceName,
+		})
+		configActivatorWorker.Start()
+		log.Info("Config activator worker started (interval=%ds)", cfg.ConfigActivator.Interval)
 	}
 
 	// Инициализируем handlers
 	getLoyaltyCardHandler := get_loyalty_card.NewHandler(loyaltySvc, log)
 	createLoyaltyCardHandler := create_loyalty_card.NewHandler(loyaltySvc, log)
 	configureLoyaltyHandler := configure_loyalty.NewHandler(loyaltySvc, log)
+	getLoyaltyConfigHandler := get_loyalty_config.NewHandler(loyaltySvc, log)
+	listConfigVersionsHandler := list_config_versions.NewHandler(loyaltySvc, log)
+	restoreConfigVersionHandler := restore_config_version.NewHandler(loyaltySvc, log)
+	listConfigSchedulesHandler := list_config_schedules.NewHandler(loyaltySvc, log)
+	cancelConfigScheduleHandler := cancel_config_schedule.NewHandler(loyaltySvc, log)
+	createCampaignHandler := create_campaign.NewHandler(loyaltySvc, log)
+	listCampaignsHandler := list_campaigns.NewHandler(loyaltySvc, log)
+	deleteCampaignHandler := delete_campaign.NewHandler(loyaltySvc, log)
+	getHappyHoursHandler := get_happy_hours.NewHandler(loyaltySvc, log)
+	saveHappyHoursHandler := save_happy_hours.NewHandler(loyaltySvc, log)
+	listUserLoyaltyCardsHandler := list_user_loyalty_cards.NewHandler(loyaltySvc, log)
+	listCompanyLoyaltyCardsHandler := list_company_loyalty_cards.NewHandler(loyaltySvc, log)
+	listAuditEventsHandler := list_audit_events.NewHandler(loyaltySvc, log)
+	accruePointsHandler := accrue_points.NewHandler(loyaltySvc, log)
+	redeemPointsHandler := redeem_points.NewHandler(loyaltySvc, log)
+	recordVisitHandler := record_visit.NewHandler(loyaltySvc, log)
+	verifyLoyaltyCardHandler := verify_loyalty_card.NewHandler(loyaltySvc, log)
+	suspendCardHandler := change_card_status.NewHandler(loyaltySvc, log, loyaltyModels.CardStatusActionSuspend)
+	disableCardHandler := change_card_status.NewHandler(loyaltySvc, log, loyaltyModels.CardStatusActionDisable)
+	reactivateCardHandler := change_card_status.NewHandler(loyaltySvc, log, loyaltyModels.CardStatusActionReactivate)
+
+	// Выбираем режим аутентификации: заголовки от API Gateway или JWT
+	authMiddleware, optionalAuthMiddleware := mux.MiddlewareFunc(middleware.Auth), mux.MiddlewareFunc(middleware.OptionalAuth)
+	if cfg.Auth.Mode == config.AuthModeJWT {
+		verifier, err := newJWTVerifier(cfg.Auth.JWT)
+		if err != nil {
+			log.Fatal("Failed to initialize JWT verifier: %v", err)
+		}
+		authMiddleware = middleware.JWTAuth(verifier)
+		optionalAuthMiddleware = middleware.OptionalJWTAuth(verifier)
+		log.Info("JWT authentication enabled: algorithm=%s", cfg.Auth.JWT.Algorithm)
+	}
 
 	// Настраиваем роутер
 	r := mux.NewRouter()
@@ -127,16 +298,69 @@ func main() {
 	// API prefix
 	api := r.PathPrefix("/api/v1").Subrouter()
 
-	// Public routes (не требуют аутентификации)
-	api.HandleFunc("/loyalty-cards", getLoyaltyCardHandler.Handle).Methods(http.MethodGet)
-	api.HandleFunc("/loyalty-cards", createLoyaltyCardHandler.Handle).Methods(http.MethodPost)
-
-	// Protected routes (требуют X-User-ID)
+	// Routes для внутренних сервисов (запросы с X-API-Key, права ограничены scopes ключа)
+	// Регистрируются первыми: запросы без X-API-Key сюда не попадают и обрабатываются ниже
+	scoped := func(scope domain.APIKeyScope, handler http.HandlerFunc) http.Handler {
+		return middleware.RequireScope(scope)(handler)
+	}
+	internal := api.Headers(middleware.APIKeyHeader, "").Subrouter()
+	internal.Use(middleware.APIKeyAuth(apiKeySvc))
+	internal.Handle("/loyalty-cards", scoped(domain.APIKeyScopeCardsRead, getLoyaltyCardHandler.Handle)).Methods(http.MethodGet)
+	internal.Handle("/loyalty-cards", scoped(domain.APIKeyScopeCardsWrite, createLoyaltyCardHandler.Handle)).Methods(http.MethodPost)
+	internal.Handle("/users/{userId}/loyalty-cards", scoped(domain.APIKeyScopeCardsRead, listUserLoyaltyCardsHandler.Handle)).Methods(http.MethodGet)
+	internal.Handle("/loyalty-cards/verify", scoped(domain.APIKeyScopeCardsRead, verifyLoyaltyCardHandler.Handle)).Methods(http.MethodPost)
+	internal.Handle("/loyalty-cards/{cardId}/points/accrue", scoped(domain.APIKeyScopePointsWrite, accruePointsHandler.Handle)).Methods(http.MethodPost)
+	internal.Handle("/loyalty-cards/{cardId}/points/redeem", scoped(domain.APIKeyScopePointsWrite, redeemPointsHandler.Handle)).Methods(http.MethodPost)
+	internal.Handle("/loyalty-cards/{cardId}/visits", scoped(domain.APIKeyScopeVisitsWrite, recordVisitHandler.Handle)).Methods(http.MethodPost)
+
+	// Routes с необязательной аутентификацией (аутентифицированный пользователь получает расширенный ответ)
+	optional := api.PathPrefix("").Subrouter()
+	optional.Use(optionalAuthMiddleware)
+	optional.HandleFunc("/companies/{companyId}/loyalty-config", getLoyaltyConfigHandler.Handle).Methods(http.MethodGet)
+
+	// Protected routes (требуют аутентификации)
 	protected := api.PathPrefix("").Subrouter()
-	protected.Use(middleware.Auth)
+	protected.Use(authMiddleware)
+
+	// Protected routes для карт клиента
+	protected.HandleFunc("/loyalty-cards", getLoyaltyCardHandler.Handle).Methods(http.MethodGet)
+	protected.HandleFunc("/loyalty-cards", createLoyaltyCardHandler.Handle).Methods(http.MethodPost)
 
 	// Protected routes для конфигурации лояльности
 	protected.HandleFunc("/companies/{companyId}/loyalty-config", configureLoyaltyHandler.Handle).Methods(http.MethodPost)
+	protected.HandleFunc("/companies/{companyId}/loyalty-config/versions", listConfigVersionsHandler.Handle).Methods(http.MethodGet)
+	protected.HandleFunc("/companies/{companyId}/loyalty-config/versions/{version}/restore", restoreConfigVersionHandler.Handle).Methods(http.MethodPost)
+	protected.HandleFunc("/companies/{companyId}/loyalty-config/schedules", listConfigSchedulesHandler.Handle).Methods(http.MethodGet)
+	protected.HandleFunc("/companies/{companyId}/loyalty-config/schedules/{scheduleId}", cancelConfigScheduleHandler.Handle).Methods(http.MethodDelete)
+	protected.HandleFunc("/companies/{companyId}/campaigns", createCampaignHandler.Handle).Methods(http.MethodPost)
+	protected.HandleFunc("/companies/{companyId}/campaigns", listCampaignsHandler.Handle).Methods(http.MethodGet)
+	protected.HandleFunc("/companies/{companyId}/campaigns/{campaignId}", deleteCampaignHandler.Handle).Methods(http.MethodDelete)
+	protected.HandleFunc("/companies/{companyId}/happy-hours", getHappyHoursHandler.Handle).Methods(http.MethodGet)
+	protected.HandleFunc("/companies/{companyId}/happy-hours", saveHappyHoursHandler.Handle).Methods(http.MethodPut)
+
+	// Protected routes для списка карт компании (JSON или выгрузка CSV)
+	protected.HandleFunc("/companies/{companyId}/loyalty-cards", listCompanyLoyaltyCardsHandler.Handle).Methods(http.MethodGet)
+
+	// Protected routes для журнала изменений компании
+	protected.HandleFunc("/companies/{companyId}/audit-events", listAuditEventsHandler.Handle).Methods(http.MethodGet)
+
+	// Protected routes для списка карт клиента
+	protected.HandleFunc("/users/{userId}/loyalty-cards", listUserLoyaltyCardsHandler.Handle).Methods(http.MethodGet)
+
+	// Protected routes для проверки QR-кода карты на кассе
+	protected.HandleFunc("/loyalty-cards/verify", verifyLoyaltyCardHandler.Handle).Methods(http.MethodPost)
+
+	// Protected routes для управления статусом карты
+	protected.HandleFunc("/loyalty-cards/{cardId}/suspend", suspendCardHandler.Handle).Methods(http.MethodPost)
+	protected.HandleFunc("/loyalty-cards/{cardId}/disable", disableCardHandler.Handle).Methods(http.MethodPost)
+	protected.HandleFunc("/loyalty-cards/{cardId}/reactivate", reactivateCardHandler.Handle).Methods(http.MethodPost)
+
+	// Protected routes для операций с баллами (накопительная система)
+	protected.HandleFunc("/loyalty-cards/{cardId}/points/accrue", accruePointsHandler.Handle).Methods(http.MethodPost)
+	protected.HandleFunc("/loyalty-cards/{cardId}/points/redeem", redeemPointsHandler.Handle).Methods(http.MethodPost)
+
+	// Protected routes для учёта визитов (прогрессивная скидка)
+	protected.HandleFunc("/loyalty-cards/{cardId}/visits", recordVisitHandler.Handle).Methods(http.MethodPost)
 
 	// Создаем HTTP сервер
 	addr := fmt.Sprintf(":%d", cfg.Server.HTTPPort)
@@ -179,5 +403,51 @@ func main() {
 		log.Error("Server forced to shutdown: %v", err)
 	}
 
+	// Останавливаем фоновый процесс истечения карт
+	if cardExpiryWorker != nil {
+		if err := cardExpiryWorker.Stop(shutdownCtx); err != nil {
+			log.Error("Card expiry worker forced to stop: %v", err)
+		} else {
+			log.Info("Card expiry worker stopped")
+		}
+	}
+
+	if reconciliationWorker != nil {
+		if err := reconciliationWorker.Stop(shutdownCtx); err != nil {
+			log.Error("Company reconciliation worker forced to stop: %v", err)
+		} else {
+			log.Info("Company reconciliation worker stopped")
+		}
+	}
+
+	if configActivatorWorker != nil {
+		if err := configActivatorWorker.Stop(shutdownCtx); err != nil {
+			log.Error("Config activator worker forced to stop: %v", err)
+		} else {
+			log.Info("Config activator worker stopped")
+		}
+	}
+
 	log.Info("Server stopped gracefully")
 }
+
+// newJWTVerifier создает проверку JWT по настройкам: HS256 с общим секретом или RS256 с ключами из JWKS
+func newJWTVerifier(cfg config.JWTConfig) (*jwtauth.Verifier, error) {
+	opts := jwtauth.Options{
+		Issuer:      cfg.Issuer,
+		Audience:    cfg.Audience,
+		UserIDClaim: cfg.UserIDClaim,
+		RoleClaim:   cfg.RoleClaim,
+		Leeway:      time.Duration(cfg.Leeway) * time.Second,
+	}
+
+	if cfg.Algorithm == jwtauth.AlgorithmRS256 {
+		keys, err := jwtauth.LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		return jwtauth.NewRS256Verifier(keys, opts), nil
+	}
+
+	return jwtauth.NewHS256Verifier(cfg.Secret, opts), nil
+}
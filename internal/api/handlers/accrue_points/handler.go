@@ -0,0 +1,104 @@
+package accrue_points
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID      = "отсутствует заголовок X-User-ID"
+	msgInvalidCardID      = "некорректный cardId"
+	msgInvalidRequestBody = "некорректное тело запроса"
+	msgInvalidInput       = "некорректные входные данные"
+	msgAccessDenied       = "доступ запрещён: пользователь не является менеджером компании"
+	msgCardNotFound       = "карта лояльности не найдена"
+	msgConfigNotFound     = "программа лояльности не настроена для данной компании"
+	msgConfigDisabled     = "программа лояльности отключена для данной компании"
+	msgPointsNotSupported = "программа лояльности компании не является накопительной"
+	msgCardNotActive      = "карта лояльности не активна"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle POST /api/v1/loyalty-cards/{cardId}/points/accrue
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим cardId из URL
+	cardID, err := strconv.ParseInt(mux.Vars(r)["cardId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Invalid cardId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCardID)
+		return
+	}
+
+	// 3. Парсим request body
+	var req models.AccruePointsRequest
+	if err := handlers.DecodeJSON(r, &req); err != nil {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Invalid request body: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidRequestBody)
+		return
+	}
+
+	// 4. Вызываем сервис
+	tx, err := h.service.AccruePoints(r.Context(), cardID, actor, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, loyalty.ErrInvalidInput):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Invalid input: card_id=%d, error=%v", cardID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+		case errors.Is(err, loyalty.ErrCardNotFound):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Card not found: card_id=%d", cardID)
+			handlers.RespondNotFound(w, msgCardNotFound)
+		case errors.Is(err, loyalty.ErrAccessDenied):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Access denied: user_id=%d, card_id=%d", actor.UserID, cardID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+		case errors.Is(err, loyalty.ErrConfigNotFound):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Config not found: card_id=%d", cardID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+		case errors.Is(err, loyalty.ErrConfigDisabled):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Config disabled: card_id=%d", cardID)
+			handlers.RespondForbidden(w, msgConfigDisabled)
+		case errors.Is(err, loyalty.ErrPointsNotSupported):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Points not supported: card_id=%d", cardID)
+			handlers.RespondConflict(w, msgPointsNotSupported)
+		case errors.Is(err, loyalty.ErrCardNotActive):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/points/accrue - Card not active: card_id=%d", cardID)
+			handlers.RespondConflict(w, msgCardNotActive)
+		case handlers.IsSellerServiceError(err):
+			h.logger.Error("POST /loyalty-cards/{cardId}/points/accrue - SellerService error: card_id=%d, error=%v", cardID, err)
+			handlers.RespondSellerServiceError(w, err)
+		default:
+			h.logger.Error("POST /loyalty-cards/{cardId}/points/accrue - Failed to accrue points: user_id=%d, card_id=%d, error=%v", actor.UserID, cardID, err)
+			handlers.RespondInternalError(w)
+		}
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("POST /loyalty-cards/{cardId}/points/accrue - Points accrued: user_id=%d, card_id=%d, points=%d, balance=%d", actor.UserID, cardID, tx.Points, tx.PointsBalance)
+	handlers.RespondJSON(w, http.StatusOK, tx)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/middleware"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// ActorFromContext собирает пользователя запроса из контекста (установлен middleware.Auth)
+// или внутренний сервис, если запрос аутентифицирован по API-ключу (middleware.APIKeyAuth)
+func ActorFromContext(ctx context.Context) (models.Actor, bool) {
+	if key, ok := middleware.GetAPIKey(ctx); ok {
+		return models.Actor{Service: key.Name}, true
+	}
+
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return models.Actor{}, false
+	}
+
+	role, _ := middleware.GetUserRole(ctx)
+
+	return models.Actor{UserID: userID, Role: role}, true
+}
@@ -0,0 +1,19 @@
+package cancel_config_schedule
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	CancelConfigSchedule(ctx context.Context, companyID, scheduleID int64, actor models.Actor) (*models.LoyaltyConfigScheduleResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
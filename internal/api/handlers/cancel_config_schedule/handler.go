@@ -0,0 +1,98 @@
+package cancel_config_schedule
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+)
+
+const (
+	msgMissingUserID     = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID  = "некорректный companyId"
+	msgInvalidScheduleID = "некорректный scheduleId"
+	msgAccessDenied      = "доступ запрещён: пользователь не является менеджером компании"
+	msgCompanyNotFound   = "компания не найдена"
+	msgScheduleNotFound  = "ожидающее изменение программы не найдено"
+	msgScheduleDue       = "изменение программы уже вступило в силу"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle DELETE /api/v1/companies/{companyId}/loyalty-config/schedules/{scheduleId}
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId и scheduleId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	scheduleID, err := strconv.ParseInt(vars["scheduleId"], 10, 64)
+	if err != nil || scheduleID <= 0 {
+		h.logger.Warn("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Invalid scheduleId: %s", vars["scheduleId"])
+		handlers.RespondBadRequest(w, msgInvalidScheduleID)
+		return
+	}
+
+	// 3. Вызываем сервис
+	schedule, err := h.service.CancelConfigSchedule(r.Context(), companyID, scheduleID, actor)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Company not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgCompanyNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigScheduleNotFound) {
+			h.logger.Warn("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Schedule not found: company_id=%d, schedule_id=%d", companyID, scheduleID)
+			handlers.RespondNotFound(w, msgScheduleNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigScheduleAlreadyDue) {
+			h.logger.Warn("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Schedule already in effect: company_id=%d, schedule_id=%d", companyID, scheduleID)
+			handlers.RespondConflict(w, msgScheduleDue)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Failed to cancel schedule: user_id=%d, company_id=%d, schedule_id=%d, error=%v", actor.UserID, companyID, scheduleID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 4. Возвращаем успешный ответ
+	h.logger.Info("DELETE /companies/{companyId}/loyalty-config/schedules/{scheduleId} - Schedule canceled: user_id=%d, company_id=%d, schedule_id=%d", actor.UserID, companyID, scheduleID)
+	handlers.RespondJSON(w, http.StatusOK, schedule)
+}
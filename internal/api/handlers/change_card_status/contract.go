@@ -0,0 +1,19 @@
+package change_card_status
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	ChangeCardStatus(ctx context.Context, cardID int64, actor models.Actor, action models.CardStatusAction, req *models.ChangeCardStatusRequest) (*models.LoyaltyCardResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -0,0 +1,101 @@
+package change_card_status
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID           = "отсутствует заголовок X-User-ID"
+	msgInvalidCardID           = "некорректный cardId"
+	msgInvalidRequestBody      = "некорректное тело запроса"
+	msgInvalidInput            = "некорректные входные данные"
+	msgAccessDenied            = "доступ запрещён: пользователь не является менеджером компании"
+	msgCardNotFound            = "карта лояльности не найдена"
+	msgConfigNotFound          = "программа лояльности не настроена для данной компании"
+	msgInvalidStatusTransition = "недопустимая смена статуса карты лояльности"
+)
+
+// Handler обрабатывает смену статуса карты; одно действие (suspend/disable/reactivate) на экземпляр
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+	action  models.CardStatusAction
+}
+
+func NewHandler(service LoyaltyService, logger Logger, action models.CardStatusAction) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+		action:  action,
+	}
+}
+
+// Handle POST /api/v1/loyalty-cards/{cardId}/{suspend|disable|reactivate}
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Missing user ID in context", h.action)
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим cardId из URL
+	cardID, err := strconv.ParseInt(mux.Vars(r)["cardId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Invalid cardId: %v", h.action, err)
+		handlers.RespondBadRequest(w, msgInvalidCardID)
+		return
+	}
+
+	// 3. Парсим request body (для reactivate тело может отсутствовать)
+	var req models.ChangeCardStatusRequest
+	if r.ContentLength != 0 {
+		if err := handlers.DecodeJSON(r, &req); err != nil {
+			h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Invalid request body: %v", h.action, err)
+			handlers.RespondBadRequest(w, msgInvalidRequestBody)
+			return
+		}
+	}
+
+	// 4. Вызываем сервис
+	card, err := h.service.ChangeCardStatus(r.Context(), cardID, actor, h.action, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, loyalty.ErrInvalidInput):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Invalid input: card_id=%d, error=%v", h.action, cardID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+		case errors.Is(err, loyalty.ErrCardNotFound):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Card not found: card_id=%d", h.action, cardID)
+			handlers.RespondNotFound(w, msgCardNotFound)
+		case errors.Is(err, loyalty.ErrAccessDenied):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Access denied: user_id=%d, card_id=%d", h.action, actor.UserID, cardID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+		case errors.Is(err, loyalty.ErrConfigNotFound):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Config not found: card_id=%d", h.action, cardID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+		case errors.Is(err, loyalty.ErrInvalidStatusTransition):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/%s - Invalid status transition: card_id=%d, error=%v", h.action, cardID, err)
+			handlers.RespondConflict(w, msgInvalidStatusTransition)
+		case handlers.IsSellerServiceError(err):
+			h.logger.Error("POST /loyalty-cards/{cardId}/%s - SellerService error: card_id=%d, error=%v", h.action, cardID, err)
+			handlers.RespondSellerServiceError(w, err)
+		default:
+			h.logger.Error("POST /loyalty-cards/{cardId}/%s - Failed to change card status: user_id=%d, card_id=%d, error=%v", h.action, actor.UserID, cardID, err)
+			handlers.RespondInternalError(w)
+		}
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("POST /loyalty-cards/{cardId}/%s - Card status changed: user_id=%d, card_id=%d, status=%s", h.action, actor.UserID, cardID, card.Status)
+	handlers.RespondJSON(w, http.StatusOK, card)
+}
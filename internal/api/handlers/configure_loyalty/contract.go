@@ -8,7 +8,7 @@ import (
 
 // LoyaltyService интерфейс сервиса лояльности
 type LoyaltyService interface {
-	ConfigureLoyalty(ctx context.Context, companyID, userID int64, req *models.ConfigureLoyaltyRequest) (*models.LoyaltyConfigResponse, error)
+	ConfigureLoyalty(ctx context.Context, companyID int64, actor models.Actor, req *models.ConfigureLoyaltyRequest) (*models.LoyaltyConfigResponse, error)
 }
 
 // Logger интерфейс для логирования
@@ -4,11 +4,11 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
-	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/middleware"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
 )
@@ -19,6 +19,8 @@ const (
 	msgInvalidRequestBody = "некорректное тело запроса"
 	msgAccessDenied       = "доступ запрещён: пользователь не является менеджером компании"
 	msgInvalidInput       = "некорректные входные данные"
+	msgConfigNotFound     = "программа лояльности не настроена: изменение можно запланировать только для настроенной программы"
+	msgScheduleConflict   = "на это время уже запланировано изменение программы"
 )
 
 type Handler struct {
@@ -36,7 +38,7 @@ func NewHandler(service LoyaltyService, logger Logger) *Handler {
 // Handle POST /api/v1/companies/{companyId}/loyalty-config
 func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
-	userID, ok := middleware.GetUserID(r.Context())
+	actor, ok := handlers.ActorFromContext(r.Context())
 	if !ok {
 		h.logger.Warn("POST /companies/{companyId}/loyalty-config - Missing user ID in context")
 		handlers.RespondUnauthorized(w, msgMissingUserID)
@@ -63,10 +65,10 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 4. Вызываем сервис
-	config, err := h.service.ConfigureLoyalty(r.Context(), companyID, userID, &req)
+	config, err := h.service.ConfigureLoyalty(r.Context(), companyID, actor, &req)
 	if err != nil {
 		if errors.Is(err, loyalty.ErrAccessDenied) {
-			h.logger.Warn("POST /companies/{companyId}/loyalty-config - Access denied: user_id=%d, company_id=%d", userID, companyID)
+			h.logger.Warn("POST /companies/{companyId}/loyalty-config - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
 			handlers.RespondForbidden(w, msgAccessDenied)
 			return
 		}
@@ -75,17 +77,34 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 			handlers.RespondBadRequest(w, msgInvalidInput)
 			return
 		}
-		if errors.Is(err, loyalty.ErrSellerServiceUnavailable) {
-			h.logger.Error("POST /companies/{companyId}/loyalty-config - SellerService unavailable: company_id=%d, error=%v", companyID, err)
-			handlers.RespondInternalError(w)
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("POST /companies/{companyId}/loyalty-config - Config not found for scheduled change: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
 			return
 		}
-		h.logger.Error("POST /companies/{companyId}/loyalty-config - Failed to configure loyalty: user_id=%d, company_id=%d, error=%v", userID, companyID, err)
+		if errors.Is(err, loyalty.ErrConfigScheduleConflict) {
+			h.logger.Warn("POST /companies/{companyId}/loyalty-config - Schedule conflict: company_id=%d, effective_from=%v", companyID, req.EffectiveFrom)
+			handlers.RespondConflict(w, msgScheduleConflict)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("POST /companies/{companyId}/loyalty-config - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("POST /companies/{companyId}/loyalty-config - Failed to configure loyalty: user_id=%d, company_id=%d, error=%v", actor.UserID, companyID, err)
 		handlers.RespondInternalError(w)
 		return
 	}
 
-	// 5. Возвращаем успешный ответ
-	h.logger.Info("POST /companies/{companyId}/loyalty-config - Loyalty configured successfully: user_id=%d, company_id=%d", userID, companyID)
+	// 5. Возвращаем успешный ответ (202 - изменение запланировано и начнёт действовать позже)
+	if config.ScheduledChange != nil {
+		h.logger.Info("POST /companies/{companyId}/loyalty-config - Loyalty config change scheduled: user_id=%d, company_id=%d, schedule_id=%d, effective_from=%s",
+			actor.UserID, companyID, config.ScheduledChange.ScheduleID, config.ScheduledChange.EffectiveFrom.Format(time.RFC3339))
+		handlers.RespondJSON(w, http.StatusAccepted, config)
+		return
+	}
+
+	h.logger.Info("POST /companies/{companyId}/loyalty-config - Loyalty configured successfully: user_id=%d, company_id=%d", actor.UserID, companyID)
 	handlers.RespondJSON(w, http.StatusOK, config)
 }
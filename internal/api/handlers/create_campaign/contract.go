@@ -0,0 +1,19 @@
+package create_campaign
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	CreateCampaign(ctx context.Context, companyID int64, actor models.Actor, req *models.CreateCampaignRequest) (*models.CampaignResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -8,7 +8,7 @@ import (
 
 // LoyaltyService интерфейс сервиса лояльности
 type LoyaltyService interface {
-	CreateCard(ctx context.Context, req *models.CreateLoyaltyCardRequest) (*models.LoyaltyCardResponse, error)
+	CreateCard(ctx context.Context, actor models.Actor, req *models.CreateLoyaltyCardRequest) (*models.LoyaltyCardResponse, error)
 }
 
 // Logger интерфейс для логирования
@@ -10,10 +10,15 @@ import (
 )
 
 const (
+	msgMissingUserID      = "отсутствует заголовок X-User-ID"
 	msgInvalidRequestBody = "некорректное тело запроса"
+	msgUserIDRequired     = "поле user_id обязательно для вызова по API-ключу"
 	msgConfigNotFound     = "программа лояльности не настроена для данной компании"
 	msgConfigDisabled     = "программа лояльности отключена для данной компании"
+	msgCompanyNotFound    = "компания не найдена"
 	msgCardAlreadyExists  = "карта лояльности уже существует"
+	msgCardBlocked        = "карта лояльности клиента приостановлена или выключена"
+	msgAccessDenied       = "доступ запрещён: выпустить карту другому клиенту может только менеджер компании"
 )
 
 type Handler struct {
@@ -29,8 +34,17 @@ func NewHandler(service LoyaltyService, logger Logger) *Handler {
 }
 
 // Handle POST /api/v1/loyalty-cards
+// user_id необязателен: по умолчанию карта выпускается текущему пользователю (обязателен для вызова по API-ключу)
 func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
-	// 1. Парсим request body
+	// 1. Извлекаем пользователя из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("POST /loyalty-cards - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим request body
 	var req models.CreateLoyaltyCardRequest
 	if err := handlers.DecodeJSON(r, &req); err != nil {
 		h.logger.Warn("POST /loyalty-cards - Invalid request body: %v", err)
@@ -38,9 +52,25 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. Вызываем сервис
-	card, err := h.service.CreateCard(r.Context(), &req)
+	if req.UserID == 0 {
+		req.UserID = actor.UserID
+	}
+
+	// У внутреннего сервиса нет собственного пользователя, клиента нужно указать явно
+	if req.UserID == 0 {
+		h.logger.Warn("POST /loyalty-cards - Missing user_id for service %s", actor.Service)
+		handlers.RespondBadRequest(w, msgUserIDRequired)
+		return
+	}
+
+	// 3. Вызываем сервис
+	card, err := h.service.CreateCard(r.Context(), actor, &req)
 	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("POST /loyalty-cards - Access denied: requester_id=%d, user_id=%d, company_id=%d", actor.UserID, req.UserID, req.CompanyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
 		if errors.Is(err, loyalty.ErrConfigNotFound) {
 			h.logger.Warn("POST /loyalty-cards - Config not found: company_id=%d", req.CompanyID)
 			handlers.RespondNotFound(w, msgConfigNotFound)
@@ -51,17 +81,32 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 			handlers.RespondNotFound(w, msgConfigDisabled)
 			return
 		}
+		if errors.Is(err, loyalty.ErrCompanyNotFound) {
+			h.logger.Warn("POST /loyalty-cards - Company not found in SellerService: company_id=%d", req.CompanyID)
+			handlers.RespondNotFound(w, msgCompanyNotFound)
+			return
+		}
 		if errors.Is(err, loyalty.ErrCardAlreadyExists) {
 			h.logger.Warn("POST /loyalty-cards - Card already exists: user_id=%d, company_id=%d", req.UserID, req.CompanyID)
 			handlers.RespondConflict(w, msgCardAlreadyExists)
 			return
 		}
+		if errors.Is(err, loyalty.ErrCardBlocked) {
+			h.logger.Warn("POST /loyalty-cards - Card is blocked: user_id=%d, company_id=%d", req.UserID, req.CompanyID)
+			handlers.RespondForbidden(w, msgCardBlocked)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("POST /loyalty-cards - SellerService error: company_id=%d, error=%v", req.CompanyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
 		h.logger.Error("POST /loyalty-cards - Failed to create card: user_id=%d, company_id=%d, error=%v", req.UserID, req.CompanyID, err)
 		handlers.RespondInternalError(w)
 		return
 	}
 
-	// 3. Возвращаем успешный ответ
+	// 4. Возвращаем успешный ответ
 	h.logger.Info("POST /loyalty-cards - Card created successfully: user_id=%d, company_id=%d, card_id=%d", req.UserID, req.CompanyID, card.CardID)
 	handlers.RespondJSON(w, http.StatusCreated, card)
 }
@@ -0,0 +1,19 @@
+package delete_campaign
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	DeleteCampaign(ctx context.Context, companyID, campaignID int64, actor models.Actor) (*models.CampaignResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -0,0 +1,92 @@
+package delete_campaign
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+)
+
+const (
+	msgMissingUserID     = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID  = "некорректный companyId"
+	msgInvalidCampaignID = "некорректный campaignId"
+	msgAccessDenied      = "доступ запрещён: пользователь не является менеджером компании"
+	msgCompanyNotFound   = "компания не найдена"
+	msgCampaignNotFound  = "акция не найдена"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle DELETE /api/v1/companies/{companyId}/campaigns/{campaignId}
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("DELETE /companies/{companyId}/campaigns/{campaignId} - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId и campaignId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("DELETE /companies/{companyId}/campaigns/{campaignId} - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	campaignID, err := strconv.ParseInt(vars["campaignId"], 10, 64)
+	if err != nil || campaignID <= 0 {
+		h.logger.Warn("DELETE /companies/{companyId}/campaigns/{campaignId} - Invalid campaignId: %s", vars["campaignId"])
+		handlers.RespondBadRequest(w, msgInvalidCampaignID)
+		return
+	}
+
+	// 3. Вызываем сервис
+	campaign, err := h.service.DeleteCampaign(r.Context(), companyID, campaignID, actor)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("DELETE /companies/{companyId}/campaigns/{campaignId} - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("DELETE /companies/{companyId}/campaigns/{campaignId} - Company not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgCompanyNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrCampaignNotFound) {
+			h.logger.Warn("DELETE /companies/{companyId}/campaigns/{campaignId} - Campaign not found: company_id=%d, campaign_id=%d", companyID, campaignID)
+			handlers.RespondNotFound(w, msgCampaignNotFound)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("DELETE /companies/{companyId}/campaigns/{campaignId} - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("DELETE /companies/{companyId}/campaigns/{campaignId} - Failed to delete campaign: user_id=%d, company_id=%d, campaign_id=%d, error=%v", actor.UserID, companyID, campaignID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 4. Возвращаем успешный ответ
+	h.logger.Info("DELETE /companies/{companyId}/campaigns/{campaignId} - Campaign deleted: user_id=%d, company_id=%d, campaign_id=%d", actor.UserID, companyID, campaignID)
+	handlers.RespondJSON(w, http.StatusOK, campaign)
+}
@@ -8,7 +8,7 @@ import (
 
 // LoyaltyService интерфейс сервиса лояльности
 type LoyaltyService interface {
-	GetCard(ctx context.Context, userID, companyID int64) (*models.LoyaltyCardResponse, error)
+	GetCard(ctx context.Context, actor models.Actor, userID, companyID int64) (*models.LoyaltyCardResponse, error)
 }
 
 // Logger интерфейс для логирования
@@ -10,11 +10,14 @@ import (
 )
 
 const (
-	msgInvalidUserID    = "некорректный или отсутствующий параметр userId"
+	msgMissingUserID    = "отсутствует заголовок X-User-ID"
+	msgInvalidUserID    = "некорректный параметр userId"
+	msgUserIDRequired   = "параметр userId обязателен для вызова по API-ключу"
 	msgInvalidCompanyID = "некорректный или отсутствующий параметр companyId"
 	msgCardNotFound     = "карта лояльности не найдена"
 	msgConfigNotFound   = "программа лояльности не настроена для данной компании"
 	msgConfigDisabled   = "программа лояльности отключена для данной компании"
+	msgAccessDenied     = "доступ запрещён: карта принадлежит другому клиенту"
 )
 
 type Handler struct {
@@ -30,27 +33,41 @@ func NewHandler(service LoyaltyService, logger Logger) *Handler {
 }
 
 // Handle GET /api/v1/loyalty-cards?userId={userId}&companyId={companyId}
+// userId необязателен: по умолчанию возвращается карта текущего пользователя (обязателен для вызова по API-ключу)
 func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
-	// 1. Парсим query параметры
-	userIDStr := r.URL.Query().Get("userId")
-	companyIDStr := r.URL.Query().Get("companyId")
-
-	if userIDStr == "" {
-		h.logger.Warn("GET /loyalty-cards - Missing userId parameter")
-		handlers.RespondBadRequest(w, msgInvalidUserID)
+	// 1. Извлекаем пользователя из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("GET /loyalty-cards - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
 		return
 	}
 
+	// 2. Парсим query параметры
+	userIDStr := r.URL.Query().Get("userId")
+	companyIDStr := r.URL.Query().Get("companyId")
+
 	if companyIDStr == "" {
 		h.logger.Warn("GET /loyalty-cards - Missing companyId parameter")
 		handlers.RespondBadRequest(w, msgInvalidCompanyID)
 		return
 	}
 
-	userID, err := strconv.ParseInt(userIDStr, 10, 64)
-	if err != nil {
-		h.logger.Warn("GET /loyalty-cards - Invalid userId: %v", err)
-		handlers.RespondBadRequest(w, msgInvalidUserID)
+	userID := actor.UserID
+	if userIDStr != "" {
+		var err error
+		userID, err = strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil {
+			h.logger.Warn("GET /loyalty-cards - Invalid userId: %v", err)
+			handlers.RespondBadRequest(w, msgInvalidUserID)
+			return
+		}
+	}
+
+	// У внутреннего сервиса нет собственного пользователя, клиента нужно указать явно
+	if userID == 0 {
+		h.logger.Warn("GET /loyalty-cards - Missing userId for service %s", actor.Service)
+		handlers.RespondBadRequest(w, msgUserIDRequired)
 		return
 	}
 
@@ -61,9 +78,14 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. Вызываем сервис
-	card, err := h.service.GetCard(r.Context(), userID, companyID)
+	// 3. Вызываем сервис
+	card, err := h.service.GetCard(r.Context(), actor, userID, companyID)
 	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("GET /loyalty-cards - Access denied: requester_id=%d, user_id=%d, company_id=%d", actor.UserID, userID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
 		if errors.Is(err, loyalty.ErrCardNotFound) {
 			h.logger.Warn("GET /loyalty-cards - Card not found: user_id=%d, company_id=%d", userID, companyID)
 			handlers.RespondNotFound(w, msgCardNotFound)
@@ -79,12 +101,17 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 			handlers.RespondForbidden(w, msgConfigDisabled)
 			return
 		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("GET /loyalty-cards - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
 		h.logger.Error("GET /loyalty-cards - Failed to get card: user_id=%d, company_id=%d, error=%v", userID, companyID, err)
 		handlers.RespondInternalError(w)
 		return
 	}
 
-	// 3. Возвращаем успешный ответ
+	// 4. Возвращаем успешный ответ
 	h.logger.Info("GET /loyalty-cards - Card retrieved successfully: user_id=%d, company_id=%d, card_id=%d", userID, companyID, card.CardID)
 	handlers.RespondJSON(w, http.StatusOK, card)
 }
@@ -0,0 +1,67 @@
+package get_loyalty_config
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgInvalidCompanyID = "некорректный companyId"
+	msgConfigNotFound   = "программа лояльности не настроена для этой компании"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle GET /api/v1/companies/{companyId}/loyalty-config
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем пользователя из контекста, если он передан (установлен middleware.OptionalAuth)
+	var actor *models.Actor
+	if a, ok := handlers.ActorFromContext(r.Context()); ok {
+		actor = &a
+	}
+
+	// 2. Парсим companyId из URL
+	vars := mux.Vars(r)
+	companyIDStr := vars["companyId"]
+
+	companyID, err := strconv.ParseInt(companyIDStr, 10, 64)
+	if err != nil {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-config - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	// 3. Вызываем сервис
+	config, err := h.service.GetLoyaltyConfig(r.Context(), companyID, actor)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Info("GET /companies/{companyId}/loyalty-config - Config not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+			return
+		}
+		h.logger.Error("GET /companies/{companyId}/loyalty-config - Failed to get config: company_id=%d, error=%v", companyID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 4. Возвращаем успешный ответ
+	h.logger.Info("GET /companies/{companyId}/loyalty-config - Config retrieved: company_id=%d, view=%s", companyID, config.View)
+	handlers.RespondJSON(w, http.StatusOK, config)
+}
@@ -0,0 +1,19 @@
+package list_audit_events
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	ListAuditEvents(ctx context.Context, companyID int64, actor models.Actor, req *models.ListAuditEventsRequest) (*models.AuditEventsResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
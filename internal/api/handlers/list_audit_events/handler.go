@@ -0,0 +1,127 @@
+package list_audit_events
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID    = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID = "некорректный companyId"
+	msgInvalidEntityID  = "некорректный параметр entity_id"
+	msgInvalidLimit     = "некорректный параметр limit"
+	msgInvalidInput     = "некорректные параметры запроса"
+	msgAccessDenied     = "доступ запрещён: пользователь не является менеджером компании"
+	msgConfigNotFound   = "программа лояльности не настроена для этой компании"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle GET /api/v1/companies/{companyId}/audit-events?entity_type=&entity_id=&cursor=&limit=
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("GET /companies/{companyId}/audit-events - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("GET /companies/{companyId}/audit-events - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	// 3. Парсим фильтры и пагинацию
+	req, msg := parseListRequest(r)
+	if msg != "" {
+		h.logger.Warn("GET /companies/{companyId}/audit-events - Invalid query: company_id=%d, error=%s", companyID, msg)
+		handlers.RespondBadRequest(w, msg)
+		return
+	}
+
+	// 4. Вызываем сервис
+	events, err := h.service.ListAuditEvents(r.Context(), companyID, actor, req)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("GET /companies/{companyId}/audit-events - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("GET /companies/{companyId}/audit-events - Config not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrInvalidInput) {
+			h.logger.Warn("GET /companies/{companyId}/audit-events - Invalid input: company_id=%d, error=%v", companyID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("GET /companies/{companyId}/audit-events - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("GET /companies/{companyId}/audit-events - Failed to list events: user_id=%d, company_id=%d, error=%v", actor.UserID, companyID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("GET /companies/{companyId}/audit-events - Events listed: company_id=%d, user_id=%d, count=%d", companyID, actor.UserID, len(events.Events))
+	handlers.RespondJSON(w, http.StatusOK, events)
+}
+
+// parseListRequest разбирает query параметры журнала изменений
+// Возвращает текст ошибки для клиента, если параметр некорректен
+func parseListRequest(r *http.Request) (*models.ListAuditEventsRequest, string) {
+	query := r.URL.Query()
+	req := &models.ListAuditEventsRequest{}
+
+	if entityType := query.Get("entity_type"); entityType != "" {
+		req.EntityType = &entityType
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		req.Cursor = &cursor
+	}
+
+	if value := query.Get("entity_id"); value != "" {
+		entityID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || entityID <= 0 {
+			return nil, msgInvalidEntityID
+		}
+		req.EntityID = &entityID
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, msgInvalidLimit
+		}
+		req.Limit = limit
+	}
+
+	return req, ""
+}
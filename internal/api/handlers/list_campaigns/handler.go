@@ -0,0 +1,92 @@
+package list_campaigns
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID          = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID       = "некорректный companyId"
+	msgInvalidIncludeFinished = "некорректный параметр include_finished"
+	msgAccessDenied           = "доступ запрещён: пользователь не является менеджером компании"
+	msgCompanyNotFound        = "компания не найдена"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle GET /api/v1/companies/{companyId}/campaigns?include_finished=
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("GET /companies/{companyId}/campaigns - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("GET /companies/{companyId}/campaigns - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	// 3. Парсим фильтр
+	req := &models.ListCampaignsRequest{}
+	if value := r.URL.Query().Get("include_finished"); value != "" {
+		includeFinished, err := strconv.ParseBool(value)
+		if err != nil {
+			h.logger.Warn("GET /companies/{companyId}/campaigns - Invalid include_finished: company_id=%d, value=%s", companyID, value)
+			handlers.RespondBadRequest(w, msgInvalidIncludeFinished)
+			return
+		}
+		req.IncludeFinished = includeFinished
+	}
+
+	// 4. Вызываем сервис
+	campaigns, err := h.service.ListCampaigns(r.Context(), companyID, actor, req)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("GET /companies/{companyId}/campaigns - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("GET /companies/{companyId}/campaigns - Company not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgCompanyNotFound)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("GET /companies/{companyId}/campaigns - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("GET /companies/{companyId}/campaigns - Failed to list campaigns: user_id=%d, company_id=%d, error=%v", actor.UserID, companyID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("GET /companies/{companyId}/campaigns - Campaigns listed: company_id=%d, user_id=%d, count=%d", companyID, actor.UserID, len(campaigns.Campaigns))
+	handlers.RespondJSON(w, http.StatusOK, campaigns)
+}
@@ -0,0 +1,20 @@
+package list_company_loyalty_cards
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	ListCompanyCards(ctx context.Context, companyID int64, actor models.Actor, req *models.ListCompanyCardsRequest) (*models.CompanyLoyaltyCardsResponse, error)
+	StreamCompanyCards(ctx context.Context, companyID int64, actor models.Actor, req *models.ListCompanyCardsRequest, fn func(card *models.LoyaltyCardResponse) error) error
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -0,0 +1,119 @@
+package list_company_loyalty_cards
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// csvFlushEvery через сколько строк буфер CSV отправляется клиенту
+const csvFlushEvery = 100
+
+// csvHeader заголовок выгрузки карт компании
+var csvHeader = []string{
+	"card_id", "user_id", "card_type", "status", "status_reason", "discount_percentage",
+	"visits_count", "points_balance", "expires_at", "created_at", "updated_at",
+}
+
+// csvCardWriter построчно пишет карты в ответ в формате CSV
+// Заголовки ответа отправляются при первой записи, поэтому до неё ещё можно ответить ошибкой
+type csvCardWriter struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	csv       *csv.Writer
+	companyID int64
+	started   bool
+	rows      int
+}
+
+func newCSVCardWriter(w http.ResponseWriter, companyID int64) *csvCardWriter {
+	return &csvCardWriter{
+		w:         w,
+		rc:        http.NewResponseController(w),
+		csv:       csv.NewWriter(w),
+		companyID: companyID,
+	}
+}
+
+// start отправляет заголовки ответа и строку заголовка CSV
+// Дедлайн записи сервера снимается: выгрузка большой компании может идти дольше write_timeout
+func (cw *csvCardWriter) start() error {
+	cw.started = true
+	_ = cw.rc.SetWriteDeadline(time.Time{})
+	cw.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	cw.w.Header().Set("Content-Disposition", "attachment; filename=\"company-"+strconv.FormatInt(cw.companyID, 10)+"-loyalty-cards.csv\"")
+	cw.w.WriteHeader(http.StatusOK)
+
+	return cw.csv.Write(csvHeader)
+}
+
+// Write записывает карту строкой CSV
+func (cw *csvCardWriter) Write(card *models.LoyaltyCardResponse) error {
+	if !cw.started {
+		if err := cw.start(); err != nil {
+			return err
+		}
+	}
+
+	statusReason := ""
+	if card.StatusReason != nil {
+		statusReason = *card.StatusReason
+	}
+
+	expiresAt := ""
+	if card.ExpiresAt != nil {
+		expiresAt = card.ExpiresAt.Format(time.RFC3339)
+	}
+
+	err := cw.csv.Write([]string{
+		strconv.FormatInt(card.CardID, 10),
+		strconv.FormatInt(card.UserID, 10),
+		card.CardType,
+		card.Status,
+		statusReason,
+		strconv.FormatFloat(card.DiscountPercentage, 'f', -1, 64),
+		strconv.Itoa(card.VisitsCount),
+		strconv.FormatInt(card.PointsBalance, 10),
+		expiresAt,
+		card.CreatedAt.Format(time.RFC3339),
+		card.UpdatedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.rows++
+	if cw.rows%csvFlushEvery == 0 {
+		return cw.flush()
+	}
+
+	return nil
+}
+
+// Close дописывает оставшиеся строки; для пустой выгрузки отправляет только заголовок CSV
+func (cw *csvCardWriter) Close() error {
+	if !cw.started {
+		if err := cw.start(); err != nil {
+			return err
+		}
+	}
+
+	return cw.flush()
+}
+
+func (cw *csvCardWriter) flush() error {
+	cw.csv.Flush()
+	if err := cw.csv.Error(); err != nil {
+		return err
+	}
+
+	if err := cw.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package list_company_loyalty_cards
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID      = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID   = "некорректный companyId"
+	msgInvalidLimit       = "некорректный параметр limit"
+	msgInvalidCreatedFrom = "некорректный параметр created_from (ожидается RFC 3339)"
+	msgInvalidCreatedTo   = "некорректный параметр created_to (ожидается RFC 3339)"
+	msgInvalidInput       = "некорректные параметры запроса"
+	msgAccessDenied       = "доступ запрещён: пользователь не является менеджером компании"
+	msgConfigNotFound     = "программа лояльности не настроена для этой компании"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle GET /api/v1/companies/{companyId}/loyalty-cards
+// С заголовком Accept: text/csv отдаёт все карты компании потоковой выгрузкой CSV
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-cards - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-cards - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	// 3. Парсим фильтры, сортировку и пагинацию
+	req, msg := parseListRequest(r)
+	if msg != "" {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-cards - Invalid query: company_id=%d, error=%s", companyID, msg)
+		handlers.RespondBadRequest(w, msg)
+		return
+	}
+
+	// 4. Выгрузка CSV
+	if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		h.handleCSV(w, r, companyID, actor, req)
+		return
+	}
+
+	// 5. Вызываем сервис
+	cards, err := h.service.ListCompanyCards(r.Context(), companyID, actor, req)
+	if err != nil {
+		h.respondServiceError(w, companyID, actor.UserID, err)
+		return
+	}
+
+	// 6. Возвращаем успешный ответ
+	h.logger.Info("GET /companies/{companyId}/loyalty-cards - Cards listed: company_id=%d, user_id=%d, count=%d", companyID, actor.UserID, len(cards.Cards))
+	handlers.RespondJSON(w, http.StatusOK, cards)
+}
+
+// handleCSV построчно выгружает карты компании в CSV
+func (h *Handler) handleCSV(w http.ResponseWriter, r *http.Request, companyID int64, actor models.Actor, req *models.ListCompanyCardsRequest) {
+	cw := newCSVCardWriter(w, companyID)
+
+	err := h.service.StreamCompanyCards(r.Context(), companyID, actor, req, cw.Write)
+	if err == nil {
+		err = cw.Close()
+	}
+	if err != nil {
+		// После начала выгрузки статус ответа уже отправлен - обрываем ответ и логируем ошибку
+		if cw.started {
+			h.logger.Error("GET /companies/{companyId}/loyalty-cards - CSV export interrupted: company_id=%d, rows=%d, error=%v", companyID, cw.rows, err)
+			return
+		}
+		h.respondServiceError(w, companyID, actor.UserID, err)
+		return
+	}
+
+	h.logger.Info("GET /companies/{companyId}/loyalty-cards - CSV exported: company_id=%d, user_id=%d, rows=%d", companyID, actor.UserID, cw.rows)
+}
+
+// respondServiceError отвечает ошибкой сервиса с подходящим HTTP статусом
+func (h *Handler) respondServiceError(w http.ResponseWriter, companyID, userID int64, err error) {
+	if errors.Is(err, loyalty.ErrAccessDenied) {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-cards - Access denied: user_id=%d, company_id=%d", userID, companyID)
+		handlers.RespondForbidden(w, msgAccessDenied)
+		return
+	}
+	if errors.Is(err, loyalty.ErrConfigNotFound) {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-cards - Config not found: company_id=%d", companyID)
+		handlers.RespondNotFound(w, msgConfigNotFound)
+		return
+	}
+	if errors.Is(err, loyalty.ErrInvalidInput) {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-cards - Invalid input: company_id=%d, error=%v", companyID, err)
+		handlers.RespondBadRequest(w, msgInvalidInput)
+		return
+	}
+	if handlers.IsSellerServiceError(err) {
+		h.logger.Error("GET /companies/{companyId}/loyalty-cards - SellerService error: company_id=%d, error=%v", companyID, err)
+		handlers.RespondSellerServiceError(w, err)
+		return
+	}
+	h.logger.Error("GET /companies/{companyId}/loyalty-cards - Failed to list cards: user_id=%d, company_id=%d, error=%v", userID, companyID, err)
+	handlers.RespondInternalError(w)
+}
+
+// parseListRequest разбирает query параметры списка карт
+// Возвращает текст ошибки для клиента, если параметр некорректен
+func parseListRequest(r *http.Request) (*models.ListCompanyCardsRequest, string) {
+	query := r.URL.Query()
+	req := &models.ListCompanyCardsRequest{
+		Sort:  query.Get("sort"),
+		Order: query.Get("order"),
+	}
+
+	if status := query.Get("status"); status != "" {
+		req.Status = &status
+	}
+	if cardType := query.Get("card_type"); cardType != "" {
+		req.CardType = &cardType
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		req.Cursor = &cursor
+	}
+
+	if value := query.Get("created_from"); value != "" {
+		createdFrom, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, msgInvalidCreatedFrom
+		}
+		req.CreatedFrom = &createdFrom
+	}
+	if value := query.Get("created_to"); value != "" {
+		createdTo, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, msgInvalidCreatedTo
+		}
+		req.CreatedTo = &createdTo
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, msgInvalidLimit
+		}
+		req.Limit = limit
+	}
+
+	return req, ""
+}
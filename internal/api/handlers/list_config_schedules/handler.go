@@ -0,0 +1,78 @@
+package list_config_schedules
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+)
+
+const (
+	msgMissingUserID    = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID = "некорректный companyId"
+	msgAccessDenied     = "доступ запрещён: пользователь не является менеджером компании"
+	msgCompanyNotFound  = "компания не найдена"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle GET /api/v1/companies/{companyId}/loyalty-config/schedules
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-config/schedules - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-config/schedules - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	// 3. Вызываем сервис
+	schedules, err := h.service.ListConfigSchedules(r.Context(), companyID, actor)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("GET /companies/{companyId}/loyalty-config/schedules - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("GET /companies/{companyId}/loyalty-config/schedules - Company not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgCompanyNotFound)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("GET /companies/{companyId}/loyalty-config/schedules - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("GET /companies/{companyId}/loyalty-config/schedules - Failed to list schedules: user_id=%d, company_id=%d, error=%v", actor.UserID, companyID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 4. Возвращаем успешный ответ
+	h.logger.Info("GET /companies/{companyId}/loyalty-config/schedules - Schedules listed: company_id=%d, user_id=%d, count=%d", companyID, actor.UserID, len(schedules.Schedules))
+	handlers.RespondJSON(w, http.StatusOK, schedules)
+}
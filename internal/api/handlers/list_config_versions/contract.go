@@ -0,0 +1,19 @@
+package list_config_versions
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	ListConfigVersions(ctx context.Context, companyID int64, actor models.Actor, req *models.ListConfigVersionsRequest) (*models.LoyaltyConfigVersionsResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -0,0 +1,102 @@
+package list_config_versions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID    = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID = "некорректный companyId"
+	msgInvalidLimit     = "некорректный параметр limit"
+	msgInvalidInput     = "некорректные параметры запроса"
+	msgAccessDenied     = "доступ запрещён: пользователь не является менеджером компании"
+	msgConfigNotFound   = "программа лояльности не настроена для этой компании"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle GET /api/v1/companies/{companyId}/loyalty-config/versions?cursor=&limit=
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-config/versions - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("GET /companies/{companyId}/loyalty-config/versions - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	// 3. Парсим пагинацию
+	query := r.URL.Query()
+	req := &models.ListConfigVersionsRequest{}
+	if cursor := query.Get("cursor"); cursor != "" {
+		req.Cursor = &cursor
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			h.logger.Warn("GET /companies/{companyId}/loyalty-config/versions - Invalid limit: company_id=%d, limit=%s", companyID, value)
+			handlers.RespondBadRequest(w, msgInvalidLimit)
+			return
+		}
+		req.Limit = limit
+	}
+
+	// 4. Вызываем сервис
+	versions, err := h.service.ListConfigVersions(r.Context(), companyID, actor, req)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("GET /companies/{companyId}/loyalty-config/versions - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("GET /companies/{companyId}/loyalty-config/versions - Config not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrInvalidInput) {
+			h.logger.Warn("GET /companies/{companyId}/loyalty-config/versions - Invalid input: company_id=%d, error=%v", companyID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("GET /companies/{companyId}/loyalty-config/versions - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("GET /companies/{companyId}/loyalty-config/versions - Failed to list versions: user_id=%d, company_id=%d, error=%v", actor.UserID, companyID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("GET /companies/{companyId}/loyalty-config/versions - Versions listed: company_id=%d, user_id=%d, count=%d", companyID, actor.UserID, len(versions.Versions))
+	handlers.RespondJSON(w, http.StatusOK, versions)
+}
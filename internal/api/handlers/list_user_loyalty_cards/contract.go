@@ -0,0 +1,19 @@
+package list_user_loyalty_cards
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	ListUserCards(ctx context.Context, userID int64, actor models.Actor, req *models.ListUserCardsRequest) (*models.UserLoyaltyCardsResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -0,0 +1,100 @@
+package list_user_loyalty_cards
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID = "отсутствует заголовок X-User-ID"
+	msgInvalidUserID = "некорректный userId"
+	msgInvalidLimit  = "некорректный параметр limit"
+	msgAccessDenied  = "доступ запрещён: можно просматривать только свои карты"
+	msgInvalidInput  = "некорректные параметры запроса"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle GET /api/v1/users/{userId}/loyalty-cards?status={status}&cursor={cursor}&limit={limit}
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("GET /users/{userId}/loyalty-cards - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим userId из URL
+	vars := mux.Vars(r)
+	userID, err := strconv.ParseInt(vars["userId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("GET /users/{userId}/loyalty-cards - Invalid userId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidUserID)
+		return
+	}
+
+	// 3. Парсим query параметры
+	query := r.URL.Query()
+	var req models.ListUserCardsRequest
+
+	if status := query.Get("status"); status != "" {
+		req.Status = &status
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		req.Cursor = &cursor
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			h.logger.Warn("GET /users/{userId}/loyalty-cards - Invalid limit: %s", limitStr)
+			handlers.RespondBadRequest(w, msgInvalidLimit)
+			return
+		}
+		req.Limit = limit
+	}
+
+	// 4. Вызываем сервис
+	cards, err := h.service.ListUserCards(r.Context(), userID, actor, &req)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("GET /users/{userId}/loyalty-cards - Access denied: user_id=%d, requester_id=%d", userID, actor.UserID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrInvalidInput) {
+			h.logger.Warn("GET /users/{userId}/loyalty-cards - Invalid input: user_id=%d, error=%v", userID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("GET /users/{userId}/loyalty-cards - SellerService error: user_id=%d, error=%v", userID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("GET /users/{userId}/loyalty-cards - Failed to list cards: user_id=%d, error=%v", userID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("GET /users/{userId}/loyalty-cards - Cards listed: user_id=%d, count=%d", userID, len(cards.Cards))
+	handlers.RespondJSON(w, http.StatusOK, cards)
+}
@@ -0,0 +1,100 @@
+package record_visit
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID      = "отсутствует заголовок X-User-ID"
+	msgInvalidCardID      = "некорректный cardId"
+	msgInvalidRequestBody = "некорректное тело запроса"
+	msgInvalidInput       = "некорректные входные данные"
+	msgAccessDenied       = "доступ запрещён: пользователь не является менеджером компании"
+	msgCardNotFound       = "карта лояльности не найдена"
+	msgConfigNotFound     = "программа лояльности не настроена для данной компании"
+	msgConfigDisabled     = "программа лояльности отключена для данной компании"
+	msgCardNotActive      = "карта лояльности не активна"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle POST /api/v1/loyalty-cards/{cardId}/visits
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим cardId из URL
+	cardID, err := strconv.ParseInt(mux.Vars(r)["cardId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Invalid cardId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCardID)
+		return
+	}
+
+	// 3. Парсим request body
+	var req models.RecordVisitRequest
+	if err := handlers.DecodeJSON(r, &req); err != nil {
+		h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Invalid request body: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidRequestBody)
+		return
+	}
+
+	// 4. Вызываем сервис
+	visit, err := h.service.RecordVisit(r.Context(), cardID, actor, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, loyalty.ErrInvalidInput):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Invalid input: card_id=%d, error=%v", cardID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+		case errors.Is(err, loyalty.ErrCardNotFound):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Card not found: card_id=%d", cardID)
+			handlers.RespondNotFound(w, msgCardNotFound)
+		case errors.Is(err, loyalty.ErrAccessDenied):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Access denied: user_id=%d, card_id=%d", actor.UserID, cardID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+		case errors.Is(err, loyalty.ErrConfigNotFound):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Config not found: card_id=%d", cardID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+		case errors.Is(err, loyalty.ErrConfigDisabled):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Config disabled: card_id=%d", cardID)
+			handlers.RespondForbidden(w, msgConfigDisabled)
+		case errors.Is(err, loyalty.ErrCardNotActive):
+			h.logger.Warn("POST /loyalty-cards/{cardId}/visits - Card not active: card_id=%d", cardID)
+			handlers.RespondConflict(w, msgCardNotActive)
+		case handlers.IsSellerServiceError(err):
+			h.logger.Error("POST /loyalty-cards/{cardId}/visits - SellerService error: card_id=%d, error=%v", cardID, err)
+			handlers.RespondSellerServiceError(w, err)
+		default:
+			h.logger.Error("POST /loyalty-cards/{cardId}/visits - Failed to record visit: user_id=%d, card_id=%d, error=%v", actor.UserID, cardID, err)
+			handlers.RespondInternalError(w)
+		}
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("POST /loyalty-cards/{cardId}/visits - Visit recorded: user_id=%d, card_id=%d, visit_number=%d", actor.UserID, cardID, visit.VisitNumber)
+	handlers.RespondJSON(w, http.StatusCreated, visit)
+}
@@ -0,0 +1,19 @@
+package redeem_points
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	RedeemPoints(ctx context.Context, cardID int64, actor models.Actor, req *models.RedeemPointsRequest) (*models.PointsTransactionResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -0,0 +1,98 @@
+package restore_config_version
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+)
+
+const (
+	msgMissingUserID       = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID    = "некорректный companyId"
+	msgInvalidVersion      = "некорректный номер версии"
+	msgAccessDenied        = "доступ запрещён: пользователь не является менеджером компании"
+	msgConfigNotFound      = "программа лояльности не настроена для этой компании"
+	msgVersionNotFound     = "версия конфигурации не найдена"
+	msgInvalidVersionInput = "версия конфигурации не проходит проверку и не может быть восстановлена"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle POST /api/v1/companies/{companyId}/loyalty-config/versions/{version}/restore
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId и номер версии из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	version, err := strconv.Atoi(vars["version"])
+	if err != nil || version <= 0 {
+		h.logger.Warn("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Invalid version: %s", vars["version"])
+		handlers.RespondBadRequest(w, msgInvalidVersion)
+		return
+	}
+
+	// 3. Вызываем сервис
+	config, err := h.service.RestoreConfigVersion(r.Context(), companyID, version, actor)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) {
+			h.logger.Warn("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Config not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigVersionNotFound) {
+			h.logger.Warn("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Version not found: company_id=%d, version=%d", companyID, version)
+			handlers.RespondNotFound(w, msgVersionNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrInvalidInput) {
+			h.logger.Warn("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Invalid version: company_id=%d, version=%d, error=%v", companyID, version, err)
+			handlers.RespondBadRequest(w, msgInvalidVersionInput)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Failed to restore version: user_id=%d, company_id=%d, version=%d, error=%v", actor.UserID, companyID, version, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 4. Возвращаем успешный ответ
+	h.logger.Info("POST /companies/{companyId}/loyalty-config/versions/{version}/restore - Config restored: user_id=%d, company_id=%d, version=%d", actor.UserID, companyID, version)
+	handlers.RespondJSON(w, http.StatusOK, config)
+}
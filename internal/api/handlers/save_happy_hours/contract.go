@@ -0,0 +1,19 @@
+package save_happy_hours
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	SaveHappyHours(ctx context.Context, companyID int64, actor models.Actor, req *models.SaveHappyHoursRequest) (*models.HappyHoursResponse, error)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
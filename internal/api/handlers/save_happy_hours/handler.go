@@ -0,0 +1,94 @@
+package save_happy_hours
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID      = "отсутствует заголовок X-User-ID"
+	msgInvalidCompanyID   = "некорректный companyId"
+	msgInvalidRequestBody = "некорректное тело запроса"
+	msgAccessDenied       = "доступ запрещён: пользователь не является менеджером компании"
+	msgCompanyNotFound    = "компания не найдена"
+	msgInvalidInput       = "некорректные «счастливые часы»: интервалы должны приходиться на часы работы компании"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle PUT /api/v1/companies/{companyId}/happy-hours
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("PUT /companies/{companyId}/happy-hours - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим companyId из URL
+	vars := mux.Vars(r)
+	companyID, err := strconv.ParseInt(vars["companyId"], 10, 64)
+	if err != nil {
+		h.logger.Warn("PUT /companies/{companyId}/happy-hours - Invalid companyId: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidCompanyID)
+		return
+	}
+
+	// 3. Парсим request body
+	var req models.SaveHappyHoursRequest
+	if err := handlers.DecodeJSON(r, &req); err != nil {
+		h.logger.Warn("PUT /companies/{companyId}/happy-hours - Invalid request body: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidRequestBody)
+		return
+	}
+
+	// 4. Вызываем сервис
+	happyHours, err := h.service.SaveHappyHours(r.Context(), companyID, actor, &req)
+	if err != nil {
+		if errors.Is(err, loyalty.ErrAccessDenied) {
+			h.logger.Warn("PUT /companies/{companyId}/happy-hours - Access denied: user_id=%d, company_id=%d", actor.UserID, companyID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+			return
+		}
+		if errors.Is(err, loyalty.ErrConfigNotFound) || errors.Is(err, loyalty.ErrCompanyNotFound) {
+			h.logger.Warn("PUT /companies/{companyId}/happy-hours - Company not found: company_id=%d", companyID)
+			handlers.RespondNotFound(w, msgCompanyNotFound)
+			return
+		}
+		if errors.Is(err, loyalty.ErrInvalidInput) {
+			h.logger.Warn("PUT /companies/{companyId}/happy-hours - Invalid input: company_id=%d, error=%v", companyID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+			return
+		}
+		if handlers.IsSellerServiceError(err) {
+			h.logger.Error("PUT /companies/{companyId}/happy-hours - SellerService error: company_id=%d, error=%v", companyID, err)
+			handlers.RespondSellerServiceError(w, err)
+			return
+		}
+		h.logger.Error("PUT /companies/{companyId}/happy-hours - Failed to save happy hours: user_id=%d, company_id=%d, error=%v", actor.UserID, companyID, err)
+		handlers.RespondInternalError(w)
+		return
+	}
+
+	// 5. Возвращаем успешный ответ
+	h.logger.Info("PUT /companies/{companyId}/happy-hours - Happy hours saved: user_id=%d, company_id=%d, windows=%d", actor.UserID, companyID, len(happyHours.Windows))
+	handlers.RespondJSON(w, http.StatusOK, happyHours)
+}
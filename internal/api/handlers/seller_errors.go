@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+)
+
+const (
+	msgSellerServiceTimeout     = "сервис компаний не ответил вовремя, повторите запрос позже"
+	msgSellerServiceUnavailable = "сервис компаний временно недоступен, повторите запрос позже"
+	msgSellerServiceBadResponse = "сервис компаний вернул некорректный ответ"
+)
+
+// sellerServiceRetryAfter рекомендуемая пауза перед повтором запроса при сбое SellerService (секунды)
+const sellerServiceRetryAfter = 5
+
+// IsSellerServiceError сообщает, что запрос не выполнен из-за сбоя SellerService
+func IsSellerServiceError(err error) bool {
+	return errors.Is(err, loyalty.ErrSellerServiceTimeout) ||
+		errors.Is(err, loyalty.ErrSellerServiceUnavailable) ||
+		errors.Is(err, loyalty.ErrSellerServiceBadResponse)
+}
+
+// RespondSellerServiceError отвечает на сбой SellerService: 504 - таймаут, 503 - сервис недоступен,
+// 502 - некорректный ответ
+func RespondSellerServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, loyalty.ErrSellerServiceTimeout):
+		RespondUnavailable(w, http.StatusGatewayTimeout, msgSellerServiceTimeout, sellerServiceRetryAfter)
+	case errors.Is(err, loyalty.ErrSellerServiceBadResponse):
+		RespondUnavailable(w, http.StatusBadGateway, msgSellerServiceBadResponse, sellerServiceRetryAfter)
+	default:
+		RespondUnavailable(w, http.StatusServiceUnavailable, msgSellerServiceUnavailable, sellerServiceRetryAfter)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 )
 
 // ErrorResponse структура для ответа с ошибкой
@@ -62,3 +63,9 @@ func RespondConflict(w http.ResponseWriter, message string) {
 func RespondInternalError(w http.ResponseWriter) {
 	RespondError(w, http.StatusInternalServerError, "internal server error")
 }
+
+// RespondUnavailable отправляет ошибку 502, 503 или 504 с заголовком Retry-After (в секундах)
+func RespondUnavailable(w http.ResponseWriter, status int, message string, retryAfter int) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	RespondError(w, status, message)
+}
@@ -0,0 +1,88 @@
+package verify_loyalty_card
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/api/handlers"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	msgMissingUserID      = "отсутствует заголовок X-User-ID"
+	msgInvalidRequestBody = "некорректное тело запроса"
+	msgInvalidInput       = "некорректные входные данные"
+	msgInvalidQRToken     = "недействительный QR-код карты лояльности"
+	msgQRTokenExpired     = "срок действия QR-кода истёк, клиенту нужно обновить карту"
+	msgAccessDenied       = "доступ запрещён: пользователь не является менеджером компании"
+	msgCardNotFound       = "карта лояльности не найдена"
+	msgConfigNotFound     = "программа лояльности не настроена для данной компании"
+)
+
+type Handler struct {
+	service LoyaltyService
+	logger  Logger
+}
+
+func NewHandler(service LoyaltyService, logger Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handle POST /api/v1/loyalty-cards/verify
+func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	// 1. Извлекаем user ID из контекста (установлен middleware.Auth)
+	actor, ok := handlers.ActorFromContext(r.Context())
+	if !ok {
+		h.logger.Warn("POST /loyalty-cards/verify - Missing user ID in context")
+		handlers.RespondUnauthorized(w, msgMissingUserID)
+		return
+	}
+
+	// 2. Парсим request body
+	var req models.VerifyCardRequest
+	if err := handlers.DecodeJSON(r, &req); err != nil {
+		h.logger.Warn("POST /loyalty-cards/verify - Invalid request body: %v", err)
+		handlers.RespondBadRequest(w, msgInvalidRequestBody)
+		return
+	}
+
+	// 3. Вызываем сервис
+	result, err := h.service.VerifyCard(r.Context(), actor, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, loyalty.ErrInvalidInput):
+			h.logger.Warn("POST /loyalty-cards/verify - Invalid input: user_id=%d, error=%v", actor.UserID, err)
+			handlers.RespondBadRequest(w, msgInvalidInput)
+		case errors.Is(err, loyalty.ErrInvalidQRToken):
+			h.logger.Warn("POST /loyalty-cards/verify - Invalid QR token: user_id=%d, error=%v", actor.UserID, err)
+			handlers.RespondBadRequest(w, msgInvalidQRToken)
+		case errors.Is(err, loyalty.ErrQRTokenExpired):
+			h.logger.Warn("POST /loyalty-cards/verify - QR token expired: user_id=%d", actor.UserID)
+			handlers.RespondError(w, http.StatusGone, msgQRTokenExpired)
+		case errors.Is(err, loyalty.ErrCardNotFound):
+			h.logger.Warn("POST /loyalty-cards/verify - Card not found: user_id=%d", actor.UserID)
+			handlers.RespondNotFound(w, msgCardNotFound)
+		case errors.Is(err, loyalty.ErrAccessDenied):
+			h.logger.Warn("POST /loyalty-cards/verify - Access denied: user_id=%d", actor.UserID)
+			handlers.RespondForbidden(w, msgAccessDenied)
+		case errors.Is(err, loyalty.ErrConfigNotFound):
+			h.logger.Warn("POST /loyalty-cards/verify - Config not found: user_id=%d", actor.UserID)
+			handlers.RespondNotFound(w, msgConfigNotFound)
+		case handlers.IsSellerServiceError(err):
+			h.logger.Error("POST /loyalty-cards/verify - SellerService error: user_id=%d, error=%v", actor.UserID, err)
+			handlers.RespondSellerServiceError(w, err)
+		default:
+			h.logger.Error("POST /loyalty-cards/verify - Failed to verify card: user_id=%d, error=%v", actor.UserID, err)
+			handlers.RespondInternalError(w)
+		}
+		return
+	}
+
+	// 4. Возвращаем успешный ответ
+	h.logger.Info("POST /loyalty-cards/verify - Card verified: user_id=%d, card_id=%d, discount_applicable=%t", actor.UserID, result.Card.CardID, result.DiscountApplicable)
+	handlers.RespondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/apikey"
+)
+
+// APIKeyHeader заголовок с API-ключом внутреннего сервиса
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyContextKey ключ контекста с API-ключом, по которому аутентифицирован сервис
+const APIKeyContextKey contextKey = "api_key"
+
+// APIKeyAuthenticator проверяет API-ключ и возвращает его данные
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error)
+}
+
+// APIKeyAuth аутентифицирует внутренний сервис по заголовку X-API-Key и сохраняет ключ в контекст
+// Права ключа на конкретный endpoint проверяет RequireScope
+func APIKeyAuth(authenticator APIKeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rawKey := r.Header.Get(APIKeyHeader)
+			if rawKey == "" {
+				http.Error(w, "missing X-API-Key header", http.StatusUnauthorized)
+				return
+			}
+
+			key, err := authenticator.Authenticate(r.Context(), rawKey)
+			if err != nil {
+				if errors.Is(err, apikey.ErrInvalidKey) {
+					http.Error(w, "invalid API key", http.StatusUnauthorized)
+					return
+				}
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), APIKeyContextKey, key)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope пропускает запрос, только если API-ключу из контекста выдано право scope
+func RequireScope(scope domain.APIKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := GetAPIKey(r.Context())
+			if !ok {
+				http.Error(w, "missing X-API-Key header", http.StatusUnauthorized)
+				return
+			}
+
+			if !key.HasScope(scope) {
+				http.Error(w, "API key has no "+string(scope)+" scope", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetAPIKey извлекает API-ключ внутреннего сервиса из контекста
+func GetAPIKey(ctx context.Context) (*domain.APIKey, bool) {
+	key, ok := ctx.Value(APIKeyContextKey).(*domain.APIKey)
+	return key, ok
+}
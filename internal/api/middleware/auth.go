@@ -42,6 +42,19 @@ func Auth(next http.Handler) http.Handler {
 	})
 }
 
+// OptionalAuth сохраняет X-User-ID в контекст, если заголовок передан
+// Запросы без заголовка пропускаются как анонимные, некорректный X-User-ID отклоняется
+func OptionalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-ID") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		Auth(next).ServeHTTP(w, r)
+	})
+}
+
 // GetUserID извлекает user ID из контекста
 func GetUserID(ctx context.Context) (int64, bool) {
 	userID, ok := ctx.Value(UserIDKey).(int64)
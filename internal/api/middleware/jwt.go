@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/jwtauth"
+)
+
+const bearerRealm = "loyaltysystemservice"
+
+// TokenVerifier проверяет bearer-токен и возвращает данные пользователя
+type TokenVerifier interface {
+	Verify(token string) (*jwtauth.Claims, error)
+}
+
+// JWTAuth проверяет токен из заголовка Authorization: Bearer и сохраняет пользователя и роль в контекст
+// Используются те же ключи контекста, что и в Auth, поэтому обработчики не зависят от режима аутентификации
+func JWTAuth(verifier TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				respondBearerUnauthorized(w, "", "missing bearer token")
+				return
+			}
+
+			claims, err := verifier.Verify(token)
+			if err != nil {
+				respondBearerUnauthorized(w, "invalid_token", tokenErrorDescription(err))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			if claims.Role != "" {
+				ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalJWTAuth проверяет токен, если заголовок Authorization передан
+// Запросы без заголовка пропускаются как анонимные, некорректный токен отклоняется
+func OptionalJWTAuth(verifier TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		auth := JWTAuth(verifier)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			auth.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bearerToken извлекает токен из заголовка Authorization (схема Bearer нечувствительна к регистру)
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// tokenErrorDescription формирует описание ошибки без подробностей о ключах и подписи
+func tokenErrorDescription(err error) string {
+	switch {
+	case errors.Is(err, jwtauth.ErrTokenExpired):
+		return "token expired"
+	case errors.Is(err, jwtauth.ErrTokenNotYetValid):
+		return "token not yet valid"
+	case errors.Is(err, jwtauth.ErrInvalidClaims):
+		return "invalid token claims"
+	default:
+		return "invalid token"
+	}
+}
+
+// respondBearerUnauthorized отвечает 401 с заголовком WWW-Authenticate по RFC 6750
+func respondBearerUnauthorized(w http.ResponseWriter, errorCode, description string) {
+	challenge := fmt.Sprintf(`Bearer realm=%q`, bearerRealm)
+	if errorCode != "" {
+		challenge += fmt.Sprintf(`, error=%q, error_description=%q`, errorCode, description)
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, description, http.StatusUnauthorized)
+}
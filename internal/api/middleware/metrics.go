@@ -56,6 +56,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap возвращает исходный ResponseWriter для http.ResponseController (Flush, дедлайны записи)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // categorizeError категоризирует ошибки по типам
 func categorizeError(statusCode int) string {
 	switch {
@@ -4,17 +4,23 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
 // Config представляет полную конфигурацию приложения
 type Config struct {
-	Logs          LogsConfig         `toml:"logs"`
-	Server        ServerConfig       `toml:"server"`
-	Database      DatabaseConfig     `toml:"database"`
-	Metrics       MetricsConfig      `toml:"metrics"`
-	SellerService IntegrationConfig  `toml:"sellerservice"`
+	Logs                  LogsConfig                  `toml:"logs"`
+	Server                ServerConfig                `toml:"server"`
+	Database              DatabaseConfig              `toml:"database"`
+	Metrics               MetricsConfig               `toml:"metrics"`
+	SellerService         IntegrationConfig           `toml:"sellerservice"`
+	QR                    QRConfig                    `toml:"qr"`
+	CardExpiry            CardExpiryConfig            `toml:"card_expiry"`
+	CompanyReconciliation CompanyReconciliationConfig `toml:"company_reconciliation"`
+	ConfigActivator       ConfigActivatorConfig       `toml:"config_activator"`
+	Auth                  AuthConfig                  `toml:"auth"`
 }
 
 // LogsConfig содержит настройки логирования
@@ -55,7 +61,73 @@ type MetricsConfig struct {
 // IntegrationConfig содержит настройки интеграции с внешним сервисом
 type IntegrationConfig struct {
 	BaseURL string `toml:"base_url"`
-	Timeout int    `toml:"timeout"`
+	Timeout int    `toml:"timeout"` // Таймаут одной попытки в секундах
+
+	MaxRetries     int `toml:"max_retries"`      // Повторы после первой попытки (0 - без повторов)
+	RetryBaseDelay int `toml:"retry_base_delay"` // Начальная задержка между попытками в миллисекундах
+	RetryMaxDelay  int `toml:"retry_max_delay"`  // Максимальная задержка между попытками в миллисекундах
+
+	BreakerFailureThreshold int `toml:"breaker_failure_threshold"` // Сбоев подряд до размыкания выключателя
+	BreakerOpenTimeout      int `toml:"breaker_open_timeout"`      // Время до пробного запроса в секундах
+
+	CacheEnabled     bool `toml:"cache_enabled"`      // Кэширование данных компаний
+	CacheTTL         int  `toml:"cache_ttl"`          // Время жизни компании в кэше в секундах
+	CacheNegativeTTL int  `toml:"cache_negative_ttl"` // Время жизни ответа "компания не найдена" в секундах (0 - не кэшировать)
+	CacheMaxEntries  int  `toml:"cache_max_entries"`  // Максимальное количество компаний в кэше
+
+	// Timezone часовой пояс IANA, в котором сервис задаёт часы работы компаний (в ответах пояс не передаётся)
+	Timezone string `toml:"timezone"`
+}
+
+// QRConfig содержит настройки подписи QR-токенов карт лояльности
+type QRConfig struct {
+	SecretKey string `toml:"secret_key"`
+	TTL       int    `toml:"ttl"` // Время жизни токена в секундах
+}
+
+// CardExpiryConfig содержит настройки фонового процесса истечения карт
+type CardExpiryConfig struct {
+	Enabled   bool `toml:"enabled"`
+	Interval  int  `toml:"interval"`   // Интервал между проходами в секундах
+	BatchSize int  `toml:"batch_size"` // Количество карт, обрабатываемых одним запросом
+}
+
+// CompanyReconciliationConfig содержит настройки фонового процесса сверки компаний с SellerService
+type CompanyReconciliationConfig struct {
+	Enabled   bool `toml:"enabled"`
+	Interval  int  `toml:"interval"`   // Интервал между проходами в секундах
+	BatchSize int  `toml:"batch_size"` // Количество компаний, читаемых из БД одним запросом
+}
+
+// ConfigActivatorConfig содержит настройки фонового процесса переноса запланированных изменений программ лояльности
+type ConfigActivatorConfig struct {
+	Enabled  bool `toml:"enabled"`
+	Interval int  `toml:"interval"` // Интервал между проходами в секундах
+}
+
+const (
+	// AuthModeHeader пользователь передаётся заголовками X-User-ID и X-User-Role (за API Gateway)
+	AuthModeHeader = "header"
+	// AuthModeJWT пользователь и роль извлекаются из подписанного JWT в заголовке Authorization
+	AuthModeJWT = "jwt"
+)
+
+// AuthConfig содержит настройки аутентификации входящих запросов
+type AuthConfig struct {
+	Mode string    `toml:"mode"` // header (по умолчанию) или jwt
+	JWT  JWTConfig `toml:"jwt"`
+}
+
+// JWTConfig содержит настройки проверки JWT
+type JWTConfig struct {
+	Algorithm   string `toml:"algorithm"`     // HS256 или RS256
+	Secret      string `toml:"secret"`        // Общий секрет для HS256
+	JWKSFile    string `toml:"jwks_file"`     // Путь к JWKS с публичными ключами для RS256
+	Issuer      string `toml:"issuer"`        // Ожидаемый iss (пусто - не проверяется)
+	Audience    string `toml:"audience"`      // Ожидаемый aud (пусто - не проверяется)
+	UserIDClaim string `toml:"user_id_claim"` // Claim с ID пользователя
+	RoleClaim   string `toml:"role_claim"`    // Claim с ролью пользователя
+	Leeway      int    `toml:"leeway"`        // Допуск на рассинхронизацию часов в секундах
 }
 
 // DSN формирует строку подключения к PostgreSQL
@@ -147,6 +219,66 @@ func overrideFromEnv(cfg *Config) {
 			cfg.SellerService.Timeout = timeout
 		}
 	}
+	if v := os.Getenv("SELLERSERVICE_MAX_RETRIES"); v != "" {
+		if retries, err := strconv.Atoi(v); err == nil {
+			cfg.SellerService.MaxRetries = retries
+		}
+	}
+	if v := os.Getenv("SELLERSERVICE_CACHE_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.SellerService.CacheEnabled = enabled
+		}
+	}
+	if v := os.Getenv("SELLERSERVICE_CACHE_TTL"); v != "" {
+		if ttl, err := strconv.Atoi(v); err == nil {
+			cfg.SellerService.CacheTTL = ttl
+		}
+	}
+	if v := os.Getenv("SELLERSERVICE_TIMEZONE"); v != "" {
+		cfg.SellerService.Timezone = v
+	}
+
+	// QR tokens
+	if v := os.Getenv("QR_SECRET_KEY"); v != "" {
+		cfg.QR.SecretKey = v
+	}
+	if v := os.Getenv("QR_TTL"); v != "" {
+		if ttl, err := strconv.Atoi(v); err == nil {
+			cfg.QR.TTL = ttl
+		}
+	}
+
+	// Card expiry worker
+	if v := os.Getenv("CARD_EXPIRY_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.CardExpiry.Enabled = enabled
+		}
+	}
+
+	// Company reconciliation worker
+	if v := os.Getenv("COMPANY_RECONCILIATION_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.CompanyReconciliation.Enabled = enabled
+		}
+	}
+
+	// Scheduled config activation worker
+	if v := os.Getenv("CONFIG_ACTIVATOR_ENABLED"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			cfg.ConfigActivator.Enabled = enabled
+		}
+	}
+
+	// Auth
+	if v := os.Getenv("AUTH_MODE"); v != "" {
+		cfg.Auth.Mode = v
+	}
+	if v := os.Getenv("AUTH_JWT_SECRET"); v != "" {
+		cfg.Auth.JWT.Secret = v
+	}
+	if v := os.Getenv("AUTH_JWT_JWKS_FILE"); v != "" {
+		cfg.Auth.JWT.JWKSFile = v
+	}
 }
 
 // validate проверяет корректность конфигурации
@@ -218,6 +350,136 @@ func validate(cfg *Config) error {
 	if cfg.SellerService.Timeout == 0 {
 		cfg.SellerService.Timeout = 10 // default 10 seconds
 	}
+	if cfg.SellerService.MaxRetries < 0 || cfg.SellerService.RetryBaseDelay < 0 || cfg.SellerService.RetryMaxDelay < 0 {
+		return fmt.Errorf("sellerservice retry settings must not be negative")
+	}
+	if cfg.SellerService.RetryBaseDelay == 0 {
+		cfg.SellerService.RetryBaseDelay = 100 // default 100 ms
+	}
+	if cfg.SellerService.RetryMaxDelay == 0 {
+		cfg.SellerService.RetryMaxDelay = 2000 // default 2 seconds
+	}
+	if cfg.SellerService.RetryMaxDelay < cfg.SellerService.RetryBaseDelay {
+		return fmt.Errorf("sellerservice retry_max_delay must not be less than retry_base_delay")
+	}
+	if cfg.SellerService.BreakerFailureThreshold < 0 || cfg.SellerService.BreakerOpenTimeout < 0 {
+		return fmt.Errorf("sellerservice circuit breaker settings must not be negative")
+	}
+	if cfg.SellerService.BreakerFailureThreshold == 0 {
+		cfg.SellerService.BreakerFailureThreshold = 5
+	}
+	if cfg.SellerService.BreakerOpenTimeout == 0 {
+		cfg.SellerService.BreakerOpenTimeout = 30 // default 30 seconds
+	}
+	if cfg.SellerService.CacheTTL < 0 || cfg.SellerService.CacheNegativeTTL < 0 || cfg.SellerService.CacheMaxEntries < 0 {
+		return fmt.Errorf("sellerservice cache settings must not be negative")
+	}
+	if cfg.SellerService.CacheTTL == 0 {
+		cfg.SellerService.CacheTTL = 60 // default 1 minute
+	}
+	if cfg.SellerService.CacheMaxEntries == 0 {
+		cfg.SellerService.CacheMaxEntries = 1000
+	}
+	if cfg.SellerService.Timezone == "" {
+		cfg.SellerService.Timezone = "Europe/Moscow"
+	}
+	if _, err := time.LoadLocation(cfg.SellerService.Timezone); err != nil {
+		return fmt.Errorf("sellerservice timezone %q is unknown: %v", cfg.SellerService.Timezone, err)
+	}
+
+	// QR tokens validation
+	if cfg.QR.SecretKey == "" {
+		return fmt.Errorf("qr secret_key is required (set QR_SECRET_KEY)")
+	}
+	if len(cfg.QR.SecretKey) < 32 {
+		return fmt.Errorf("qr secret_key must be at least 32 characters")
+	}
+	if cfg.QR.TTL < 0 {
+		return fmt.Errorf("qr ttl must not be negative")
+	}
+	if cfg.QR.TTL == 0 {
+		cfg.QR.TTL = 300 // default 5 minutes
+	}
+
+	// Card expiry worker defaults
+	if cfg.CardExpiry.Interval < 0 || cfg.CardExpiry.BatchSize < 0 {
+		return fmt.Errorf("card_expiry interval and batch_size must not be negative")
+	}
+	if cfg.CardExpiry.Interval == 0 {
+		cfg.CardExpiry.Interval = 60 // default 1 minute
+	}
+	if cfg.CardExpiry.BatchSize == 0 {
+		cfg.CardExpiry.BatchSize = 500
+	}
+
+	// Company reconciliation worker defaults
+	if cfg.CompanyReconciliation.Interval < 0 || cfg.CompanyReconciliation.BatchSize < 0 {
+		return fmt.Errorf("company_reconciliation interval and batch_size must not be negative")
+	}
+	if cfg.CompanyReconciliation.Interval == 0 {
+		cfg.CompanyReconciliation.Interval = 3600 // default 1 hour
+	}
+	if cfg.CompanyReconciliation.BatchSize == 0 {
+		cfg.CompanyReconciliation.BatchSize = 100
+	}
+
+	// Scheduled config activation worker defaults
+	if cfg.ConfigActivator.Interval < 0 {
+		return fmt.Errorf("config_activator interval must not be negative")
+	}
+	if cfg.ConfigActivator.Interval == 0 {
+		cfg.ConfigActivator.Interval = 60 // default 1 minute
+	}
+
+	// Auth validation
+	if err := validateAuth(&cfg.Auth); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateAuth проверяет настройки аутентификации и проставляет значения по умолчанию
+func validateAuth(auth *AuthConfig) error {
+	if auth.Mode == "" {
+		auth.Mode = AuthModeHeader // default
+	}
+
+	switch auth.Mode {
+	case AuthModeHeader:
+		return nil
+	case AuthModeJWT:
+	default:
+		return fmt.Errorf("auth mode must be %q or %q", AuthModeHeader, AuthModeJWT)
+	}
+
+	jwt := &auth.JWT
+	if jwt.Algorithm == "" {
+		jwt.Algorithm = "HS256" // default
+	}
+
+	switch jwt.Algorithm {
+	case "HS256":
+		if len(jwt.Secret) < 32 {
+			return fmt.Errorf("auth jwt secret must be at least 32 characters for HS256")
+		}
+	case "RS256":
+		if jwt.JWKSFile == "" {
+			return fmt.Errorf("auth jwt jwks_file is required for RS256")
+		}
+	default:
+		return fmt.Errorf("auth jwt algorithm must be HS256 or RS256")
+	}
+
+	if jwt.Leeway < 0 {
+		return fmt.Errorf("auth jwt leeway must not be negative")
+	}
+	if jwt.UserIDClaim == "" {
+		jwt.UserIDClaim = "sub"
+	}
+	if jwt.RoleClaim == "" {
+		jwt.RoleClaim = "role"
+	}
 
 	return nil
 }
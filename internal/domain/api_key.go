@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// APIKeyScope право внутреннего сервиса, выданное API-ключу
+type APIKeyScope string
+
+const (
+	// APIKeyScopeCardsRead чтение и проверка карт клиентов
+	APIKeyScopeCardsRead APIKeyScope = "cards:read"
+	// APIKeyScopeCardsWrite выпуск карт клиентам
+	APIKeyScopeCardsWrite APIKeyScope = "cards:write"
+	// APIKeyScopePointsWrite начисление и списание баллов
+	APIKeyScopePointsWrite APIKeyScope = "points:write"
+	// APIKeyScopeVisitsWrite запись визитов
+	APIKeyScopeVisitsWrite APIKeyScope = "visits:write"
+)
+
+// APIKeyScopes все поддерживаемые права API-ключей
+var APIKeyScopes = []APIKeyScope{
+	APIKeyScopeCardsRead,
+	APIKeyScopeCardsWrite,
+	APIKeyScopePointsWrite,
+	APIKeyScopeVisitsWrite,
+}
+
+// APIKey API-ключ внутреннего сервиса (без открытого значения и хеша)
+type APIKey struct {
+	ID        int64
+	Name      string // Имя сервиса-владельца ключа
+	Prefix    string // Начало ключа для поиска при отзыве и в логах
+	Scopes    []APIKeyScope
+	CreatedAt time.Time
+	RevokedAt *time.Time // nil - ключ действует
+}
+
+// CreateAPIKeyInput входные данные для сохранения выпущенного ключа
+type CreateAPIKeyInput struct {
+	Name   string
+	Prefix string
+	Hash   string
+	Scopes []APIKeyScope
+}
+
+// HasScope сообщает, выдано ли ключу право scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntityType тип сущности, изменение которой записано в журнал
+type AuditEntityType string
+
+const (
+	// AuditEntityLoyaltyConfig конфигурация программы лояльности компании
+	AuditEntityLoyaltyConfig AuditEntityType = "loyalty_config"
+	// AuditEntityLoyaltyCard карта лояльности клиента
+	AuditEntityLoyaltyCard AuditEntityType = "loyalty_card"
+	// AuditEntityLoyaltyCampaign промо-акция компании
+	AuditEntityLoyaltyCampaign AuditEntityType = "loyalty_campaign"
+	// AuditEntityHappyHours «счастливые часы» компании
+	AuditEntityHappyHours AuditEntityType = "happy_hours"
+)
+
+// IsValid проверяет, что тип сущности поддерживается
+func (t AuditEntityType) IsValid() bool {
+	switch t {
+	case AuditEntityLoyaltyConfig, AuditEntityLoyaltyCard, AuditEntityLoyaltyCampaign, AuditEntityHappyHours:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuditAction действие, записанное в журнал
+type AuditAction string
+
+const (
+	// AuditActionConfigCreated программа лояльности настроена впервые
+	AuditActionConfigCreated AuditAction = "config_created"
+	// AuditActionConfigUpdated параметры программы лояльности изменены
+	AuditActionConfigUpdated AuditAction = "config_updated"
+	// AuditActionConfigRestored программа лояльности восстановлена из сохранённой версии
+	AuditActionConfigRestored AuditAction = "config_restored"
+	// AuditActionConfigScheduled изменение программы лояльности запланировано на будущий момент
+	AuditActionConfigScheduled AuditAction = "config_scheduled"
+	// AuditActionConfigScheduleCanceled запланированное изменение программы отменено
+	AuditActionConfigScheduleCanceled AuditAction = "config_schedule_canceled"
+	// AuditActionConfigScheduleApplied запланированное изменение программы вступило в силу
+	AuditActionConfigScheduleApplied AuditAction = "config_schedule_applied"
+	// AuditActionCampaignCreated промо-акция создана
+	AuditActionCampaignCreated AuditAction = "campaign_created"
+	// AuditActionCampaignDeleted промо-акция удалена
+	AuditActionCampaignDeleted AuditAction = "campaign_deleted"
+	// AuditActionHappyHoursUpdated «счастливые часы» компании заданы или изменены
+	AuditActionHappyHoursUpdated AuditAction = "happy_hours_updated"
+	// AuditActionCardCreated карта выпущена
+	AuditActionCardCreated AuditAction = "card_created"
+	// AuditActionCardStatusChanged карта приостановлена, выключена, реактивирована или истекла
+	AuditActionCardStatusChanged AuditAction = "card_status_changed"
+	// AuditActionPointsAccrued на карту начислены баллы
+	AuditActionPointsAccrued AuditAction = "points_accrued"
+	// AuditActionPointsRedeemed с карты списаны баллы
+	AuditActionPointsRedeemed AuditAction = "points_redeemed"
+	// AuditActionVisitRecorded по карте записан визит
+	AuditActionVisitRecorded AuditAction = "visit_recorded"
+)
+
+// AuditEvent запись журнала изменений
+type AuditEvent struct {
+	ID         int64
+	CompanyID  int64
+	EntityType AuditEntityType
+	EntityID   int64
+	Action     AuditAction
+	// ActorID пользователь, выполнивший действие (nil - внутренний сервис или фоновый процесс)
+	ActorID   *int64
+	ActorRole ActorRole
+	// ActorService имя внутреннего сервиса, если действие выполнено по API-ключу
+	ActorService *string
+	// Before состояние сущности до изменения (nil - сущность создана)
+	Before json.RawMessage
+	After  json.RawMessage
+	// Changes изменённые поля: {"поле": {"before": ..., "after": ...}}
+	Changes   json.RawMessage
+	CreatedAt time.Time
+}
+
+// CreateAuditEventInput входные данные для записи события в журнал
+type CreateAuditEventInput struct {
+	CompanyID    int64
+	EntityType   AuditEntityType
+	EntityID     int64
+	Action       AuditAction
+	ActorID      *int64
+	ActorRole    ActorRole
+	ActorService *string
+	Before       json.RawMessage
+	After        json.RawMessage
+	Changes      json.RawMessage
+}
+
+// ListAuditEventsInput параметры выборки журнала компании (от новых событий к старым)
+type ListAuditEventsInput struct {
+	CompanyID int64
+	// EntityType фильтр по типу сущности (nil - все)
+	EntityType *AuditEntityType
+	// EntityID фильтр по ID сущности (nil - все)
+	EntityID *int64
+	// BeforeID вернуть события с ID меньше указанного (курсор предыдущей страницы)
+	BeforeID *int64
+	Limit    int
+}
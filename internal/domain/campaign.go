@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+)
+
+const (
+	// MaxCampaignPointsMultiplier максимальный множитель баллов акции
+	MaxCampaignPointsMultiplier = 10
+	// MaxCampaignNameLength максимальная длина названия акции
+	MaxCampaignNameLength = 255
+)
+
+// LoyaltyCampaign промо-акция компании, действующая поверх программы лояльности
+// Акция действует с StartsAt до EndsAt, а внутри периода - только в дни Weekdays
+// и в интервале WindowStart-WindowEnd (по местному времени Timezone), если они заданы
+type LoyaltyCampaign struct {
+	ID        int64
+	CompanyID int64
+	Name      string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	// Weekdays дни недели ISO 8601 (1 - понедельник, 7 - воскресенье), пусто - каждый день
+	Weekdays []int
+	// WindowStart и WindowEnd интервал времени суток, пусто - весь день
+	WindowStart types.TimeString
+	WindowEnd   types.TimeString
+	// Timezone часовой пояс IANA, в котором заданы дни недели и интервал
+	Timezone string
+	// DiscountPercentage скидка акции, сочетается со скидкой карты по StackingRule
+	DiscountPercentage float64
+	// PointsMultiplier множитель баллов накопительной системы (1 - без изменений)
+	PointsMultiplier float64
+	StackingRule     CampaignStackingRule
+	// MaxDiscountPercentage предел итоговой скидки (только для правила capped)
+	MaxDiscountPercentage *float64
+	CreatedBy             int64
+	CreatedByRole         ActorRole
+	CreatedAt             time.Time
+}
+
+// ListLoyaltyCampaignsInput параметры выборки акций компании
+type ListLoyaltyCampaignsInput struct {
+	CompanyID int64
+	// ActiveAt только акции, период которых включает этот момент (дни недели и интервал не учитываются)
+	ActiveAt *time.Time
+	// EndsAfter только акции, которые заканчиваются позже этого момента
+	EndsAfter *time.Time
+}
+
+// Validate проверяет корректность параметров акции
+func (c *LoyaltyCampaign) Validate() error {
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		return errors.New("campaign name is required")
+	}
+
+	if len(name) > MaxCampaignNameLength {
+		return fmt.Errorf("campaign name must be at most %d characters", MaxCampaignNameLength)
+	}
+
+	if !c.EndsAt.After(c.StartsAt) {
+		return errors.New("campaign ends_at must be after starts_at")
+	}
+
+	seen := make(map[int]bool, len(c.Weekdays))
+	for _, day := range c.Weekdays {
+		if day < 1 || day > 7 {
+			return errors.New("weekdays must be between 1 (monday) and 7 (sunday)")
+		}
+		if seen[day] {
+			return fmt.Errorf("weekday %d is repeated", day)
+		}
+		seen[day] = true
+	}
+
+	if c.WindowStart.IsZero() != c.WindowEnd.IsZero() {
+		return errors.New("window_start and window_end must be set together")
+	}
+
+	if !c.WindowStart.IsZero() {
+		if err := c.WindowStart.Validate(); err != nil {
+			return fmt.Errorf("window_start: %v", err)
+		}
+		if err := c.WindowEnd.Validate(); err != nil {
+			return fmt.Errorf("window_end: %v", err)
+		}
+		if !c.WindowStart.IsBefore(c.WindowEnd) {
+			return errors.New("window_start must be before window_end")
+		}
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil || c.Timezone == "" {
+		return fmt.Errorf("unknown timezone %q", c.Timezone)
+	}
+
+	if c.DiscountPercentage < 0 || c.DiscountPercentage > 100 {
+		return errors.New("campaign discount percentage must be between 0 and 100")
+	}
+
+	if c.PointsMultiplier < 1 || c.PointsMultiplier > MaxCampaignPointsMultiplier {
+		return fmt.Errorf("points multiplier must be between 1 and %d", MaxCampaignPointsMultiplier)
+	}
+
+	if c.DiscountPercentage == 0 && c.PointsMultiplier == 1 {
+		return errors.New("campaign must grant a discount or a points multiplier")
+	}
+
+	switch c.StackingRule {
+	case CampaignStackingMax, CampaignStackingAdditive:
+		if c.MaxDiscountPercentage != nil {
+			return errors.New("max discount percentage is allowed only for capped stacking rule")
+		}
+	case CampaignStackingCapped:
+		if c.MaxDiscountPercentage == nil {
+			return errors.New("max discount percentage is required for capped stacking rule")
+		}
+		if *c.MaxDiscountPercentage <= 0 || *c.MaxDiscountPercentage > 100 {
+			return errors.New("max discount percentage must be between 0 and 100")
+		}
+	default:
+		return errors.New("unsupported stacking rule")
+	}
+
+	return nil
+}
+
+// ActiveAt проверяет, действует ли акция в момент at
+func (c *LoyaltyCampaign) ActiveAt(at time.Time) bool {
+	if at.Before(c.StartsAt) || !at.Before(c.EndsAt) {
+		return false
+	}
+
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return false
+	}
+	local := at.In(location)
+
+	if len(c.Weekdays) > 0 && !containsWeekday(c.Weekdays, local) {
+		return false
+	}
+
+	if c.WindowStart.IsZero() {
+		return true
+	}
+
+	// Интервал [WindowStart, WindowEnd) сравнивается с местным временем с точностью до минуты
+	now := types.NewTimeString(local)
+	return !now.IsBefore(c.WindowStart) && now.IsBefore(c.WindowEnd)
+}
+
+// ApplyTo возвращает скидку карты с учётом акции
+func (c *LoyaltyCampaign) ApplyTo(baseDiscount float64) float64 {
+	switch c.StackingRule {
+	case CampaignStackingAdditive:
+		return math.Min(baseDiscount+c.DiscountPercentage, 100)
+	case CampaignStackingCapped:
+		// Предел ограничивает только прибавку акции: более высокая скидка карты не уменьшается
+		return math.Max(baseDiscount, math.Min(baseDiscount+c.DiscountPercentage, *c.MaxDiscountPercentage))
+	default:
+		return math.Max(baseDiscount, c.DiscountPercentage)
+	}
+}
+
+// CampaignEffect результат применения действующих акций к карте
+type CampaignEffect struct {
+	// DiscountPercentage итоговая скидка карты
+	DiscountPercentage float64
+	// DiscountCampaign акция, которая дала итоговую скидку (nil - действует скидка карты)
+	DiscountCampaign *LoyaltyCampaign
+	// PointsMultiplier итоговый множитель баллов
+	PointsMultiplier float64
+	// Active акции, действующие в момент расчёта
+	Active []*LoyaltyCampaign
+}
+
+// ApplyCampaigns применяет к скидке карты акции, действующие в момент at
+// Акции между собой не суммируются: действует та, что даёт большую скидку,
+// и отдельно - наибольший множитель баллов
+func ApplyCampaigns(baseDiscount float64, campaigns []*LoyaltyCampaign, at time.Time) CampaignEffect {
+	effect := CampaignEffect{
+		DiscountPercentage: baseDiscount,
+		PointsMultiplier:   1,
+	}
+
+	for _, campaign := range campaigns {
+		if !campaign.ActiveAt(at) {
+			continue
+		}
+		effect.Active = append(effect.Active, campaign)
+
+		if discount := campaign.ApplyTo(baseDiscount); discount > effect.DiscountPercentage {
+			effect.DiscountPercentage = discount
+			effect.DiscountCampaign = campaign
+		}
+
+		if campaign.PointsMultiplier > effect.PointsMultiplier {
+			effect.PointsMultiplier = campaign.PointsMultiplier
+		}
+	}
+
+	return effect
+}
+
+// ApplyPointsCampaigns применяет к карте накопительной системы акции, действующие в момент at
+// Такая карта скидку не даёт, поэтому учитывается только наибольший множитель баллов,
+// а среди действующих остаются акции с множителем
+func ApplyPointsCampaigns(campaigns []*LoyaltyCampaign, at time.Time) CampaignEffect {
+	effect := CampaignEffect{PointsMultiplier: 1}
+
+	for _, campaign := range campaigns {
+		if campaign.PointsMultiplier <= 1 || !campaign.ActiveAt(at) {
+			continue
+		}
+		effect.Active = append(effect.Active, campaign)
+
+		if campaign.PointsMultiplier > effect.PointsMultiplier {
+			effect.PointsMultiplier = campaign.PointsMultiplier
+		}
+	}
+
+	return effect
+}
+
+func containsWeekday(weekdays []int, at time.Time) bool {
+	// time.Weekday: воскресенье - 0, в ISO 8601 - 7
+	day := int(at.Weekday())
+	if day == 0 {
+		day = 7
+	}
+
+	for _, weekday := range weekdays {
+		if weekday == day {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	// База часовых поясов для тестов, как и в бинарнике: в образе alpine нет tzdata
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/ptr"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+)
+
+func TestLoyaltyCampaign_ApplyTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     CampaignStackingRule
+		discount float64
+		max      *float64
+		base     float64
+		want     float64
+	}{
+		{name: "max campaign above base", rule: CampaignStackingMax, discount: 15, base: 10, want: 15},
+		{name: "max base above campaign", rule: CampaignStackingMax, discount: 15, base: 20, want: 20},
+		{name: "additive", rule: CampaignStackingAdditive, discount: 15, base: 10, want: 25},
+		{name: "additive limited by 100", rule: CampaignStackingAdditive, discount: 15, base: 90, want: 100},
+		{name: "capped below cap", rule: CampaignStackingCapped, discount: 10, max: ptr.Ptr(20.0), base: 5, want: 15},
+		{name: "capped at cap", rule: CampaignStackingCapped, discount: 15, max: ptr.Ptr(20.0), base: 10, want: 20},
+		{name: "capped base above cap", rule: CampaignStackingCapped, discount: 10, max: ptr.Ptr(20.0), base: 30, want: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			campaign := &LoyaltyCampaign{StackingRule: tt.rule, DiscountPercentage: tt.discount, MaxDiscountPercentage: tt.max}
+			assert.Equal(t, tt.want, campaign.ApplyTo(tt.base))
+		})
+	}
+}
+
+func TestLoyaltyCampaign_ActiveAt(t *testing.T) {
+	// 2025-06-01 - воскресенье, 2025-06-02 - понедельник
+	startsAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		timezone    string
+		weekdays    []int
+		windowStart string
+		windowEnd   string
+		at          time.Time
+		want        bool
+	}{
+		{name: "at starts_at", at: startsAt, want: true},
+		{name: "before starts_at", at: startsAt.Add(-time.Nanosecond), want: false},
+		{name: "just before ends_at", at: endsAt.Add(-time.Nanosecond), want: true},
+		{name: "at ends_at", at: endsAt, want: false},
+		{name: "sunday is 7", weekdays: []int{7}, at: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), want: true},
+		{name: "monday is not 7", weekdays: []int{7}, at: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC), want: false},
+		{name: "monday is 1", weekdays: []int{1, 3}, at: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC), want: true},
+		{
+			name:     "weekday in campaign timezone",
+			timezone: "Europe/Moscow",
+			weekdays: []int{1},
+			// Воскресенье 22:00 UTC - понедельник 01:00 по Москве
+			at:   time.Date(2025, 6, 1, 22, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{name: "at window start", windowStart: "10:00", windowEnd: "12:00", at: time.Date(2025, 6, 3, 10, 0, 0, 0, time.UTC), want: true},
+		{name: "before window end", windowStart: "10:00", windowEnd: "12:00", at: time.Date(2025, 6, 3, 11, 59, 0, 0, time.UTC), want: true},
+		{name: "at window end", windowStart: "10:00", windowEnd: "12:00", at: time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC), want: false},
+		{name: "before window start", windowStart: "10:00", windowEnd: "12:00", at: time.Date(2025, 6, 3, 9, 59, 0, 0, time.UTC), want: false},
+		{
+			name:        "window in campaign timezone",
+			timezone:    "Europe/Moscow",
+			windowStart: "10:00",
+			windowEnd:   "12:00",
+			// 07:30 UTC - 10:30 по Москве
+			at:   time.Date(2025, 6, 3, 7, 30, 0, 0, time.UTC),
+			want: true,
+		},
+		{name: "unknown timezone", timezone: "Mars/Olympus", at: time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timezone := tt.timezone
+			if timezone == "" {
+				timezone = "UTC"
+			}
+
+			campaign := &LoyaltyCampaign{
+				StartsAt:    startsAt,
+				EndsAt:      endsAt,
+				Weekdays:    tt.weekdays,
+				WindowStart: types.TimeString(tt.windowStart),
+				WindowEnd:   types.TimeString(tt.windowEnd),
+				Timezone:    timezone,
+			}
+			assert.Equal(t, tt.want, campaign.ActiveAt(tt.at))
+		})
+	}
+}
+
+func TestApplyCampaigns(t *testing.T) {
+	at := time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	newCampaign := func(id int64, rule CampaignStackingRule, discount, multiplier float64, active bool) *LoyaltyCampaign {
+		campaign := &LoyaltyCampaign{
+			ID:                 id,
+			StartsAt:           at.Add(-time.Hour),
+			EndsAt:             at.Add(time.Hour),
+			Timezone:           "UTC",
+			DiscountPercentage: discount,
+			PointsMultiplier:   multiplier,
+			StackingRule:       rule,
+		}
+		if !active {
+			campaign.StartsAt = at.Add(time.Minute)
+		}
+		return campaign
+	}
+
+	maxCampaign := newCampaign(1, CampaignStackingMax, 15, 2, true)
+	additiveCampaign := newCampaign(2, CampaignStackingAdditive, 10, 1.5, true)
+	inactiveCampaign := newCampaign(3, CampaignStackingAdditive, 50, 5, false)
+
+	tests := []struct {
+		name           string
+		base           float64
+		campaigns      []*LoyaltyCampaign
+		wantDiscount   float64
+		wantCampaign   *LoyaltyCampaign
+		wantMultiplier float64
+		wantActive     []*LoyaltyCampaign
+	}{
+		{name: "no campaigns", base: 10, wantDiscount: 10, wantMultiplier: 1},
+		{
+			name:           "inactive campaign is ignored",
+			base:           10,
+			campaigns:      []*LoyaltyCampaign{inactiveCampaign},
+			wantDiscount:   10,
+			wantMultiplier: 1,
+		},
+		{
+			name:           "largest discount and multiplier win separately",
+			base:           10,
+			campaigns:      []*LoyaltyCampaign{maxCampaign, additiveCampaign, inactiveCampaign},
+			wantDiscount:   20,
+			wantCampaign:   additiveCampaign,
+			wantMultiplier: 2,
+			wantActive:     []*LoyaltyCampaign{maxCampaign, additiveCampaign},
+		},
+		{
+			name:           "card discount above campaigns",
+			base:           30,
+			campaigns:      []*LoyaltyCampaign{maxCampaign},
+			wantDiscount:   30,
+			wantMultiplier: 2,
+			wantActive:     []*LoyaltyCampaign{maxCampaign},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effect := ApplyCampaigns(tt.base, tt.campaigns, at)
+
+			assert.Equal(t, tt.wantDiscount, effect.DiscountPercentage)
+			assert.Same(t, tt.wantCampaign, effect.DiscountCampaign)
+			assert.Equal(t, tt.wantMultiplier, effect.PointsMultiplier)
+			assert.Equal(t, tt.wantActive, effect.Active)
+		})
+	}
+}
+
+func TestApplyPointsCampaigns(t *testing.T) {
+	at := time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	newCampaign := func(id int64, discount, multiplier float64) *LoyaltyCampaign {
+		return &LoyaltyCampaign{
+			ID:                 id,
+			StartsAt:           at.Add(-time.Hour),
+			EndsAt:             at.Add(time.Hour),
+			Timezone:           "UTC",
+			DiscountPercentage: discount,
+			PointsMultiplier:   multiplier,
+			StackingRule:       CampaignStackingAdditive,
+		}
+	}
+
+	discountOnly := newCampaign(1, 20, 1)
+	double := newCampaign(2, 10, 2)
+	triple := newCampaign(3, 0, 3)
+	finished := newCampaign(4, 0, 5)
+	finished.EndsAt = at
+
+	effect := ApplyPointsCampaigns([]*LoyaltyCampaign{discountOnly, double, triple, finished}, at)
+
+	assert.Zero(t, effect.DiscountPercentage)
+	assert.Nil(t, effect.DiscountCampaign)
+	assert.Equal(t, 3.0, effect.PointsMultiplier)
+	assert.Equal(t, []*LoyaltyCampaign{double, triple}, effect.Active)
+
+	assert.Equal(t, CampaignEffect{PointsMultiplier: 1}, ApplyPointsCampaigns(nil, at))
+}
@@ -0,0 +1,29 @@
+package domain
+
+// MaxStatusReasonLength максимальная длина причины смены статуса карты
+const MaxStatusReasonLength = 500
+
+// cardStatusTransitions допустимые переходы между статусами карты
+// expired выставляется только системой по истечении срока действия карты
+var cardStatusTransitions = map[CardStatus][]CardStatus{
+	CardStatusActive:    {CardStatusSuspended, CardStatusDisabled, CardStatusExpired},
+	CardStatusSuspended: {CardStatusActive, CardStatusDisabled, CardStatusExpired},
+	CardStatusDisabled:  {CardStatusActive},
+	CardStatusExpired:   {CardStatusActive},
+}
+
+// CanTransitionTo проверяет, допустим ли переход карты из текущего статуса в target
+func (s CardStatus) CanTransitionTo(target CardStatus) bool {
+	for _, allowed := range cardStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GrantsDiscount сообщает, даёт ли карта в этом статусе скидку и доступ к операциям
+func (s CardStatus) GrantsDiscount() bool {
+	return s == CardStatusActive
+}
@@ -6,9 +6,9 @@ type CardType string
 const (
 	// CardTypeFixedDiscount фиксированная скидка (текущая реализация)
 	CardTypeFixedDiscount CardType = "fixed_discount"
-	// CardTypeProgressiveDiscount прогрессивная скидка (будущее)
+	// CardTypeProgressiveDiscount прогрессивная скидка (зависит от количества визитов)
 	CardTypeProgressiveDiscount CardType = "progressive_discount"
-	// CardTypePointsBased накопительная система баллов (будущее)
+	// CardTypePointsBased накопительная система баллов
 	CardTypePointsBased CardType = "points_based"
 )
 
@@ -25,3 +25,55 @@ const (
 	// CardStatusExpired истёкшая карта
 	CardStatusExpired CardStatus = "expired"
 )
+
+// DiscountUpdatePolicy политика применения изменённой скидки программы к выпущенным картам
+type DiscountUpdatePolicy string
+
+const (
+	// DiscountUpdatePolicyApplyToAll новая скидка применяется ко всем картам компании
+	DiscountUpdatePolicyApplyToAll DiscountUpdatePolicy = "apply_to_all"
+	// DiscountUpdatePolicyNewCardsOnly новая скидка действует только для новых карт
+	DiscountUpdatePolicyNewCardsOnly DiscountUpdatePolicy = "new_cards_only"
+	// DiscountUpdatePolicyNeverLower новая скидка применяется к картам, только если она не ниже текущей
+	DiscountUpdatePolicyNeverLower DiscountUpdatePolicy = "never_lower"
+)
+
+// ConfigScheduleStatus статусы запланированного изменения программы лояльности
+type ConfigScheduleStatus string
+
+const (
+	// ConfigScheduleStatusPending изменение ожидает наступления effective_from
+	ConfigScheduleStatusPending ConfigScheduleStatus = "pending"
+	// ConfigScheduleStatusApplied изменение перенесено в конфигурацию компании
+	ConfigScheduleStatusApplied ConfigScheduleStatus = "applied"
+	// ConfigScheduleStatusCanceled изменение отменено менеджером
+	ConfigScheduleStatusCanceled ConfigScheduleStatus = "canceled"
+)
+
+// CampaignStackingRule правило сочетания скидки акции с базовой скидкой карты
+type CampaignStackingRule string
+
+const (
+	// CampaignStackingMax действует большая из скидок: карты или акции
+	CampaignStackingMax CampaignStackingRule = "max"
+	// CampaignStackingAdditive скидка акции прибавляется к скидке карты (не более 100%)
+	CampaignStackingAdditive CampaignStackingRule = "additive"
+	// CampaignStackingCapped скидка акции прибавляется к скидке карты, но сумма не превышает max_discount_percentage
+	CampaignStackingCapped CampaignStackingRule = "capped"
+)
+
+// ActorRole роль, в которой пользователь выполнил действие
+type ActorRole string
+
+const (
+	// ActorRoleCustomer клиент, действующий со своей картой
+	ActorRoleCustomer ActorRole = "customer"
+	// ActorRoleManager менеджер компании (проверен через SellerService)
+	ActorRoleManager ActorRole = "manager"
+	// ActorRoleSuperuser суперпользователь, действующий в обход проверки менеджера
+	ActorRoleSuperuser ActorRole = "superuser"
+	// ActorRoleService внутренний сервис, аутентифицированный по API-ключу
+	ActorRoleService ActorRole = "service"
+	// ActorRoleSystem фоновый процесс самого сервиса (автор действия не указывается)
+	ActorRoleSystem ActorRole = "system"
+)
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+)
+
+// MaxHappyHourWindows максимальное количество интервалов «счастливых часов» компании
+const MaxHappyHourWindows = 28
+
+// HappyHourWindow интервал «счастливых часов» в один день недели
+// Интервал [Start, End) задан по местному времени компании и не переходит через полночь
+type HappyHourWindow struct {
+	// Weekday день недели ISO 8601 (1 - понедельник, 7 - воскресенье)
+	Weekday int              `json:"weekday"`
+	Start   types.TimeString `json:"start"`
+	End     types.TimeString `json:"end"`
+	// DiscountPercentage дополнительная скидка, прибавляется к скидке карты
+	DiscountPercentage float64 `json:"discount_percentage"`
+}
+
+// ApplyTo возвращает скидку карты с учётом надбавки интервала
+func (w *HappyHourWindow) ApplyTo(baseDiscount float64) float64 {
+	return math.Min(baseDiscount+w.DiscountPercentage, 100)
+}
+
+// HappyHours «счастливые часы» компании (loyalty_happy_hours)
+// Timezone - местное время компании: в нём заданы интервалы и по нему рассчитывается скидка
+type HappyHours struct {
+	ID            int64
+	CompanyID     int64
+	Timezone      string
+	Windows       []HappyHourWindow
+	UpdatedBy     int64
+	UpdatedByRole ActorRole
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// WorkingDay часы работы компании в один день недели
+type WorkingDay struct {
+	IsOpen bool
+	// OpenTime и CloseTime не заданы - компания работает круглосуточно;
+	// CloseTime не позже OpenTime - работа продолжается после полуночи,
+	// и её утренняя часть [00:00, CloseTime) приходится на следующий день недели
+	OpenTime  types.TimeString
+	CloseTime types.TimeString
+}
+
+// Covers проверяет, что интервал [start, end) приходится на часы работы, начатые в этот день
+// (при работе после полуночи - на вечернюю часть [OpenTime, 24:00))
+func (d WorkingDay) Covers(start, end types.TimeString) bool {
+	if !d.IsOpen {
+		return false
+	}
+
+	if d.OpenTime.IsZero() || d.CloseTime.IsZero() {
+		return true
+	}
+
+	if start.IsBefore(d.OpenTime) {
+		return false
+	}
+
+	_, overnight := d.NextDayUntil()
+	return overnight || !d.CloseTime.IsBefore(end)
+}
+
+// NextDayUntil возвращает время закрытия утренней части работы после полуночи [00:00, CloseTime),
+// которая приходится на следующий день недели (false - работа не продолжается после полуночи)
+func (d WorkingDay) NextDayUntil() (types.TimeString, bool) {
+	if !d.IsOpen || d.OpenTime.IsZero() || d.CloseTime.IsZero() || d.OpenTime.IsBefore(d.CloseTime) {
+		return "", false
+	}
+
+	return d.CloseTime, true
+}
+
+// Validate проверяет часовой пояс и интервалы: дни недели, формат времени, скидку и пересечения
+func (h *HappyHours) Validate() error {
+	if _, err := time.LoadLocation(h.Timezone); err != nil || h.Timezone == "" {
+		return fmt.Errorf("unknown timezone %q", h.Timezone)
+	}
+
+	if len(h.Windows) > MaxHappyHourWindows {
+		return fmt.Errorf("at most %d happy hour windows are allowed", MaxHappyHourWindows)
+	}
+
+	for i, window := range h.Windows {
+		if window.Weekday < 1 || window.Weekday > 7 {
+			return fmt.Errorf("window %d: weekday must be between 1 (monday) and 7 (sunday)", i+1)
+		}
+		if window.Start.IsZero() || window.End.IsZero() {
+			return fmt.Errorf("window %d: start and end are required", i+1)
+		}
+		if err := window.Start.Validate(); err != nil {
+			return fmt.Errorf("window %d: start: %v", i+1, err)
+		}
+		if err := window.End.Validate(); err != nil {
+			return fmt.Errorf("window %d: end: %v", i+1, err)
+		}
+		if !window.Start.IsBefore(window.End) {
+			return fmt.Errorf("window %d: start must be before end", i+1)
+		}
+		if window.DiscountPercentage <= 0 || window.DiscountPercentage > 100 {
+			return fmt.Errorf("window %d: discount percentage must be between 0 and 100", i+1)
+		}
+	}
+
+	// Интервалы одного дня не должны пересекаться, иначе надбавка в момент пересечения неоднозначна
+	windows := h.SortedWindows()
+	for i := 1; i < len(windows); i++ {
+		prev, cur := windows[i-1], windows[i]
+		if prev.Weekday == cur.Weekday && cur.Start.IsBefore(prev.End) {
+			return fmt.Errorf("windows %s-%s and %s-%s overlap on weekday %d", prev.Start, prev.End, cur.Start, cur.End, cur.Weekday)
+		}
+	}
+
+	return nil
+}
+
+// FitWorkingHours проверяет, что каждый интервал приходится на часы работы компании в свой день недели
+// Утро дня покрывает и работа после полуночи, начатая накануне (в предыдущий день недели)
+// workingDays - часы работы по дням недели ISO 8601
+func (h *HappyHours) FitWorkingHours(workingDays map[int]WorkingDay) error {
+	for _, window := range h.Windows {
+		day := workingDays[window.Weekday]
+		until, carried := workingDays[previousWeekday(window.Weekday)].NextDayUntil()
+
+		switch {
+		case carried && !until.IsBefore(window.End):
+			// Интервал приходится на работу после полуночи, начатую накануне
+		case day.Covers(window.Start, window.End):
+		case carried && day.IsOpen && !until.IsBefore(day.OpenTime) && day.Covers(day.OpenTime, window.End):
+			// Работа, начатая накануне, продолжается без перерыва открытием этого дня
+		case !day.IsOpen && !carried:
+			return fmt.Errorf("company is closed on weekday %d", window.Weekday)
+		case !day.IsOpen:
+			return fmt.Errorf("window %s-%s on weekday %d is outside working hours 00:00-%s",
+				window.Start, window.End, window.Weekday, until)
+		default:
+			return fmt.Errorf("window %s-%s on weekday %d is outside working hours %s-%s",
+				window.Start, window.End, window.Weekday, day.OpenTime, day.CloseTime)
+		}
+	}
+
+	return nil
+}
+
+// previousWeekday возвращает предыдущий день недели ISO 8601 (для понедельника - воскресенье)
+func previousWeekday(weekday int) int {
+	if weekday == 1 {
+		return 7
+	}
+	return weekday - 1
+}
+
+// SortedWindows возвращает копию интервалов, упорядоченную по дню недели и началу
+func (h *HappyHours) SortedWindows() []HappyHourWindow {
+	windows := make([]HappyHourWindow, len(h.Windows))
+	copy(windows, h.Windows)
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		if windows[i].Weekday != windows[j].Weekday {
+			return windows[i].Weekday < windows[j].Weekday
+		}
+		return windows[i].Start.IsBefore(windows[j].Start)
+	})
+
+	return windows
+}
+
+// WindowAt возвращает интервал, действующий в момент at по местному времени компании (nil - вне интервалов)
+func (h *HappyHours) WindowAt(at time.Time) *HappyHourWindow {
+	location, err := time.LoadLocation(h.Timezone)
+	if err != nil {
+		return nil
+	}
+	local := at.In(location)
+	now := types.NewTimeString(local)
+
+	for i := range h.Windows {
+		window := &h.Windows[i]
+		if !containsWeekday([]int{window.Weekday}, local) {
+			continue
+		}
+		if !now.IsBefore(window.Start) && now.IsBefore(window.End) {
+			return window
+		}
+	}
+
+	return nil
+}
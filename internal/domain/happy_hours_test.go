@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+)
+
+func openDay(openTime, closeTime string) WorkingDay {
+	return WorkingDay{IsOpen: true, OpenTime: types.TimeString(openTime), CloseTime: types.TimeString(closeTime)}
+}
+
+func TestWorkingDay_Covers(t *testing.T) {
+	tests := []struct {
+		name  string
+		day   WorkingDay
+		start string
+		end   string
+		want  bool
+	}{
+		{name: "closed", day: WorkingDay{}, start: "10:00", end: "12:00", want: false},
+		{name: "around the clock", day: WorkingDay{IsOpen: true}, start: "00:00", end: "23:59", want: true},
+		{name: "inside", day: openDay("09:00", "21:00"), start: "10:00", end: "12:00", want: true},
+		{name: "ends at close", day: openDay("09:00", "21:00"), start: "20:00", end: "21:00", want: true},
+		{name: "starts before open", day: openDay("09:00", "21:00"), start: "08:00", end: "10:00", want: false},
+		{name: "ends after close", day: openDay("09:00", "21:00"), start: "20:00", end: "21:30", want: false},
+		{name: "overnight evening part", day: openDay("20:00", "02:00"), start: "21:00", end: "23:59", want: true},
+		// Утренняя часть относится к работе, начатой накануне, а не в этот день
+		{name: "overnight morning part", day: openDay("20:00", "02:00"), start: "00:30", end: "01:30", want: false},
+		{name: "overnight starts before open", day: openDay("20:00", "02:00"), start: "19:00", end: "21:00", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.day.Covers(types.TimeString(tt.start), types.TimeString(tt.end)))
+		})
+	}
+}
+
+func TestHappyHours_FitWorkingHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		days    map[int]WorkingDay
+		window  HappyHourWindow
+		wantErr string
+	}{
+		{
+			name:   "inside working hours",
+			days:   map[int]WorkingDay{3: openDay("09:00", "21:00")},
+			window: HappyHourWindow{Weekday: 3, Start: "10:00", End: "12:00"},
+		},
+		{
+			name:    "closed day",
+			days:    map[int]WorkingDay{2: openDay("09:00", "21:00")},
+			window:  HappyHourWindow{Weekday: 3, Start: "10:00", End: "12:00"},
+			wantErr: "company is closed on weekday 3",
+		},
+		{
+			name:   "morning after overnight previous day",
+			days:   map[int]WorkingDay{1: openDay("20:00", "02:00"), 2: openDay("10:00", "22:00")},
+			window: HappyHourWindow{Weekday: 2, Start: "00:30", End: "01:30"},
+		},
+		{
+			name:    "morning of own overnight day",
+			days:    map[int]WorkingDay{2: openDay("20:00", "02:00")},
+			window:  HappyHourWindow{Weekday: 2, Start: "00:30", End: "01:30"},
+			wantErr: "outside working hours",
+		},
+		{
+			name:    "morning after regular previous day",
+			days:    map[int]WorkingDay{1: openDay("09:00", "21:00"), 2: openDay("20:00", "02:00")},
+			window:  HappyHourWindow{Weekday: 2, Start: "00:30", End: "01:30"},
+			wantErr: "outside working hours",
+		},
+		{
+			name:   "monday morning after overnight sunday",
+			days:   map[int]WorkingDay{7: openDay("18:00", "03:00")},
+			window: HappyHourWindow{Weekday: 1, Start: "01:00", End: "02:00"},
+		},
+		{
+			name:    "monday window past sunday overnight close",
+			days:    map[int]WorkingDay{7: openDay("18:00", "03:00")},
+			window:  HappyHourWindow{Weekday: 1, Start: "02:00", End: "04:00"},
+			wantErr: "outside working hours 00:00-03:00",
+		},
+		{
+			name:   "overnight continues into opening",
+			days:   map[int]WorkingDay{1: openDay("20:00", "02:00"), 2: openDay("02:00", "12:00")},
+			window: HappyHourWindow{Weekday: 2, Start: "01:00", End: "03:00"},
+		},
+		{
+			name:    "gap between overnight close and opening",
+			days:    map[int]WorkingDay{1: openDay("20:00", "02:00"), 2: openDay("03:00", "12:00")},
+			window:  HappyHourWindow{Weekday: 2, Start: "01:00", End: "04:00"},
+			wantErr: "outside working hours 03:00-12:00",
+		},
+		{
+			name:    "around the clock previous day does not carry over",
+			days:    map[int]WorkingDay{1: {IsOpen: true}},
+			window:  HappyHourWindow{Weekday: 2, Start: "01:00", End: "02:00"},
+			wantErr: "company is closed on weekday 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			happyHours := &HappyHours{Windows: []HappyHourWindow{tt.window}}
+
+			err := happyHours.FitWorkingHours(tt.days)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestHappyHours_WindowAt(t *testing.T) {
+	happyHours := &HappyHours{
+		Timezone: "Europe/Moscow",
+		Windows: []HappyHourWindow{
+			{Weekday: 1, Start: "00:00", End: "01:00", DiscountPercentage: 5},
+			{Weekday: 1, Start: "10:00", End: "12:00", DiscountPercentage: 10},
+			{Weekday: 7, Start: "22:00", End: "23:59", DiscountPercentage: 15},
+		},
+	}
+
+	// 2025-06-01 - воскресенье, 2025-06-02 - понедельник; Москва - UTC+3
+	tests := []struct {
+		name string
+		at   time.Time
+		want *HappyHourWindow
+	}{
+		{name: "at window start", at: time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC), want: &happyHours.Windows[1]},
+		{name: "at window end", at: time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), want: nil},
+		{name: "same time on another weekday", at: time.Date(2025, 6, 3, 7, 0, 0, 0, time.UTC), want: nil},
+		{name: "sunday is 7", at: time.Date(2025, 6, 1, 19, 30, 0, 0, time.UTC), want: &happyHours.Windows[2]},
+		// Воскресенье 21:30 UTC - понедельник 00:30 по Москве
+		{name: "monday in local time", at: time.Date(2025, 6, 1, 21, 30, 0, 0, time.UTC), want: &happyHours.Windows[0]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Same(t, tt.want, happyHours.WindowAt(tt.at))
+		})
+	}
+
+	t.Run("unknown timezone", func(t *testing.T) {
+		broken := &HappyHours{Timezone: "Mars/Olympus", Windows: happyHours.Windows}
+		assert.Nil(t, broken.WindowAt(time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)))
+	})
+}
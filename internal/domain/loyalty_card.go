@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // LoyaltyCard представляет карту лояльности клиента
 type LoyaltyCard struct {
@@ -10,6 +13,12 @@ type LoyaltyCard struct {
 	CardType           CardType
 	Status             CardStatus
 	DiscountPercentage float64
+	VisitsCount        int
+	PointsBalance      int64
+	StatusReason       *string
+	StatusChangedAt    *time.Time
+	StatusChangedBy    *int64
+	ExpiresAt          *time.Time // nil - карта бессрочная
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 }
@@ -28,8 +37,95 @@ type UpdateLoyaltyCardInput struct {
 	UserID    *int64
 	CompanyID *int64
 
+	// ExpectedStatus обновлять карту, только если её статус не изменился с момента чтения
+	ExpectedStatus *CardStatus
+
 	// Обновляемые поля
 	CardType           *CardType
 	Status             *CardStatus
 	DiscountPercentage *float64
+	// StatusReason, StatusChangedBy и StatusChangedByRole сохраняются вместе со сменой Status
+	StatusReason        *string
+	StatusChangedBy     *int64
+	StatusChangedByRole *ActorRole
+	// RenewExpiry перезаписать expires_at значением ExpiresAt (nil - сделать карту бессрочной)
+	RenewExpiry bool
+	ExpiresAt   *time.Time
+}
+
+// ListUserCardsInput параметры выборки карт клиента по всем компаниям
+type ListUserCardsInput struct {
+	UserID int64
+	// Status фильтр по статусу карты (nil - все статусы)
+	Status *CardStatus
+	// AfterID курсор: вернуть карты с ID больше указанного
+	AfterID int64
+	Limit   int
+}
+
+// CardSortField поле сортировки списка карт компании
+type CardSortField string
+
+const (
+	// CardSortCreatedAt сортировка по дате выпуска карты
+	CardSortCreatedAt CardSortField = "created_at"
+	// CardSortVisitsCount сортировка по количеству визитов
+	CardSortVisitsCount CardSortField = "visits_count"
+	// CardSortPointsBalance сортировка по балансу баллов
+	CardSortPointsBalance CardSortField = "points_balance"
+)
+
+// CardCursor позиция keyset-пагинации: значение поля сортировки и ID последней карты страницы
+type CardCursor struct {
+	Value string
+	ID    int64
+}
+
+// ListCompanyCardsInput параметры выборки карт компании
+type ListCompanyCardsInput struct {
+	CompanyID int64
+	// Фильтры (nil - без фильтра)
+	Status      *CardStatus
+	CardType    *CardType
+	CreatedFrom *time.Time // включительно
+	CreatedTo   *time.Time // не включительно
+
+	SortBy     CardSortField
+	Descending bool
+	// After курсор: вернуть карты, следующие за указанной позицией
+	After *CardCursor
+	// Limit размер страницы (0 - без ограничения, для потоковой выгрузки)
+	Limit int
+}
+
+// BulkUpdateCardDiscountInput входные данные для массового обновления скидки карт компании
+type BulkUpdateCardDiscountInput struct {
+	CompanyID          int64
+	CardType           CardType
+	DiscountPercentage float64
+	// OnlyIncrease не снижать скидку карт (политика never_lower); тип карты обновляется у всех карт
+	OnlyIncrease bool
+}
+
+// EffectiveStatus возвращает статус карты с учётом срока действия
+// Карта с истёкшим сроком считается expired ещё до того, как её обработает фоновый процесс
+func (c *LoyaltyCard) EffectiveStatus(now time.Time) CardStatus {
+	if c.ExpiresAt != nil && !now.Before(*c.ExpiresAt) &&
+		(c.Status == CardStatusActive || c.Status == CardStatusSuspended) {
+		return CardStatusExpired
+	}
+
+	return c.Status
+}
+
+// SortValue возвращает значение поля сортировки карты в строковом виде для курсора
+func (c *LoyaltyCard) SortValue(field CardSortField) string {
+	switch field {
+	case CardSortVisitsCount:
+		return strconv.Itoa(c.VisitsCount)
+	case CardSortPointsBalance:
+		return strconv.FormatInt(c.PointsBalance, 10)
+	default:
+		return c.CreatedAt.Format(time.RFC3339Nano)
+	}
 }
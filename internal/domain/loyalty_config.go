@@ -2,45 +2,281 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
+// MaxCardValidityDays максимальный срок действия карты (10 лет)
+const MaxCardValidityDays = 3650
+
 // LoyaltyConfig представляет конфигурацию программы лояльности компании
 type LoyaltyConfig struct {
 	ID                 int64
 	CompanyID          int64
 	CardType           CardType
 	IsEnabled          bool
-	DiscountPercentage *float64 // Для fixed_discount
-	ProgressiveConfig  []byte   // JSONB для progressive_discount (будущее)
-	PointsConfig       []byte   // JSONB для points_based (будущее)
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	DiscountPercentage *float64           // Для fixed_discount
+	ProgressiveConfig  *ProgressiveConfig // Для progressive_discount
+	PointsConfig       *PointsConfig      // Для points_based
+	CardValidityDays   int                // Срок действия новых карт в днях, 0 - карты бессрочные
+	// DiscountUpdatePolicy применение изменённой скидки к выпущенным картам (для fixed_discount)
+	DiscountUpdatePolicy DiscountUpdatePolicy
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
 // CreateLoyaltyConfigInput входные данные для создания конфигурации
 type CreateLoyaltyConfigInput struct {
-	CompanyID          int64
-	IsEnabled          bool
-	DiscountPercentage float64
+	CompanyID            int64
+	CardType             CardType
+	IsEnabled            bool
+	DiscountPercentage   *float64
+	ProgressiveConfig    *ProgressiveConfig
+	PointsConfig         *PointsConfig
+	CardValidityDays     int
+	DiscountUpdatePolicy DiscountUpdatePolicy
+	// UpdatedBy и UpdatedByRole автор изменения конфигурации
+	UpdatedBy     int64
+	UpdatedByRole ActorRole
 }
 
 // UpdateLoyaltyConfigInput входные данные для обновления конфигурации
 type UpdateLoyaltyConfigInput struct {
-	CompanyID          int64
-	IsEnabled          *bool
-	DiscountPercentage *float64
+	CompanyID            int64
+	CardType             *CardType
+	IsEnabled            *bool
+	DiscountPercentage   *float64
+	ProgressiveConfig    *ProgressiveConfig
+	PointsConfig         *PointsConfig
+	CardValidityDays     *int // 0 - снять ограничение срока действия
+	DiscountUpdatePolicy *DiscountUpdatePolicy
+	// UpdatedBy и UpdatedByRole автор изменения конфигурации
+	UpdatedBy     int64
+	UpdatedByRole ActorRole
+}
+
+// LoyaltyConfigVersion сохранённое состояние конфигурации после одного изменения
+type LoyaltyConfigVersion struct {
+	// Version номер версии внутри компании, начиная с 1
+	Version int
+	// Config снимок конфигурации (ID - ID конфигурации, UpdatedAt - момент изменения)
+	Config LoyaltyConfig
+	// CreatedBy и CreatedByRole автор изменения (CreatedBy nil - изменение выполнил сервис)
+	CreatedBy     *int64
+	CreatedByRole ActorRole
+}
+
+// ListLoyaltyConfigVersionsInput параметры выборки версий конфигурации (от новых к старым)
+type ListLoyaltyConfigVersionsInput struct {
+	CompanyID int64
+	// BeforeVersion вернуть версии с номером меньше указанного (курсор предыдущей страницы)
+	BeforeVersion *int
+	Limit         int
+}
+
+// LoyaltyConfigChange изменение параметров программы лояльности (nil - параметр не меняется)
+type LoyaltyConfigChange struct {
+	CardType             *CardType
+	IsEnabled            *bool
+	DiscountPercentage   *float64
+	ProgressiveConfig    *ProgressiveConfig
+	PointsConfig         *PointsConfig
+	CardValidityDays     *int // 0 - снять ограничение срока действия
+	DiscountUpdatePolicy *DiscountUpdatePolicy
+}
+
+// ApplyTo возвращает копию конфигурации current с параметрами, заданными в изменении
+func (c *LoyaltyConfigChange) ApplyTo(current *LoyaltyConfig) *LoyaltyConfig {
+	config := *current
+
+	if c.CardType != nil {
+		config.CardType = *c.CardType
+	}
+	if c.IsEnabled != nil {
+		config.IsEnabled = *c.IsEnabled
+	}
+	if c.DiscountPercentage != nil {
+		config.DiscountPercentage = c.DiscountPercentage
+	}
+	if c.ProgressiveConfig != nil {
+		config.ProgressiveConfig = c.ProgressiveConfig
+	}
+	if c.PointsConfig != nil {
+		config.PointsConfig = c.PointsConfig
+	}
+	if c.CardValidityDays != nil {
+		config.CardValidityDays = *c.CardValidityDays
+	}
+	if c.DiscountUpdatePolicy != nil {
+		config.DiscountUpdatePolicy = *c.DiscountUpdatePolicy
+	}
+
+	return &config
+}
+
+// Fields возвращает названия параметров, которые меняет изменение
+func (c *LoyaltyConfigChange) Fields() []string {
+	fields := make([]string, 0, 7)
+	if c.CardType != nil {
+		fields = append(fields, "card_type")
+	}
+	if c.IsEnabled != nil {
+		fields = append(fields, "is_enabled")
+	}
+	if c.DiscountPercentage != nil {
+		fields = append(fields, "discount_percentage")
+	}
+	if c.ProgressiveConfig != nil {
+		fields = append(fields, "progressive_config")
+	}
+	if c.PointsConfig != nil {
+		fields = append(fields, "points_config")
+	}
+	if c.CardValidityDays != nil {
+		fields = append(fields, "card_validity_days")
+	}
+	if c.DiscountUpdatePolicy != nil {
+		fields = append(fields, "discount_update_policy")
+	}
+
+	return fields
+}
+
+// LoyaltyConfigSchedule запланированное изменение программы лояльности
+type LoyaltyConfigSchedule struct {
+	ID            int64
+	CompanyID     int64
+	EffectiveFrom time.Time
+	Status        ConfigScheduleStatus
+	// Change параметры из запроса, которые начнут действовать с EffectiveFrom
+	Change        LoyaltyConfigChange
+	CreatedBy     int64
+	CreatedByRole ActorRole
+	CreatedAt     time.Time
+	// ResolvedAt момент переноса в конфигурацию или отмены (nil - изменение ожидает)
+	ResolvedAt *time.Time
+}
+
+// CreateLoyaltyConfigScheduleInput входные данные для планирования изменения конфигурации
+type CreateLoyaltyConfigScheduleInput struct {
+	CompanyID     int64
+	EffectiveFrom time.Time
+	// Change параметры из запроса, которые начнут действовать с EffectiveFrom
+	Change        LoyaltyConfigChange
+	CreatedBy     int64
+	CreatedByRole ActorRole
+}
+
+// ApplyTo возвращает конфигурацию компании current после наступления запланированного изменения
+// Изменение накладывается на конфигурацию, действующую к этому моменту, поэтому немедленные изменения
+// других параметров сохраняются. Если с изменением конфигурация не проходит проверку
+// (её успели изменить несовместимо), возвращается ошибка проверки
+func (s *LoyaltyConfigSchedule) ApplyTo(current *LoyaltyConfig) (*LoyaltyConfig, error) {
+	config := s.Change.ApplyTo(current)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config.UpdatedAt = s.EffectiveFrom
+
+	return config, nil
 }
 
 // Validate проверяет корректность конфигурации программы лояльности
 func (c *LoyaltyConfig) Validate() error {
-	if c.DiscountPercentage == nil {
-		return errors.New("discount percentage is required")
+	if c.CardValidityDays < 0 || c.CardValidityDays > MaxCardValidityDays {
+		return fmt.Errorf("card validity days must be between 0 and %d", MaxCardValidityDays)
 	}
 
-	if *c.DiscountPercentage < 0 || *c.DiscountPercentage > 100 {
-		return errors.New("discount percentage must be between 0 and 100")
+	switch c.DiscountUpdatePolicy {
+	case DiscountUpdatePolicyApplyToAll, DiscountUpdatePolicyNewCardsOnly, DiscountUpdatePolicyNeverLower:
+	default:
+		return errors.New("unsupported discount update policy")
+	}
+
+	switch c.CardType {
+	case CardTypeFixedDiscount:
+		if c.DiscountPercentage == nil {
+			return errors.New("discount percentage is required")
+		}
+
+		if *c.DiscountPercentage < 0 || *c.DiscountPercentage > 100 {
+			return errors.New("discount percentage must be between 0 and 100")
+		}
+	case CardTypeProgressiveDiscount:
+		if c.ProgressiveConfig == nil {
+			return errors.New("progressive config is required")
+		}
+
+		return c.ProgressiveConfig.Validate()
+	case CardTypePointsBased:
+		if c.PointsConfig == nil {
+			return errors.New("points config is required")
+		}
+
+		return c.PointsConfig.Validate()
+	default:
+		return errors.New("unsupported card type")
 	}
 
 	return nil
 }
+
+// BaseDiscount возвращает скидку, которую получает новая карта (без истории визитов)
+// Карты накопительной системы процентной скидки не дают
+func (c *LoyaltyConfig) BaseDiscount() float64 {
+	switch c.CardType {
+	case CardTypeProgressiveDiscount:
+		if c.ProgressiveConfig != nil && len(c.ProgressiveConfig.Tiers) > 0 {
+			return c.ProgressiveConfig.StatusFor(0).CurrentTier.DiscountPercentage
+		}
+	case CardTypePointsBased:
+		return 0
+	default:
+		if c.DiscountPercentage != nil {
+			return *c.DiscountPercentage
+		}
+	}
+
+	return 0
+}
+
+// CardExpiresAt возвращает дату истечения карты, выпущенной в issuedAt (nil - карта бессрочная)
+func (c *LoyaltyConfig) CardExpiresAt(issuedAt time.Time) *time.Time {
+	if c.CardValidityDays <= 0 {
+		return nil
+	}
+
+	expiresAt := issuedAt.AddDate(0, 0, c.CardValidityDays)
+	return &expiresAt
+}
+
+// CardDiscountUpdate определяет, как изменение программы с previous на c должно отразиться
+// на уже выпущенных картах компании. Возвращает nil, если карты обновлять не нужно
+// Политика применяется только к fixed_discount: скидка прогрессивных карт вычисляется
+// по текущим уровням, а карты накопительной системы процентной скидки не дают
+func (c *LoyaltyConfig) CardDiscountUpdate(previous *LoyaltyConfig) *BulkUpdateCardDiscountInput {
+	if c.CardType != CardTypeFixedDiscount || c.DiscountPercentage == nil {
+		return nil
+	}
+
+	if previous != nil && previous.CardType == c.CardType && previous.DiscountPercentage != nil &&
+		*previous.DiscountPercentage == *c.DiscountPercentage {
+		return nil
+	}
+
+	input := &BulkUpdateCardDiscountInput{
+		CompanyID:          c.CompanyID,
+		CardType:           c.CardType,
+		DiscountPercentage: *c.DiscountPercentage,
+	}
+
+	switch c.DiscountUpdatePolicy {
+	case DiscountUpdatePolicyApplyToAll:
+		return input
+	case DiscountUpdatePolicyNeverLower:
+		input.OnlyIncrease = true
+		return input
+	default:
+		return nil
+	}
+}
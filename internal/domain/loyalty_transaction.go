@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// TransactionType тип операции в журнале баллов
+type TransactionType string
+
+const (
+	// TransactionTypeAccrual начисление баллов
+	TransactionTypeAccrual TransactionType = "accrual"
+	// TransactionTypeRedemption списание баллов
+	TransactionTypeRedemption TransactionType = "redemption"
+)
+
+// LoyaltyTransaction запись журнала начислений и списаний баллов (append-only)
+type LoyaltyTransaction struct {
+	ID            int64
+	CardID        int64
+	Type          TransactionType
+	Points        int64
+	Amount        float64 // Сумма покупки (начисление) или стоимость списанных баллов в рублях (списание)
+	BalanceAfter  int64
+	CreatedBy     *int64
+	CreatedByRole ActorRole
+	CreatedAt     time.Time
+
+	// CreatedByService внутренний сервис, записавший операцию по API-ключу (CreatedBy при этом nil)
+	CreatedByService *string
+}
+
+// CreateLoyaltyTransactionInput входные данные для записи операции с баллами
+type CreateLoyaltyTransactionInput struct {
+	CardID        int64
+	Points        int64
+	Amount        float64
+	CreatedBy     *int64
+	CreatedByRole ActorRole
+
+	// CreatedByService внутренний сервис, записавший операцию по API-ключу (CreatedBy при этом nil)
+	CreatedByService *string
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// LoyaltyVisit визит (обслуживание) клиента по карте лояльности
+type LoyaltyVisit struct {
+	ID             int64
+	CardID         int64
+	Amount         float64
+	ServiceID      *int64
+	VisitedAt      time.Time
+	VisitNumber    int // Порядковый номер визита по карте (visits_count после записи)
+	RecordedBy     *int64
+	RecordedByRole ActorRole
+	CreatedAt      time.Time
+
+	// RecordedByService внутренний сервис, записавший визит по API-ключу (RecordedBy при этом nil)
+	RecordedByService *string
+}
+
+// CreateLoyaltyVisitInput входные данные для записи визита
+type CreateLoyaltyVisitInput struct {
+	CardID         int64
+	Amount         float64
+	ServiceID      *int64
+	VisitedAt      time.Time
+	RecordedBy     *int64
+	RecordedByRole ActorRole
+	// RecordedByService внутренний сервис, записавший визит по API-ключу (RecordedBy при этом nil)
+	RecordedByService *string
+	// Tiers уровни прогрессивной скидки для пересчёта скидки карты (nil - скидка не меняется)
+	Tiers []ProgressiveTier
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+// PointsConfig конфигурация накопительной системы (JSONB points_config)
+type PointsConfig struct {
+	// PointsPerRuble сколько баллов начисляется за 1 рубль покупки
+	PointsPerRuble float64 `json:"points_per_ruble"`
+	// RedemptionRate сколько рублей стоит 1 балл при списании
+	RedemptionRate float64 `json:"redemption_rate"`
+}
+
+// Validate проверяет корректность параметров накопительной системы
+func (c *PointsConfig) Validate() error {
+	if c.PointsPerRuble <= 0 {
+		return errors.New("points_per_ruble must be greater than 0")
+	}
+
+	if c.RedemptionRate <= 0 {
+		return errors.New("redemption_rate must be greater than 0")
+	}
+
+	return nil
+}
+
+// PointsForAmount вычисляет количество баллов за покупку (с округлением вниз)
+func (c *PointsConfig) PointsForAmount(amount float64) int64 {
+	return int64(math.Floor(amount * c.PointsPerRuble))
+}
+
+// RedemptionValue вычисляет стоимость баллов в рублях
+func (c *PointsConfig) RedemptionValue(points int64) float64 {
+	return math.Round(float64(points)*c.RedemptionRate*100) / 100
+}
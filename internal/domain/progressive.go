@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxProgressiveTiers максимальное количество уровней прогрессивной скидки
+	MaxProgressiveTiers = 20
+)
+
+// ProgressiveTier уровень прогрессивной скидки
+// Хранится в loyalty_configs.progressive_config в формате {"min_visits": 5, "discount": 10}
+type ProgressiveTier struct {
+	MinVisits          int     `json:"min_visits"`
+	DiscountPercentage float64 `json:"discount"`
+}
+
+// ProgressiveConfig конфигурация прогрессивной скидки (JSONB progressive_config)
+type ProgressiveConfig struct {
+	Tiers []ProgressiveTier `json:"tiers"`
+}
+
+// ProgressiveStatus положение карты в прогрессивной шкале
+type ProgressiveStatus struct {
+	// Level номер текущего уровня (начиная с 1)
+	Level       int
+	CurrentTier ProgressiveTier
+	// NextTier следующий уровень (nil, если достигнут максимальный)
+	NextTier *ProgressiveTier
+}
+
+// Validate проверяет корректность списка уровней
+// Уровни должны начинаться с 0 визитов, идти по возрастанию min_visits
+// и не уменьшать скидку при переходе на следующий уровень
+func (c *ProgressiveConfig) Validate() error {
+	if len(c.Tiers) == 0 {
+		return errors.New("progressive config must contain at least one tier")
+	}
+
+	if len(c.Tiers) > MaxProgressiveTiers {
+		return fmt.Errorf("progressive config must contain at most %d tiers", MaxProgressiveTiers)
+	}
+
+	if c.Tiers[0].MinVisits != 0 {
+		return errors.New("first tier must start from 0 visits")
+	}
+
+	for i, tier := range c.Tiers {
+		if tier.DiscountPercentage < 0 || tier.DiscountPercentage > 100 {
+			return fmt.Errorf("tier %d: discount percentage must be between 0 and 100", i+1)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := c.Tiers[i-1]
+		if tier.MinVisits <= prev.MinVisits {
+			return fmt.Errorf("tier %d: min_visits must be greater than in previous tier", i+1)
+		}
+		if tier.DiscountPercentage < prev.DiscountPercentage {
+			return fmt.Errorf("tier %d: discount percentage must not be lower than in previous tier", i+1)
+		}
+	}
+
+	return nil
+}
+
+// StatusFor определяет уровень карты по количеству визитов
+// Предполагается, что конфигурация прошла Validate
+func (c *ProgressiveConfig) StatusFor(visits int) ProgressiveStatus {
+	idx := 0
+	for i, tier := range c.Tiers {
+		if visits >= tier.MinVisits {
+			idx = i
+		}
+	}
+
+	status := ProgressiveStatus{
+		Level:       idx + 1,
+		CurrentTier: c.Tiers[idx],
+	}
+
+	if idx+1 < len(c.Tiers) {
+		next := c.Tiers[idx+1]
+		status.NextTier = &next
+	}
+
+	return status
+}
@@ -0,0 +1,25 @@
+package api_key
+
+import "errors"
+
+const (
+	// pqErrCodeUniqueViolation PostgreSQL код ошибки нарушения UNIQUE constraint
+	pqErrCodeUniqueViolation = "23505"
+)
+
+var (
+	// ErrKeyNotFound возвращается, когда действующий API-ключ не найден
+	ErrKeyNotFound = errors.New("repository.api_key: key not found")
+
+	// ErrKeyAlreadyExists возвращается, когда ключ с таким префиксом или хешем уже существует
+	ErrKeyAlreadyExists = errors.New("repository.api_key: key already exists")
+
+	// ErrBuildQuery возвращается при ошибке построения SQL запроса
+	ErrBuildQuery = errors.New("repository.api_key: failed to build SQL query")
+
+	// ErrExecQuery возвращается при ошибке выполнения SQL запроса
+	ErrExecQuery = errors.New("repository.api_key: failed to execute SQL query")
+
+	// ErrScanRow возвращается при ошибке сканирования строки из БД
+	ErrScanRow = errors.New("repository.api_key: failed to scan row")
+)
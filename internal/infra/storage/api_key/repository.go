@@ -0,0 +1,164 @@
+package api_key
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// keyColumns колонки api_keys в порядке сканирования scanKey
+var keyColumns = []string{"id", "name", "key_prefix", "scopes", "created_at", "revoked_at"}
+
+// Repository репозиторий API-ключей внутренних сервисов
+type Repository struct {
+	db DBExecutor
+}
+
+// NewRepository создает новый экземпляр репозитория API-ключей
+func NewRepository(db DBExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// Create сохраняет выпущенный ключ (только префикс и хеш)
+func (r *Repository) Create(ctx context.Context, input domain.CreateAPIKeyInput) (*domain.APIKey, error) {
+	query, args, err := psqlbuilder.Insert("api_keys").
+		Columns("name", "key_prefix", "key_hash", "scopes").
+		Values(input.Name, input.Prefix, input.Hash, pq.Array(scopeStrings(input.Scopes))).
+		Suffix("RETURNING " + strings.Join(keyColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - build insert query: %v", ErrBuildQuery, err)
+	}
+
+	key, err := scanKey(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqErrCodeUniqueViolation {
+			return nil, ErrKeyAlreadyExists
+		}
+		return nil, fmt.Errorf("%w: Create - insert key: %v", ErrExecQuery, err)
+	}
+
+	return key, nil
+}
+
+// GetActiveByHash получает действующий (не отозванный) ключ по хешу
+func (r *Repository) GetActiveByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	query, args, err := psqlbuilder.Select(keyColumns...).
+		From("api_keys").
+		Where(squirrel.Eq{"key_hash": hash, "revoked_at": nil}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetActiveByHash - build select query: %v", ErrBuildQuery, err)
+	}
+
+	key, err := scanKey(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetActiveByHash - scan key: %v", ErrScanRow, err)
+	}
+
+	return key, nil
+}
+
+// List возвращает все ключи, включая отозванные, от новых к старым
+func (r *Repository) List(ctx context.Context) ([]*domain.APIKey, error) {
+	query, args, err := psqlbuilder.Select(keyColumns...).
+		From("api_keys").
+		OrderBy("id DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: List - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: List - select keys: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	var keys []*domain.APIKey
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: List - scan key: %v", ErrScanRow, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: List - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return keys, nil
+}
+
+// RevokeByPrefix отзывает действующий ключ по префиксу
+func (r *Repository) RevokeByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	query, args, err := psqlbuilder.Update("api_keys").
+		Set("revoked_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"key_prefix": prefix, "revoked_at": nil}).
+		Suffix("RETURNING " + strings.Join(keyColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: RevokeByPrefix - build update query: %v", ErrBuildQuery, err)
+	}
+
+	key, err := scanKey(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: RevokeByPrefix - scan key: %v", ErrScanRow, err)
+	}
+
+	return key, nil
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanKey сканирует строку api_keys (колонки keyColumns) в domain модель
+func scanKey(row rowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes []string
+	var revokedAt sql.NullTime
+
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, pq.Array(&scopes), &key.CreatedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]domain.APIKeyScope, 0, len(scopes))
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, domain.APIKeyScope(scope))
+	}
+
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
+
+func scopeStrings(scopes []domain.APIKeyScope) []string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, string(scope))
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package audit_event
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// eventColumns колонки audit_events в порядке сканирования scanEvent
+var eventColumns = []string{
+	"id", "company_id", "entity_type", "entity_id", "action",
+	"actor_id", "actor_role", "actor_service", "before", "after", "changes", "created_at",
+}
+
+// Repository репозиторий журнала изменений
+type Repository struct {
+	db DBExecutor
+}
+
+// NewRepository создает новый экземпляр репозитория журнала изменений
+func NewRepository(db DBExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// Create записывает событие в журнал
+// Вызывается в транзакции изменения, чтобы событие и изменение фиксировались вместе
+func (r *Repository) Create(ctx context.Context, input domain.CreateAuditEventInput) (*domain.AuditEvent, error) {
+	query, args, err := psqlbuilder.Insert("audit_events").
+		Columns("company_id", "entity_type", "entity_id", "action", "actor_id", "actor_role", "actor_service", "before", "after", "changes").
+		Values(
+			input.CompanyID,
+			string(input.EntityType),
+			input.EntityID,
+			string(input.Action),
+			input.ActorID,
+			string(input.ActorRole),
+			input.ActorService,
+			jsonbValue(input.Before),
+			jsonbValue(input.After),
+			jsonbValue(input.Changes),
+		).
+		Suffix("RETURNING " + strings.Join(eventColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - build insert query: %v", ErrBuildQuery, err)
+	}
+
+	event, err := scanEvent(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - insert event: %v", ErrExecQuery, err)
+	}
+
+	return event, nil
+}
+
+// ListByCompany возвращает не более input.Limit событий компании от новых к старым
+func (r *Repository) ListByCompany(ctx context.Context, input domain.ListAuditEventsInput) ([]*domain.AuditEvent, error) {
+	builder := psqlbuilder.Select(eventColumns...).
+		From("audit_events").
+		Where(squirrel.Eq{"company_id": input.CompanyID}).
+		OrderBy("id DESC").
+		Limit(uint64(input.Limit))
+
+	if input.EntityType != nil {
+		builder = builder.Where(squirrel.Eq{"entity_type": string(*input.EntityType)})
+	}
+	if input.EntityID != nil {
+		builder = builder.Where(squirrel.Eq{"entity_id": *input.EntityID})
+	}
+	if input.BeforeID != nil {
+		builder = builder.Where(squirrel.Lt{"id": *input.BeforeID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListByCompany - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListByCompany - select events: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	events := make([]*domain.AuditEvent, 0, input.Limit)
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ListByCompany - scan event: %v", ErrScanRow, err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: ListByCompany - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return events, nil
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEvent сканирует строку audit_events (колонки eventColumns) в domain модель
+func scanEvent(row rowScanner) (*domain.AuditEvent, error) {
+	var event domain.AuditEvent
+	var entityType, action, actorRole string
+	var actorID sql.NullInt64
+	var actorService sql.NullString
+	var before, after, changes []byte
+
+	err := row.Scan(
+		&event.ID,
+		&event.CompanyID,
+		&entityType,
+		&event.EntityID,
+		&action,
+		&actorID,
+		&actorRole,
+		&actorService,
+		&before,
+		&after,
+		&changes,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.EntityType = domain.AuditEntityType(entityType)
+	event.Action = domain.AuditAction(action)
+	event.ActorRole = domain.ActorRole(actorRole)
+	event.Before = before
+	event.After = after
+	event.Changes = changes
+
+	if actorID.Valid {
+		event.ActorID = &actorID.Int64
+	}
+	if actorService.Valid {
+		event.ActorService = &actorService.String
+	}
+
+	return &event, nil
+}
+
+// jsonbValue передаёт JSON в колонку JSONB (пустое значение - NULL)
+func jsonbValue(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,17 @@
+package loyalty_campaign
+
+import "errors"
+
+var (
+	// ErrCampaignNotFound возвращается, когда акция не найдена в БД
+	ErrCampaignNotFound = errors.New("repository.loyalty_campaign: campaign not found")
+
+	// ErrBuildQuery возвращается при ошибке построения SQL запроса
+	ErrBuildQuery = errors.New("repository.loyalty_campaign: failed to build SQL query")
+
+	// ErrExecQuery возвращается при ошибке выполнения SQL запроса
+	ErrExecQuery = errors.New("repository.loyalty_campaign: failed to execute SQL query")
+
+	// ErrScanRow возвращается при ошибке сканирования строки из БД
+	ErrScanRow = errors.New("repository.loyalty_campaign: failed to scan row")
+)
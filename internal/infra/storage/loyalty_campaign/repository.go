@@ -0,0 +1,186 @@
+package loyalty_campaign
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// campaignColumns колонки loyalty_campaigns в порядке сканирования scanCampaign
+// Интервал времени читается в формате HH:MM (TIME в PostgreSQL возвращается с секундами)
+var campaignColumns = []string{
+	"id", "company_id", "name", "starts_at", "ends_at", "weekdays",
+	"to_char(window_start, 'HH24:MI')", "to_char(window_end, 'HH24:MI')", "timezone",
+	"discount_percentage", "points_multiplier", "stacking_rule", "max_discount_percentage",
+	"created_by", "created_by_role", "created_at",
+}
+
+// Repository репозиторий промо-акций компаний
+type Repository struct {
+	db DBExecutor
+}
+
+// NewRepository создает новый экземпляр репозитория акций
+func NewRepository(db DBExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// Create сохраняет новую акцию
+func (r *Repository) Create(ctx context.Context, campaign *domain.LoyaltyCampaign) (*domain.LoyaltyCampaign, error) {
+	weekdays := make([]int64, 0, len(campaign.Weekdays))
+	for _, day := range campaign.Weekdays {
+		weekdays = append(weekdays, int64(day))
+	}
+
+	query, args, err := psqlbuilder.Insert("loyalty_campaigns").
+		Columns(
+			"company_id", "name", "starts_at", "ends_at", "weekdays", "window_start", "window_end", "timezone",
+			"discount_percentage", "points_multiplier", "stacking_rule", "max_discount_percentage",
+			"created_by", "created_by_role",
+		).
+		Values(
+			campaign.CompanyID, campaign.Name, campaign.StartsAt, campaign.EndsAt, pq.Array(weekdays),
+			campaign.WindowStart, campaign.WindowEnd, campaign.Timezone,
+			campaign.DiscountPercentage, campaign.PointsMultiplier, string(campaign.StackingRule), campaign.MaxDiscountPercentage,
+			campaign.CreatedBy, string(campaign.CreatedByRole),
+		).
+		Suffix("RETURNING " + strings.Join(campaignColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - build insert query: %v", ErrBuildQuery, err)
+	}
+
+	created, err := scanCampaign(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - insert campaign: %v", ErrExecQuery, err)
+	}
+
+	return created, nil
+}
+
+// ListByCompany возвращает акции компании в порядке начала периода
+func (r *Repository) ListByCompany(ctx context.Context, input domain.ListLoyaltyCampaignsInput) ([]*domain.LoyaltyCampaign, error) {
+	builder := psqlbuilder.Select(campaignColumns...).
+		From("loyalty_campaigns").
+		Where(squirrel.Eq{"company_id": input.CompanyID}).
+		OrderBy("starts_at", "id")
+
+	if input.ActiveAt != nil {
+		builder = builder.
+			Where(squirrel.LtOrEq{"starts_at": *input.ActiveAt}).
+			Where(squirrel.Gt{"ends_at": *input.ActiveAt})
+	}
+	if input.EndsAfter != nil {
+		builder = builder.Where(squirrel.Gt{"ends_at": *input.EndsAfter})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListByCompany - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListByCompany - select campaigns: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	var campaigns []*domain.LoyaltyCampaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ListByCompany - scan campaign: %v", ErrScanRow, err)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: ListByCompany - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return campaigns, nil
+}
+
+// Delete удаляет акцию компании и возвращает её последнее состояние
+func (r *Repository) Delete(ctx context.Context, companyID, campaignID int64) (*domain.LoyaltyCampaign, error) {
+	query, args, err := psqlbuilder.Delete("loyalty_campaigns").
+		Where(squirrel.Eq{"id": campaignID, "company_id": companyID}).
+		Suffix("RETURNING " + strings.Join(campaignColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Delete - build delete query: %v", ErrBuildQuery, err)
+	}
+
+	campaign, err := scanCampaign(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrCampaignNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Delete - scan campaign: %v", ErrScanRow, err)
+	}
+
+	return campaign, nil
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCampaign сканирует строку loyalty_campaigns (колонки campaignColumns) в domain модель
+func scanCampaign(row rowScanner) (*domain.LoyaltyCampaign, error) {
+	var campaign domain.LoyaltyCampaign
+	var weekdays pq.Int64Array
+	var windowStart, windowEnd sql.NullString
+	var stackingRule, createdByRole string
+	var maxDiscountPercentage sql.NullFloat64
+
+	err := row.Scan(
+		&campaign.ID,
+		&campaign.CompanyID,
+		&campaign.Name,
+		&campaign.StartsAt,
+		&campaign.EndsAt,
+		&weekdays,
+		&windowStart,
+		&windowEnd,
+		&campaign.Timezone,
+		&campaign.DiscountPercentage,
+		&campaign.PointsMultiplier,
+		&stackingRule,
+		&maxDiscountPercentage,
+		&campaign.CreatedBy,
+		&createdByRole,
+		&campaign.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.StackingRule = domain.CampaignStackingRule(stackingRule)
+	campaign.CreatedByRole = domain.ActorRole(createdByRole)
+	campaign.WindowStart = types.TimeString(windowStart.String)
+	campaign.WindowEnd = types.TimeString(windowEnd.String)
+
+	campaign.Weekdays = make([]int, 0, len(weekdays))
+	for _, day := range weekdays {
+		campaign.Weekdays = append(campaign.Weekdays, int(day))
+	}
+
+	if maxDiscountPercentage.Valid {
+		campaign.MaxDiscountPercentage = &maxDiscountPercentage.Float64
+	}
+
+	return &campaign, nil
+}
@@ -4,14 +4,46 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
 	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/lib/pq"
 )
 
+// cardColumns колонки loyalty_cards в порядке сканирования scanCard
+var cardColumns = []string{
+	"id", "user_id", "company_id", "card_type", "status", "discount_percentage",
+	"visits_count", "points_balance", "status_reason", "status_changed_at", "status_changed_by",
+	"expires_at", "created_at", "updated_at",
+}
+
+// cardSortColumn колонка сортировки и её SQL тип для сравнения со значением курсора
+type cardSortColumn struct {
+	column  string
+	sqlType string
+}
+
+// cardSortColumns поддерживаемые поля сортировки списка карт компании
+var cardSortColumns = map[domain.CardSortField]cardSortColumn{
+	domain.CardSortCreatedAt:     {column: "created_at", sqlType: "TIMESTAMPTZ"},
+	domain.CardSortVisitsCount:   {column: "visits_count", sqlType: "INTEGER"},
+	domain.CardSortPointsBalance: {column: "points_balance", sqlType: "BIGINT"},
+}
+
+// effectiveStatusEq условие на статус карты с учётом срока действия, как в domain.LoyaltyCard.EffectiveStatus:
+// активная или приостановленная карта с наступившим expires_at считается expired
+// table - имя таблицы loyalty_cards в запросе
+func effectiveStatusEq(table string, status domain.CardStatus) squirrel.Sqlizer {
+	return squirrel.Expr(fmt.Sprintf(
+		"(CASE WHEN %[1]s.expires_at <= NOW() AND %[1]s.status IN (?, ?) THEN ? ELSE %[1]s.status END) = ?", table),
+		string(domain.CardStatusActive), string(domain.CardStatusSuspended), string(domain.CardStatusExpired), string(status),
+	)
+}
+
 // Repository репозиторий для работы с картами лояльности
 type Repository struct {
 	db DBExecutor
@@ -22,11 +54,31 @@ func NewRepository(db DBExecutor) *Repository {
 	return &Repository{db: db}
 }
 
+// GetByID получает карту лояльности по ID
+func (r *Repository) GetByID(ctx context.Context, cardID int64) (*domain.LoyaltyCard, error) {
+	query, args, err := psqlbuilder.Select(cardColumns...).
+		From("loyalty_cards").
+		Where(squirrel.Eq{"id": cardID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetByID - build select query: %v", ErrBuildQuery, err)
+	}
+
+	card, err := scanCard(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetByID - scan card: %v", ErrScanRow, err)
+	}
+
+	return card, nil
+}
+
 // GetByUserAndCompany получает карту лояльности клиента в компании
 func (r *Repository) GetByUserAndCompany(ctx context.Context, userID, companyID int64) (*domain.LoyaltyCard, error) {
-	query, args, err := psqlbuilder.Select(
-		"id", "user_id", "company_id", "card_type", "status", "discount_percentage", "created_at", "updated_at",
-	).
+	query, args, err := psqlbuilder.Select(cardColumns...).
 		From("loyalty_cards").
 		Where(squirrel.Eq{"user_id": userID, "company_id": companyID}).
 		ToSql()
@@ -35,21 +87,7 @@ func (r *Repository) GetByUserAndCompany(ctx context.Context, userID, companyID
 		return nil, fmt.Errorf("%w: GetByUserAndCompany - build select query: %v", ErrBuildQuery, err)
 	}
 
-	var card domain.LoyaltyCard
-	var cardType, status string
-	var createdAt, updatedAt sql.NullTime
-
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
-		&card.ID,
-		&card.UserID,
-		&card.CompanyID,
-		&cardType,
-		&status,
-		&card.DiscountPercentage,
-		&createdAt,
-		&updatedAt,
-	)
-
+	card, err := scanCard(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err == sql.ErrNoRows {
 		return nil, ErrCardNotFound
 	}
@@ -57,19 +95,14 @@ func (r *Repository) GetByUserAndCompany(ctx context.Context, userID, companyID
 		return nil, fmt.Errorf("%w: GetByUserAndCompany - scan card: %v", ErrScanRow, err)
 	}
 
-	card.CardType = domain.CardType(cardType)
-	card.Status = domain.CardStatus(status)
-	card.CreatedAt = createdAt.Time
-	card.UpdatedAt = updatedAt.Time
-
-	return &card, nil
+	return card, nil
 }
 
 // Create создает новую карту лояльности
 func (r *Repository) Create(ctx context.Context, card *domain.LoyaltyCard) (*domain.LoyaltyCard, error) {
 	query, args, err := psqlbuilder.Insert("loyalty_cards").
-		Columns("user_id", "company_id", "card_type", "status", "discount_percentage").
-		Values(card.UserID, card.CompanyID, string(card.CardType), string(card.Status), card.DiscountPercentage).
+		Columns("user_id", "company_id", "card_type", "status", "discount_percentage", "expires_at").
+		Values(card.UserID, card.CompanyID, string(card.CardType), string(card.Status), card.DiscountPercentage, card.ExpiresAt).
 		Suffix("RETURNING id, created_at, updated_at").
 		ToSql()
 
@@ -80,7 +113,7 @@ func (r *Repository) Create(ctx context.Context, card *domain.LoyaltyCard) (*dom
 	var cardID int64
 	var createdAt, updatedAt sql.NullTime
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&cardID, &createdAt, &updatedAt)
+	err = dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&cardID, &createdAt, &updatedAt)
 	if err != nil {
 		// Проверяем на duplicate key (UNIQUE constraint violation)
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqErrCodeUniqueViolation {
@@ -109,6 +142,10 @@ func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyCardI
 		return nil, fmt.Errorf("%w: Update - either card_id or (user_id + company_id) must be provided", ErrBuildQuery)
 	}
 
+	if input.ExpectedStatus != nil {
+		updateBuilder = updateBuilder.Where(squirrel.Eq{"status": string(*input.ExpectedStatus)})
+	}
+
 	// Добавляем только те поля, которые нужно обновить
 	hasUpdates := false
 
@@ -118,7 +155,12 @@ func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyCardI
 	}
 
 	if input.Status != nil {
-		updateBuilder = updateBuilder.Set("status", string(*input.Status))
+		updateBuilder = updateBuilder.
+			Set("status", string(*input.Status)).
+			Set("status_reason", input.StatusReason).
+			Set("status_changed_at", squirrel.Expr("NOW()")).
+			Set("status_changed_by", input.StatusChangedBy).
+			Set("status_changed_by_role", input.StatusChangedByRole)
 		hasUpdates = true
 	}
 
@@ -127,38 +169,356 @@ func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyCardI
 		hasUpdates = true
 	}
 
+	if input.RenewExpiry {
+		updateBuilder = updateBuilder.Set("expires_at", input.ExpiresAt)
+		hasUpdates = true
+	}
+
 	if !hasUpdates {
 		return nil, fmt.Errorf("%w: Update - no fields to update", ErrBuildQuery)
 	}
 
 	// Добавляем RETURNING для получения обновлённых данных
-	updateBuilder = updateBuilder.Suffix("RETURNING id, user_id, company_id, card_type, status, discount_percentage, created_at, updated_at")
+	updateBuilder = updateBuilder.Suffix("RETURNING " + strings.Join(cardColumns, ", "))
 
 	query, args, err := updateBuilder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%w: Update - build update query: %v", ErrBuildQuery, err)
 	}
 
+	card, err := scanCard(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Update - scan updated card: %v", ErrScanRow, err)
+	}
+
+	return card, nil
+}
+
+// ListByUser возвращает карты клиента по всем компаниям, упорядоченные по ID
+// Карты компаний с выключенной или не настроенной программой лояльности не возвращаются
+func (r *Repository) ListByUser(ctx context.Context, input domain.ListUserCardsInput) ([]*domain.LoyaltyCard, error) {
+	columns := make([]string, 0, len(cardColumns))
+	for _, column := range cardColumns {
+		columns = append(columns, "loyalty_cards."+column)
+	}
+
+	selectBuilder := psqlbuilder.Select(columns...).
+		From("loyalty_cards").
+		Join("loyalty_configs ON loyalty_configs.company_id = loyalty_cards.company_id AND loyalty_configs.is_enabled").
+		Where(squirrel.Eq{"loyalty_cards.user_id": input.UserID}).
+		Where(squirrel.Gt{"loyalty_cards.id": input.AfterID}).
+		OrderBy("loyalty_cards.id").
+		Limit(uint64(input.Limit))
+
+	if input.Status != nil {
+		selectBuilder = selectBuilder.Where(effectiveStatusEq("loyalty_cards", *input.Status))
+	}
+
+	query, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListByUser - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListByUser - select cards: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	cards := make([]*domain.LoyaltyCard, 0, input.Limit)
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ListByUser - scan card: %v", ErrScanRow, err)
+		}
+		cards = append(cards, card)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: ListByUser - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return cards, nil
+}
+
+// ListByCompany возвращает страницу карт компании с фильтрами и keyset-пагинацией
+func (r *Repository) ListByCompany(ctx context.Context, input domain.ListCompanyCardsInput) ([]*domain.LoyaltyCard, error) {
+	cards := make([]*domain.LoyaltyCard, 0, input.Limit)
+
+	err := r.iterateByCompany(ctx, "ListByCompany", input, func(card *domain.LoyaltyCard) error {
+		cards = append(cards, card)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// StreamByCompany передаёт карты компании в fn по одной, не загружая выборку в память целиком
+// Ошибка fn прерывает выборку и возвращается как есть
+func (r *Repository) StreamByCompany(ctx context.Context, input domain.ListCompanyCardsInput, fn func(card *domain.LoyaltyCard) error) error {
+	return r.iterateByCompany(ctx, "StreamByCompany", input, fn)
+}
+
+// iterateByCompany выполняет выборку карт компании и вызывает fn для каждой строки
+// method - имя вызывающего метода для текста ошибок
+func (r *Repository) iterateByCompany(ctx context.Context, method string, input domain.ListCompanyCardsInput, fn func(card *domain.LoyaltyCard) error) error {
+	sort, ok := cardSortColumns[input.SortBy]
+	if !ok {
+		return fmt.Errorf("%w: %s - unsupported sort field %q", ErrBuildQuery, method, input.SortBy)
+	}
+
+	direction, comparison := "ASC", ">"
+	if input.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	selectBuilder := psqlbuilder.Select(cardColumns...).
+		From("loyalty_cards").
+		Where(squirrel.Eq{"company_id": input.CompanyID}).
+		OrderBy(sort.column+" "+direction, "id "+direction)
+
+	if input.Status != nil {
+		selectBuilder = selectBuilder.Where(effectiveStatusEq("loyalty_cards", *input.Status))
+	}
+	if input.CardType != nil {
+		selectBuilder = selectBuilder.Where(squirrel.Eq{"card_type": string(*input.CardType)})
+	}
+	if input.CreatedFrom != nil {
+		selectBuilder = selectBuilder.Where(squirrel.GtOrEq{"created_at": *input.CreatedFrom})
+	}
+	if input.CreatedTo != nil {
+		selectBuilder = selectBuilder.Where(squirrel.Lt{"created_at": *input.CreatedTo})
+	}
+	if input.After != nil {
+		selectBuilder = selectBuilder.Where(squirrel.Expr(
+			fmt.Sprintf("(%s, id) %s (?::%s, ?::BIGINT)", sort.column, comparison, sort.sqlType),
+			input.After.Value, input.After.ID,
+		))
+	}
+	if input.Limit > 0 {
+		selectBuilder = selectBuilder.Limit(uint64(input.Limit))
+	}
+
+	query, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: %s - build select query: %v", ErrBuildQuery, method, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %s - select cards: %v", ErrExecQuery, method, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return fmt.Errorf("%w: %s - scan card: %v", ErrScanRow, method, err)
+		}
+		if err := fn(card); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %s - iterate rows: %v", ErrScanRow, method, err)
+	}
+
+	return nil
+}
+
+// CountByStatus возвращает количество карт компании в разрезе статусов
+func (r *Repository) CountByStatus(ctx context.Context, companyID int64) (map[domain.CardStatus]int64, error) {
+	query, args, err := psqlbuilder.Select("status", "COUNT(*)").
+		From("loyalty_cards").
+		Where(squirrel.Eq{"company_id": companyID}).
+		GroupBy("status").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: CountByStatus - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CountByStatus - select counts: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.CardStatus]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("%w: CountByStatus - scan count: %v", ErrScanRow, err)
+		}
+		counts[domain.CardStatus(status)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: CountByStatus - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return counts, nil
+}
+
+// UpdateDiscountForCompany обновляет тип и скидку выпущенных карт компании
+// При OnlyIncrease скидка карты не снижается (политика never_lower), а тип карты обновляется у всех карт
+// Выполняется в транзакции из контекста, если она есть
+func (r *Repository) UpdateDiscountForCompany(ctx context.Context, input domain.BulkUpdateCardDiscountInput) (int64, error) {
+	updateBuilder := psqlbuilder.Update("loyalty_cards").
+		Set("card_type", string(input.CardType)).
+		Where(squirrel.Eq{"company_id": input.CompanyID})
+
+	if input.OnlyIncrease {
+		updateBuilder = updateBuilder.
+			Set("discount_percentage", squirrel.Expr("GREATEST(discount_percentage, ?)", input.DiscountPercentage)).
+			Where(squirrel.Or{
+				squirrel.Lt{"discount_percentage": input.DiscountPercentage},
+				squirrel.NotEq{"card_type": string(input.CardType)},
+			})
+	} else {
+		updateBuilder = updateBuilder.
+			Set("discount_percentage", input.DiscountPercentage).
+			Where(squirrel.Or{
+				squirrel.NotEq{"discount_percentage": input.DiscountPercentage},
+				squirrel.NotEq{"card_type": string(input.CardType)},
+			})
+	}
+
+	query, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%w: UpdateDiscountForCompany - build update query: %v", ErrBuildQuery, err)
+	}
+
+	result, err := dbmetrics.GetExecutor(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%w: UpdateDiscountForCompany - update cards: %v", ErrExecQuery, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: UpdateDiscountForCompany - rows affected: %v", ErrExecQuery, err)
+	}
+
+	return affected, nil
+}
+
+// LockOverdue возвращает не более limit карт с истёкшим сроком действия, ещё не переведённых в expired,
+// и блокирует их до конца транзакции. Карты, заблокированные другой транзакцией, пропускаются
+// (FOR UPDATE SKIP LOCKED), поэтому несколько экземпляров сервиса могут обрабатывать карты параллельно
+func (r *Repository) LockOverdue(ctx context.Context, limit int) ([]*domain.LoyaltyCard, error) {
+	query, args, err := psqlbuilder.Select(cardColumns...).
+		From("loyalty_cards").
+		Where(squirrel.Eq{"status": []string{string(domain.CardStatusActive), string(domain.CardStatusSuspended)}}).
+		Where(squirrel.Expr("expires_at <= NOW()")).
+		OrderBy("expires_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: LockOverdue - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: LockOverdue - select cards: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	cards := make([]*domain.LoyaltyCard, 0, limit)
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: LockOverdue - scan card: %v", ErrScanRow, err)
+		}
+		cards = append(cards, card)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: LockOverdue - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return cards, nil
+}
+
+// Expire переводит карты в expired от имени фонового процесса и возвращает их после изменения
+// Вызывается в транзакции после LockOverdue для заблокированных ею карт
+func (r *Repository) Expire(ctx context.Context, cardIDs []int64, reason string) ([]*domain.LoyaltyCard, error) {
+	query, args, err := psqlbuilder.Update("loyalty_cards").
+		Set("status", string(domain.CardStatusExpired)).
+		Set("status_reason", reason).
+		Set("status_changed_at", squirrel.Expr("NOW()")).
+		Set("status_changed_by", nil).
+		Set("status_changed_by_role", string(domain.ActorRoleSystem)).
+		Where(squirrel.Eq{"id": cardIDs}).
+		Suffix("RETURNING " + strings.Join(cardColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Expire - build update query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Expire - update cards: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	cards := make([]*domain.LoyaltyCard, 0, len(cardIDs))
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: Expire - scan card: %v", ErrScanRow, err)
+		}
+		cards = append(cards, card)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: Expire - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return cards, nil
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCard сканирует строку loyalty_cards (колонки cardColumns) в domain модель
+func scanCard(row rowScanner) (*domain.LoyaltyCard, error) {
 	var card domain.LoyaltyCard
 	var cardType, status string
-	var createdAt, updatedAt sql.NullTime
+	var statusReason sql.NullString
+	var statusChangedAt, expiresAt, createdAt, updatedAt sql.NullTime
+	var statusChangedBy sql.NullInt64
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+	err := row.Scan(
 		&card.ID,
 		&card.UserID,
 		&card.CompanyID,
 		&cardType,
 		&status,
 		&card.DiscountPercentage,
+		&card.VisitsCount,
+		&card.PointsBalance,
+		&statusReason,
+		&statusChangedAt,
+		&statusChangedBy,
+		&expiresAt,
 		&createdAt,
 		&updatedAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, ErrCardNotFound
-	}
 	if err != nil {
-		return nil, fmt.Errorf("%w: Update - scan updated card: %v", ErrScanRow, err)
+		return nil, err
 	}
 
 	card.CardType = domain.CardType(cardType)
@@ -166,5 +526,18 @@ func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyCardI
 	card.CreatedAt = createdAt.Time
 	card.UpdatedAt = updatedAt.Time
 
+	if statusReason.Valid {
+		card.StatusReason = &statusReason.String
+	}
+	if statusChangedAt.Valid {
+		card.StatusChangedAt = &statusChangedAt.Time
+	}
+	if statusChangedBy.Valid {
+		card.StatusChangedBy = &statusChangedBy.Int64
+	}
+	if expiresAt.Valid {
+		card.ExpiresAt = &expiresAt.Time
+	}
+
 	return &card, nil
 }
@@ -11,6 +11,15 @@ var (
 	// ErrConfigNotFound возвращается, когда конфигурация лояльности не найдена в БД
 	ErrConfigNotFound = errors.New("repository.loyalty_config: config not found")
 
+	// ErrVersionNotFound возвращается, когда версия конфигурации не найдена в БД
+	ErrVersionNotFound = errors.New("repository.loyalty_config: config version not found")
+
+	// ErrScheduleNotFound возвращается, когда ожидающее изменение конфигурации не найдено в БД
+	ErrScheduleNotFound = errors.New("repository.loyalty_config: config schedule not found")
+
+	// ErrScheduleAlreadyExists возвращается, когда на этот момент у компании уже запланировано изменение
+	ErrScheduleAlreadyExists = errors.New("repository.loyalty_config: config schedule already exists")
+
 	// ErrConfigAlreadyExists возвращается, когда конфигурация уже существует
 	ErrConfigAlreadyExists = errors.New("repository.loyalty_config: config already exists")
 
@@ -0,0 +1,35 @@
+package loyalty_config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// encodeJSONB сериализует значение для записи в JSONB колонку
+// Возвращает interface{}, чтобы nil значение записывалось как NULL, а не как пустая строка
+func encodeJSONB[T any](v *T) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal jsonb: %v", err)
+	}
+
+	return string(data), nil
+}
+
+// decodeJSONB десериализует значение JSONB колонки (NULL -> nil)
+func decodeJSONB[T any](data []byte) (*T, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("unmarshal jsonb: %v", err)
+	}
+
+	return &v, nil
+}
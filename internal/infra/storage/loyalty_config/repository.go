@@ -4,14 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
 	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/lib/pq"
 )
 
+// configColumns колонки loyalty_configs в порядке сканирования scanConfig
+var configColumns = []string{
+	"id", "company_id", "card_type", "is_enabled", "discount_percentage",
+	"progressive_config", "points_config", "card_validity_days", "discount_update_policy",
+	"created_at", "updated_at",
+}
+
 // Repository репозиторий для работы с конфигурациями программ лояльности
 type Repository struct {
 	db DBExecutor
@@ -24,10 +33,7 @@ func NewRepository(db DBExecutor) *Repository {
 
 // GetByCompanyID получает конфигурацию программы лояльности компании
 func (r *Repository) GetByCompanyID(ctx context.Context, companyID int64) (*domain.LoyaltyConfig, error) {
-	query, args, err := psqlbuilder.Select(
-		"id", "company_id", "card_type", "is_enabled", "discount_percentage",
-		"progressive_config", "points_config", "created_at", "updated_at",
-	).
+	query, args, err := psqlbuilder.Select(configColumns...).
 		From("loyalty_configs").
 		Where(squirrel.Eq{"company_id": companyID}).
 		ToSql()
@@ -36,24 +42,7 @@ func (r *Repository) GetByCompanyID(ctx context.Context, companyID int64) (*doma
 		return nil, fmt.Errorf("%w: GetByCompanyID - build select query: %v", ErrBuildQuery, err)
 	}
 
-	var config domain.LoyaltyConfig
-	var cardType string
-	var createdAt, updatedAt sql.NullTime
-	var discountPercentage sql.NullFloat64
-	var progressiveConfig, pointsConfig []byte
-
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
-		&config.ID,
-		&config.CompanyID,
-		&cardType,
-		&config.IsEnabled,
-		&discountPercentage,
-		&progressiveConfig,
-		&pointsConfig,
-		&createdAt,
-		&updatedAt,
-	)
-
+	config, err := scanConfig(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err == sql.ErrNoRows {
 		return nil, ErrConfigNotFound
 	}
@@ -61,32 +50,89 @@ func (r *Repository) GetByCompanyID(ctx context.Context, companyID int64) (*doma
 		return nil, fmt.Errorf("%w: GetByCompanyID - scan config: %v", ErrScanRow, err)
 	}
 
-	config.CardType = domain.CardType(cardType)
-	config.CreatedAt = createdAt.Time
-	config.UpdatedAt = updatedAt.Time
+	return config, nil
+}
 
-	if discountPercentage.Valid {
-		config.DiscountPercentage = &discountPercentage.Float64
+// GetByCompanyIDs получает конфигурации нескольких компаний, ключ - ID компании
+// Компании без конфигурации в результат не попадают
+func (r *Repository) GetByCompanyIDs(ctx context.Context, companyIDs []int64) (map[int64]*domain.LoyaltyConfig, error) {
+	configs := make(map[int64]*domain.LoyaltyConfig, len(companyIDs))
+	if len(companyIDs) == 0 {
+		return configs, nil
+	}
+
+	query, args, err := psqlbuilder.Select(configColumns...).
+		From("loyalty_configs").
+		Where(squirrel.Eq{"company_id": companyIDs}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetByCompanyIDs - build select query: %v", ErrBuildQuery, err)
 	}
 
-	if len(progressiveConfig) > 0 {
-		config.ProgressiveConfig = progressiveConfig
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetByCompanyIDs - select configs: %v", ErrExecQuery, err)
 	}
+	defer rows.Close()
 
-	if len(pointsConfig) > 0 {
-		config.PointsConfig = pointsConfig
+	for rows.Next() {
+		config, err := scanConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: GetByCompanyIDs - scan config: %v", ErrScanRow, err)
+		}
+		configs[config.CompanyID] = config
 	}
 
-	return &config, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: GetByCompanyIDs - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return configs, nil
+}
+
+// LockCompany берёт транзакционную advisory-блокировку на конфигурацию компании
+// Блокировка держится до конца транзакции из контекста и сериализует чтение-и-запись
+// конфигурации одной компании, в том числе когда строки ещё нет. Вне транзакции бесполезна
+func (r *Repository) LockCompany(ctx context.Context, companyID int64) error {
+	query, args, err := psqlbuilder.Select().
+		Column(squirrel.Expr("pg_advisory_xact_lock(hashtextextended(?, ?::BIGINT))", "loyalty_configs", companyID)).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("%w: LockCompany - build lock query: %v", ErrBuildQuery, err)
+	}
+
+	if _, err := dbmetrics.GetExecutor(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%w: LockCompany - acquire lock: %v", ErrExecQuery, err)
+	}
+
+	return nil
 }
 
 // Create создает новую конфигурацию программы лояльности
-// Пока что поддерживается только discount_percentage для fixed_discount типа
-// В будущем добавятся progressive_config и points_config для других типов
+// Для fixed_discount заполняется discount_percentage, для progressive_discount - progressive_config,
+// для points_based - points_config
 func (r *Repository) Create(ctx context.Context, input domain.CreateLoyaltyConfigInput) (*domain.LoyaltyConfig, error) {
+	progressiveConfig, err := encodeJSONB(input.ProgressiveConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - progressive_config: %v", ErrBuildQuery, err)
+	}
+
+	pointsConfig, err := encodeJSONB(input.PointsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - points_config: %v", ErrBuildQuery, err)
+	}
+
 	query, args, err := psqlbuilder.Insert("loyalty_configs").
-		Columns("company_id", "card_type", "is_enabled", "discount_percentage").
-		Values(input.CompanyID, string(domain.CardTypeFixedDiscount), input.IsEnabled, input.DiscountPercentage).
+		Columns(
+			"company_id", "card_type", "is_enabled", "discount_percentage", "progressive_config", "points_config",
+			"card_validity_days", "discount_update_policy", "updated_by", "updated_by_role",
+		).
+		Values(
+			input.CompanyID, string(input.CardType), input.IsEnabled, input.DiscountPercentage, progressiveConfig, pointsConfig,
+			validityDays(input.CardValidityDays), string(input.DiscountUpdatePolicy), input.UpdatedBy, string(input.UpdatedByRole),
+		).
 		Suffix("RETURNING id, created_at, updated_at").
 		ToSql()
 
@@ -97,7 +143,7 @@ func (r *Repository) Create(ctx context.Context, input domain.CreateLoyaltyConfi
 	var configID int64
 	var createdAt, updatedAt sql.NullTime
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&configID, &createdAt, &updatedAt)
+	err = dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&configID, &createdAt, &updatedAt)
 	if err != nil {
 		// Проверяем на duplicate key (UNIQUE constraint violation на company_id)
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqErrCodeUniqueViolation {
@@ -107,24 +153,34 @@ func (r *Repository) Create(ctx context.Context, input domain.CreateLoyaltyConfi
 	}
 
 	return &domain.LoyaltyConfig{
-		ID:                 configID,
-		CompanyID:          input.CompanyID,
-		CardType:           domain.CardTypeFixedDiscount,
-		IsEnabled:          input.IsEnabled,
-		DiscountPercentage: &input.DiscountPercentage,
-		CreatedAt:          createdAt.Time,
-		UpdatedAt:          updatedAt.Time,
+		ID:                   configID,
+		CompanyID:            input.CompanyID,
+		CardType:             input.CardType,
+		IsEnabled:            input.IsEnabled,
+		DiscountPercentage:   input.DiscountPercentage,
+		ProgressiveConfig:    input.ProgressiveConfig,
+		PointsConfig:         input.PointsConfig,
+		CardValidityDays:     input.CardValidityDays,
+		DiscountUpdatePolicy: input.DiscountUpdatePolicy,
+		CreatedAt:            createdAt.Time,
+		UpdatedAt:            updatedAt.Time,
 	}, nil
 }
 
 // Update обновляет конфигурацию программы лояльности
-// Поддерживается обновление is_enabled и discount_percentage
+// Поддерживается обновление card_type, is_enabled, discount_percentage, progressive_config, points_config
+// card_validity_days и discount_update_policy
 func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyConfigInput) (*domain.LoyaltyConfig, error) {
 	updateBuilder := psqlbuilder.Update("loyalty_configs").
 		Where(squirrel.Eq{"company_id": input.CompanyID})
 
 	hasUpdates := false
 
+	if input.CardType != nil {
+		updateBuilder = updateBuilder.Set("card_type", string(*input.CardType))
+		hasUpdates = true
+	}
+
 	if input.IsEnabled != nil {
 		updateBuilder = updateBuilder.Set("is_enabled", *input.IsEnabled)
 		hasUpdates = true
@@ -135,25 +191,152 @@ func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyConfi
 		hasUpdates = true
 	}
 
+	if input.ProgressiveConfig != nil {
+		progressiveConfig, err := encodeJSONB(input.ProgressiveConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%w: Update - progressive_config: %v", ErrBuildQuery, err)
+		}
+		updateBuilder = updateBuilder.Set("progressive_config", progressiveConfig)
+		hasUpdates = true
+	}
+
+	if input.PointsConfig != nil {
+		pointsConfig, err := encodeJSONB(input.PointsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%w: Update - points_config: %v", ErrBuildQuery, err)
+		}
+		updateBuilder = updateBuilder.Set("points_config", pointsConfig)
+		hasUpdates = true
+	}
+
+	if input.CardValidityDays != nil {
+		updateBuilder = updateBuilder.Set("card_validity_days", validityDays(*input.CardValidityDays))
+		hasUpdates = true
+	}
+
+	if input.DiscountUpdatePolicy != nil {
+		updateBuilder = updateBuilder.Set("discount_update_policy", string(*input.DiscountUpdatePolicy))
+		hasUpdates = true
+	}
+
 	if !hasUpdates {
 		return nil, fmt.Errorf("%w: Update - no fields to update", ErrBuildQuery)
 	}
 
+	// Автор изменения сохраняется вместе с любым обновлением
+	updateBuilder = updateBuilder.
+		Set("updated_by", input.UpdatedBy).
+		Set("updated_by_role", string(input.UpdatedByRole))
+
 	// Добавляем RETURNING для получения обновлённых данных
-	updateBuilder = updateBuilder.Suffix("RETURNING id, company_id, card_type, is_enabled, discount_percentage, progressive_config, points_config, created_at, updated_at")
+	updateBuilder = updateBuilder.Suffix("RETURNING " + strings.Join(configColumns, ", "))
 
 	query, args, err := updateBuilder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%w: Update - build update query: %v", ErrBuildQuery, err)
 	}
 
+	config, err := scanConfig(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrConfigNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Update - scan updated config: %v", ErrScanRow, err)
+	}
+
+	return config, nil
+}
+
+// ListEnabledCompanyIDs возвращает не более limit ID компаний с включённой программой лояльности,
+// больших afterCompanyID, по возрастанию (постраничный обход всех компаний)
+func (r *Repository) ListEnabledCompanyIDs(ctx context.Context, afterCompanyID int64, limit int) ([]int64, error) {
+	query, args, err := psqlbuilder.Select("company_id").
+		From("loyalty_configs").
+		Where(squirrel.Eq{"is_enabled": true}).
+		Where(squirrel.Gt{"company_id": afterCompanyID}).
+		OrderBy("company_id").
+		Limit(uint64(limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListEnabledCompanyIDs - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListEnabledCompanyIDs - select company ids: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	companyIDs := make([]int64, 0, limit)
+	for rows.Next() {
+		var companyID int64
+		if err := rows.Scan(&companyID); err != nil {
+			return nil, fmt.Errorf("%w: ListEnabledCompanyIDs - scan company id: %v", ErrScanRow, err)
+		}
+		companyIDs = append(companyIDs, companyID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: ListEnabledCompanyIDs - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return companyIDs, nil
+}
+
+// Disable выключает программу лояльности компании от имени фонового процесса
+// В том же запросе отменяет ожидающие изменения компании, чтобы они не включили программу снова.
+// Вызывается в транзакции под блокировкой компании (LockCompany).
+// Возвращает выключенную конфигурацию; ErrConfigNotFound, если программа уже выключена или не настроена
+func (r *Repository) Disable(ctx context.Context, companyID int64) (*domain.LoyaltyConfig, error) {
+	query, args, err := psqlbuilder.Update("loyalty_configs").
+		Prefix(
+			"WITH canceled_schedules AS (UPDATE loyalty_config_schedules SET status = ?, resolved_at = NOW() "+
+				"WHERE company_id = ? AND status = ?)",
+			string(domain.ConfigScheduleStatusCanceled), companyID, string(domain.ConfigScheduleStatusPending),
+		).
+		Set("is_enabled", false).
+		Set("updated_by", nil).
+		Set("updated_by_role", string(domain.ActorRoleSystem)).
+		Where(squirrel.Eq{"company_id": companyID, "is_enabled": true}).
+		Suffix("RETURNING " + strings.Join(configColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Disable - build update query: %v", ErrBuildQuery, err)
+	}
+
+	config, err := scanConfig(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrConfigNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Disable - scan disabled config: %v", ErrScanRow, err)
+	}
+
+	return config, nil
+}
+
+// validityDays конвертирует срок действия карт в значение колонки (0 - NULL, карты бессрочные)
+func validityDays(days int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(days), Valid: days > 0}
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanConfig сканирует строку loyalty_configs (колонки configColumns) в domain модель
+func scanConfig(row rowScanner) (*domain.LoyaltyConfig, error) {
 	var config domain.LoyaltyConfig
-	var cardType string
+	var cardType, discountUpdatePolicy string
 	var createdAt, updatedAt sql.NullTime
 	var discountPercentage sql.NullFloat64
+	var cardValidityDays sql.NullInt64
 	var progressiveConfig, pointsConfig []byte
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+	err := row.Scan(
 		&config.ID,
 		&config.CompanyID,
 		&cardType,
@@ -161,18 +344,18 @@ func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyConfi
 		&discountPercentage,
 		&progressiveConfig,
 		&pointsConfig,
+		&cardValidityDays,
+		&discountUpdatePolicy,
 		&createdAt,
 		&updatedAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, ErrConfigNotFound
-	}
 	if err != nil {
-		return nil, fmt.Errorf("%w: Update - scan updated config: %v", ErrScanRow, err)
+		return nil, err
 	}
 
 	config.CardType = domain.CardType(cardType)
+	config.CardValidityDays = int(cardValidityDays.Int64)
+	config.DiscountUpdatePolicy = domain.DiscountUpdatePolicy(discountUpdatePolicy)
 	config.CreatedAt = createdAt.Time
 	config.UpdatedAt = updatedAt.Time
 
@@ -180,12 +363,12 @@ func (r *Repository) Update(ctx context.Context, input domain.UpdateLoyaltyConfi
 		config.DiscountPercentage = &discountPercentage.Float64
 	}
 
-	if len(progressiveConfig) > 0 {
-		config.ProgressiveConfig = progressiveConfig
+	if config.ProgressiveConfig, err = decodeJSONB[domain.ProgressiveConfig](progressiveConfig); err != nil {
+		return nil, fmt.Errorf("progressive_config: %v", err)
 	}
 
-	if len(pointsConfig) > 0 {
-		config.PointsConfig = pointsConfig
+	if config.PointsConfig, err = decodeJSONB[domain.PointsConfig](pointsConfig); err != nil {
+		return nil, fmt.Errorf("points_config: %v", err)
 	}
 
 	return &config, nil
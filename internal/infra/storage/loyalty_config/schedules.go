@@ -0,0 +1,282 @@
+package loyalty_config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// scheduleColumns колонки loyalty_config_schedules в порядке сканирования scanSchedule
+var scheduleColumns = []string{
+	"id", "company_id", "effective_from", "status", "card_type", "is_enabled", "discount_percentage",
+	"progressive_config", "points_config", "card_validity_days", "discount_update_policy",
+	"created_by", "created_by_role", "created_at", "resolved_at",
+}
+
+// CreateSchedule сохраняет запланированное изменение конфигурации компании
+// Незаданные параметры изменения сохраняются как NULL
+func (r *Repository) CreateSchedule(ctx context.Context, input domain.CreateLoyaltyConfigScheduleInput) (*domain.LoyaltyConfigSchedule, error) {
+	change := input.Change
+
+	progressiveConfig, err := encodeJSONB(change.ProgressiveConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CreateSchedule - progressive_config: %v", ErrBuildQuery, err)
+	}
+
+	pointsConfig, err := encodeJSONB(change.PointsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: CreateSchedule - points_config: %v", ErrBuildQuery, err)
+	}
+
+	var cardValidityDays sql.NullInt64
+	if change.CardValidityDays != nil {
+		cardValidityDays = sql.NullInt64{Int64: int64(*change.CardValidityDays), Valid: true}
+	}
+
+	query, args, err := psqlbuilder.Insert("loyalty_config_schedules").
+		Columns(
+			"company_id", "effective_from", "card_type", "is_enabled", "discount_percentage", "progressive_config",
+			"points_config", "card_validity_days", "discount_update_policy", "created_by", "created_by_role",
+		).
+		Values(
+			input.CompanyID, input.EffectiveFrom, nullString(change.CardType), change.IsEnabled,
+			change.DiscountPercentage, progressiveConfig, pointsConfig, cardValidityDays,
+			nullString(change.DiscountUpdatePolicy), input.CreatedBy, string(input.CreatedByRole),
+		).
+		Suffix("RETURNING " + strings.Join(scheduleColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: CreateSchedule - build insert query: %v", ErrBuildQuery, err)
+	}
+
+	schedule, err := scanSchedule(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		// Проверяем на duplicate key (ожидающее изменение компании на тот же момент)
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == pqErrCodeUniqueViolation {
+			return nil, ErrScheduleAlreadyExists
+		}
+		return nil, fmt.Errorf("%w: CreateSchedule - insert schedule: %v", ErrExecQuery, err)
+	}
+
+	return schedule, nil
+}
+
+// ListPendingSchedules возвращает ожидающие изменения конфигурации компании в порядке наступления
+func (r *Repository) ListPendingSchedules(ctx context.Context, companyID int64) ([]*domain.LoyaltyConfigSchedule, error) {
+	query, args, err := psqlbuilder.Select(scheduleColumns...).
+		From("loyalty_config_schedules").
+		Where(squirrel.Eq{"company_id": companyID, "status": string(domain.ConfigScheduleStatusPending)}).
+		OrderBy("effective_from").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListPendingSchedules - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListPendingSchedules - select schedules: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	var schedules []*domain.LoyaltyConfigSchedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ListPendingSchedules - scan schedule: %v", ErrScanRow, err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: ListPendingSchedules - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return schedules, nil
+}
+
+// ListDueSchedules возвращает ожидающие изменения компаний companyIDs, наступившие к моменту at,
+// упорядоченные по компании и моменту наступления (изменения, которые фоновый процесс ещё не перенёс)
+func (r *Repository) ListDueSchedules(ctx context.Context, companyIDs []int64, at time.Time) ([]*domain.LoyaltyConfigSchedule, error) {
+	if len(companyIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := psqlbuilder.Select(scheduleColumns...).
+		From("loyalty_config_schedules").
+		Where(squirrel.Eq{"company_id": companyIDs, "status": string(domain.ConfigScheduleStatusPending)}).
+		Where(squirrel.LtOrEq{"effective_from": at}).
+		OrderBy("company_id", "effective_from").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListDueSchedules - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListDueSchedules - select schedules: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	var schedules []*domain.LoyaltyConfigSchedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ListDueSchedules - scan schedule: %v", ErrScanRow, err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: ListDueSchedules - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return schedules, nil
+}
+
+// GetNextDueSchedule возвращает самое раннее ожидающее изменение любой компании, наступившее к now
+func (r *Repository) GetNextDueSchedule(ctx context.Context, now time.Time) (*domain.LoyaltyConfigSchedule, error) {
+	query, args, err := psqlbuilder.Select(scheduleColumns...).
+		From("loyalty_config_schedules").
+		Where(squirrel.Eq{"status": string(domain.ConfigScheduleStatusPending)}).
+		Where(squirrel.LtOrEq{"effective_from": now}).
+		OrderBy("effective_from", "id").
+		Limit(1).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetNextDueSchedule - build select query: %v", ErrBuildQuery, err)
+	}
+
+	schedule, err := scanSchedule(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetNextDueSchedule - scan schedule: %v", ErrScanRow, err)
+	}
+
+	return schedule, nil
+}
+
+// ResolveSchedule переводит ожидающее изменение компании в status (applied или canceled)
+// Возвращает ErrScheduleNotFound, если изменения нет или оно уже перенесено либо отменено
+func (r *Repository) ResolveSchedule(ctx context.Context, companyID, scheduleID int64, status domain.ConfigScheduleStatus) (*domain.LoyaltyConfigSchedule, error) {
+	query, args, err := psqlbuilder.Update("loyalty_config_schedules").
+		Set("status", string(status)).
+		Set("resolved_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{
+			"id":         scheduleID,
+			"company_id": companyID,
+			"status":     string(domain.ConfigScheduleStatusPending),
+		}).
+		Suffix("RETURNING " + strings.Join(scheduleColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: ResolveSchedule - build update query: %v", ErrBuildQuery, err)
+	}
+
+	schedule, err := scanSchedule(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: ResolveSchedule - scan schedule: %v", ErrScanRow, err)
+	}
+
+	return schedule, nil
+}
+
+// nullString конвертирует необязательное строковое значение в значение колонки (nil - NULL)
+func nullString[T ~string](v *T) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(*v), Valid: true}
+}
+
+// scanSchedule сканирует строку loyalty_config_schedules (колонки scheduleColumns) в domain модель
+func scanSchedule(row rowScanner) (*domain.LoyaltyConfigSchedule, error) {
+	var schedule domain.LoyaltyConfigSchedule
+	var status, createdByRole string
+	var cardType, discountUpdatePolicy sql.NullString
+	var isEnabled sql.NullBool
+	var discountPercentage sql.NullFloat64
+	var cardValidityDays sql.NullInt64
+	var resolvedAt sql.NullTime
+	var progressiveConfig, pointsConfig []byte
+
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.CompanyID,
+		&schedule.EffectiveFrom,
+		&status,
+		&cardType,
+		&isEnabled,
+		&discountPercentage,
+		&progressiveConfig,
+		&pointsConfig,
+		&cardValidityDays,
+		&discountUpdatePolicy,
+		&schedule.CreatedBy,
+		&createdByRole,
+		&schedule.CreatedAt,
+		&resolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Status = domain.ConfigScheduleStatus(status)
+	schedule.CreatedByRole = domain.ActorRole(createdByRole)
+
+	change := &schedule.Change
+	if cardType.Valid {
+		value := domain.CardType(cardType.String)
+		change.CardType = &value
+	}
+
+	if isEnabled.Valid {
+		change.IsEnabled = &isEnabled.Bool
+	}
+
+	if discountPercentage.Valid {
+		change.DiscountPercentage = &discountPercentage.Float64
+	}
+
+	if cardValidityDays.Valid {
+		days := int(cardValidityDays.Int64)
+		change.CardValidityDays = &days
+	}
+
+	if discountUpdatePolicy.Valid {
+		value := domain.DiscountUpdatePolicy(discountUpdatePolicy.String)
+		change.DiscountUpdatePolicy = &value
+	}
+
+	if resolvedAt.Valid {
+		schedule.ResolvedAt = &resolvedAt.Time
+	}
+
+	if change.ProgressiveConfig, err = decodeJSONB[domain.ProgressiveConfig](progressiveConfig); err != nil {
+		return nil, fmt.Errorf("progressive_config: %v", err)
+	}
+
+	if change.PointsConfig, err = decodeJSONB[domain.PointsConfig](pointsConfig); err != nil {
+		return nil, fmt.Errorf("points_config: %v", err)
+	}
+
+	return &schedule, nil
+}
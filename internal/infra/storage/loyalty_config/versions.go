@@ -0,0 +1,136 @@
+package loyalty_config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// versionColumns колонки loyalty_config_versions в порядке сканирования scanVersion
+// Версии записываются триггером при каждом создании и изменении loyalty_configs
+var versionColumns = []string{
+	"config_id", "company_id", "version", "card_type", "is_enabled", "discount_percentage",
+	"progressive_config", "points_config", "card_validity_days", "discount_update_policy",
+	"created_by", "created_by_role", "created_at",
+}
+
+// ListVersions возвращает не более input.Limit версий конфигурации компании от новых к старым
+func (r *Repository) ListVersions(ctx context.Context, input domain.ListLoyaltyConfigVersionsInput) ([]*domain.LoyaltyConfigVersion, error) {
+	builder := psqlbuilder.Select(versionColumns...).
+		From("loyalty_config_versions").
+		Where(squirrel.Eq{"company_id": input.CompanyID}).
+		OrderBy("version DESC").
+		Limit(uint64(input.Limit))
+
+	if input.BeforeVersion != nil {
+		builder = builder.Where(squirrel.Lt{"version": *input.BeforeVersion})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListVersions - build select query: %v", ErrBuildQuery, err)
+	}
+
+	rows, err := dbmetrics.GetExecutor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListVersions - select versions: %v", ErrExecQuery, err)
+	}
+	defer rows.Close()
+
+	versions := make([]*domain.LoyaltyConfigVersion, 0, input.Limit)
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ListVersions - scan version: %v", ErrScanRow, err)
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: ListVersions - iterate rows: %v", ErrScanRow, err)
+	}
+
+	return versions, nil
+}
+
+// GetVersion получает версию конфигурации компании по номеру
+func (r *Repository) GetVersion(ctx context.Context, companyID int64, version int) (*domain.LoyaltyConfigVersion, error) {
+	query, args, err := psqlbuilder.Select(versionColumns...).
+		From("loyalty_config_versions").
+		Where(squirrel.Eq{"company_id": companyID, "version": version}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetVersion - build select query: %v", ErrBuildQuery, err)
+	}
+
+	result, err := scanVersion(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetVersion - scan version: %v", ErrScanRow, err)
+	}
+
+	return result, nil
+}
+
+// scanVersion сканирует строку loyalty_config_versions (колонки versionColumns) в domain модель
+func scanVersion(row rowScanner) (*domain.LoyaltyConfigVersion, error) {
+	var version domain.LoyaltyConfigVersion
+	var cardType, discountUpdatePolicy string
+	var createdByRole sql.NullString
+	var createdBy, cardValidityDays sql.NullInt64
+	var discountPercentage sql.NullFloat64
+	var createdAt sql.NullTime
+	var progressiveConfig, pointsConfig []byte
+
+	err := row.Scan(
+		&version.Config.ID,
+		&version.Config.CompanyID,
+		&version.Version,
+		&cardType,
+		&version.Config.IsEnabled,
+		&discountPercentage,
+		&progressiveConfig,
+		&pointsConfig,
+		&cardValidityDays,
+		&discountUpdatePolicy,
+		&createdBy,
+		&createdByRole,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	version.Config.CardType = domain.CardType(cardType)
+	version.Config.CardValidityDays = int(cardValidityDays.Int64)
+	version.Config.DiscountUpdatePolicy = domain.DiscountUpdatePolicy(discountUpdatePolicy)
+	version.Config.UpdatedAt = createdAt.Time
+	version.CreatedByRole = domain.ActorRole(createdByRole.String)
+
+	if discountPercentage.Valid {
+		version.Config.DiscountPercentage = &discountPercentage.Float64
+	}
+
+	if createdBy.Valid {
+		version.CreatedBy = &createdBy.Int64
+	}
+
+	if version.Config.ProgressiveConfig, err = decodeJSONB[domain.ProgressiveConfig](progressiveConfig); err != nil {
+		return nil, fmt.Errorf("progressive_config: %v", err)
+	}
+
+	if version.Config.PointsConfig, err = decodeJSONB[domain.PointsConfig](pointsConfig); err != nil {
+		return nil, fmt.Errorf("points_config: %v", err)
+	}
+
+	return &version, nil
+}
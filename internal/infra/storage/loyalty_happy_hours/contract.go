@@ -0,0 +1,8 @@
+package loyalty_happy_hours
+
+import (
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+)
+
+// Переиспользуем интерфейсы из dbmetrics
+type DBExecutor = dbmetrics.DBExecutor
@@ -0,0 +1,17 @@
+package loyalty_happy_hours
+
+import "errors"
+
+var (
+	// ErrHappyHoursNotFound возвращается, когда «счастливые часы» компании не заданы
+	ErrHappyHoursNotFound = errors.New("repository.loyalty_happy_hours: happy hours not found")
+
+	// ErrBuildQuery возвращается при ошибке построения SQL запроса
+	ErrBuildQuery = errors.New("repository.loyalty_happy_hours: failed to build SQL query")
+
+	// ErrExecQuery возвращается при ошибке выполнения SQL запроса
+	ErrExecQuery = errors.New("repository.loyalty_happy_hours: failed to execute SQL query")
+
+	// ErrScanRow возвращается при ошибке сканирования строки из БД
+	ErrScanRow = errors.New("repository.loyalty_happy_hours: failed to scan row")
+)
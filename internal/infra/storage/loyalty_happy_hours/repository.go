@@ -0,0 +1,119 @@
+package loyalty_happy_hours
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// happyHoursColumns колонки loyalty_happy_hours в порядке сканирования scanHappyHours
+var happyHoursColumns = []string{
+	"id", "company_id", "timezone", "windows", "updated_by", "updated_by_role", "created_at", "updated_at",
+}
+
+// Repository репозиторий «счастливых часов» компаний
+type Repository struct {
+	db DBExecutor
+}
+
+// NewRepository создает новый экземпляр репозитория «счастливых часов»
+func NewRepository(db DBExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// GetByCompanyID возвращает «счастливые часы» компании
+func (r *Repository) GetByCompanyID(ctx context.Context, companyID int64) (*domain.HappyHours, error) {
+	query, args, err := psqlbuilder.Select(happyHoursColumns...).
+		From("loyalty_happy_hours").
+		Where(squirrel.Eq{"company_id": companyID}).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetByCompanyID - build select query: %v", ErrBuildQuery, err)
+	}
+
+	happyHours, err := scanHappyHours(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrHappyHoursNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetByCompanyID - scan happy hours: %v", ErrScanRow, err)
+	}
+
+	return happyHours, nil
+}
+
+// Save создаёт или заменяет «счастливые часы» компании целиком
+func (r *Repository) Save(ctx context.Context, happyHours *domain.HappyHours) (*domain.HappyHours, error) {
+	windows := happyHours.Windows
+	if windows == nil {
+		windows = []domain.HappyHourWindow{}
+	}
+
+	windowsJSON, err := json.Marshal(windows)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Save - marshal windows: %v", ErrBuildQuery, err)
+	}
+
+	query, args, err := psqlbuilder.Insert("loyalty_happy_hours").
+		Columns("company_id", "timezone", "windows", "updated_by", "updated_by_role").
+		Values(happyHours.CompanyID, happyHours.Timezone, string(windowsJSON), happyHours.UpdatedBy, string(happyHours.UpdatedByRole)).
+		Suffix("ON CONFLICT (company_id) DO UPDATE SET " +
+			"timezone = EXCLUDED.timezone, windows = EXCLUDED.windows, " +
+			"updated_by = EXCLUDED.updated_by, updated_by_role = EXCLUDED.updated_by_role, updated_at = NOW() " +
+			"RETURNING " + strings.Join(happyHoursColumns, ", ")).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Save - build upsert query: %v", ErrBuildQuery, err)
+	}
+
+	saved, err := scanHappyHours(dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: Save - upsert happy hours: %v", ErrExecQuery, err)
+	}
+
+	return saved, nil
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanHappyHours сканирует строку loyalty_happy_hours (колонки happyHoursColumns) в domain модель
+func scanHappyHours(row rowScanner) (*domain.HappyHours, error) {
+	var happyHours domain.HappyHours
+	var windows []byte
+	var updatedByRole string
+
+	err := row.Scan(
+		&happyHours.ID,
+		&happyHours.CompanyID,
+		&happyHours.Timezone,
+		&windows,
+		&happyHours.UpdatedBy,
+		&updatedByRole,
+		&happyHours.CreatedAt,
+		&happyHours.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	happyHours.UpdatedByRole = domain.ActorRole(updatedByRole)
+
+	if err := json.Unmarshal(windows, &happyHours.Windows); err != nil {
+		return nil, fmt.Errorf("windows: unmarshal jsonb: %v", err)
+	}
+
+	return &happyHours, nil
+}
@@ -0,0 +1,18 @@
+package loyalty_transaction
+
+import "errors"
+
+var (
+	// ErrCardNotActive возвращается, когда активная карта для операции не найдена
+	// (карта удалена, приостановлена, отключена или истекла)
+	ErrCardNotActive = errors.New("repository.loyalty_transaction: active card not found")
+
+	// ErrInsufficientPoints возвращается, когда на карте недостаточно баллов для списания
+	ErrInsufficientPoints = errors.New("repository.loyalty_transaction: insufficient points")
+
+	// ErrBuildQuery возвращается при ошибке построения SQL запроса
+	ErrBuildQuery = errors.New("repository.loyalty_transaction: failed to build SQL query")
+
+	// ErrExecQuery возвращается при ошибке выполнения SQL запроса
+	ErrExecQuery = errors.New("repository.loyalty_transaction: failed to execute SQL query")
+)
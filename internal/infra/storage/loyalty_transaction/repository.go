@@ -0,0 +1,150 @@
+package loyalty_transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Repository репозиторий журнала операций с баллами
+//
+// Изменение баланса карты и запись в журнал выполняются одним SQL запросом (CTE),
+// поэтому операции атомарны и без явной транзакции, а списание не может увести
+// баланс в минус даже при конкурентных запросах
+type Repository struct {
+	db DBExecutor
+}
+
+// NewRepository создает новый экземпляр репозитория журнала баллов
+func NewRepository(db DBExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// Accrue начисляет баллы на активную карту и записывает операцию в журнал
+// Возвращает ErrCardNotActive, если карта не найдена, не активна или истекла к моменту записи
+func (r *Repository) Accrue(ctx context.Context, input domain.CreateLoyaltyTransactionInput) (*domain.LoyaltyTransaction, error) {
+	balanceUpdate := activeCardUpdate(input.CardID).
+		Set("points_balance", squirrel.Expr("points_balance + ?", input.Points)).
+		Suffix("RETURNING id, points_balance")
+
+	tx, err := r.insert(ctx, "Accrue", balanceUpdate, domain.TransactionTypeAccrual, input)
+	if err == sql.ErrNoRows {
+		return nil, ErrCardNotActive
+	}
+
+	return tx, err
+}
+
+// Redeem списывает баллы с активной карты и записывает операцию в журнал
+// Возвращает ErrInsufficientPoints, если баланса не хватает, и ErrCardNotActive,
+// если карта не найдена, не активна или истекла к моменту записи
+func (r *Repository) Redeem(ctx context.Context, input domain.CreateLoyaltyTransactionInput) (*domain.LoyaltyTransaction, error) {
+	balanceUpdate := activeCardUpdate(input.CardID).
+		Set("points_balance", squirrel.Expr("points_balance - ?", input.Points)).
+		Where(squirrel.GtOrEq{"points_balance": input.Points}).
+		Suffix("RETURNING id, points_balance")
+
+	tx, err := r.insert(ctx, "Redeem", balanceUpdate, domain.TransactionTypeRedemption, input)
+	if err != sql.ErrNoRows {
+		return tx, err
+	}
+
+	// Списание не выполнено: различаем нехватку баллов и неактивную карту
+	active, err := r.isActive(ctx, input.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrCardNotActive
+	}
+
+	return nil, ErrInsufficientPoints
+}
+
+// activeCardUpdate начинает изменение карты, которое выполняется, только если карта активна и не истекла
+func activeCardUpdate(cardID int64) squirrel.UpdateBuilder {
+	return squirrel.Update("loyalty_cards").
+		Where(squirrel.Eq{"id": cardID, "status": string(domain.CardStatusActive)}).
+		Where(squirrel.Expr("(expires_at IS NULL OR expires_at > NOW())"))
+}
+
+// isActive проверяет, что карта существует, активна и не истекла
+func (r *Repository) isActive(ctx context.Context, cardID int64) (bool, error) {
+	query, args, err := psqlbuilder.Select("1").
+		From("loyalty_cards").
+		Where(squirrel.Eq{"id": cardID, "status": string(domain.CardStatusActive)}).
+		Where(squirrel.Expr("(expires_at IS NULL OR expires_at > NOW())")).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("%w: isActive - build select query: %v", ErrBuildQuery, err)
+	}
+
+	var one int
+	err = dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: isActive - select card: %v", ErrExecQuery, err)
+	}
+
+	return true, nil
+}
+
+// insert выполняет изменение баланса и вставку записи журнала одним запросом
+// Если UPDATE не затронул ни одной строки, вставка не выполняется и возвращается sql.ErrNoRows
+func (r *Repository) insert(
+	ctx context.Context,
+	method string,
+	balanceUpdate squirrel.UpdateBuilder,
+	txType domain.TransactionType,
+	input domain.CreateLoyaltyTransactionInput,
+) (*domain.LoyaltyTransaction, error) {
+	query, args, err := psqlbuilder.Insert("loyalty_transactions").
+		PrefixExpr(squirrel.Expr("WITH card AS (?)", balanceUpdate)).
+		Columns("card_id", "type", "points", "amount", "balance_after", "created_by", "created_by_service", "created_by_role").
+		Select(
+			squirrel.Select("id").
+				Column("?::VARCHAR", string(txType)).
+				Column("?::BIGINT", input.Points).
+				Column("?::DECIMAL", input.Amount).
+				Column("points_balance").
+				Column("?::BIGINT", input.CreatedBy).
+				Column("?::VARCHAR", input.CreatedByService).
+				Column("?::VARCHAR", string(input.CreatedByRole)).
+				From("card"),
+		).
+		Suffix("RETURNING id, balance_after, created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s - build insert query: %v", ErrBuildQuery, method, err)
+	}
+
+	tx := &domain.LoyaltyTransaction{
+		CardID:           input.CardID,
+		Type:             txType,
+		Points:           input.Points,
+		Amount:           input.Amount,
+		CreatedBy:        input.CreatedBy,
+		CreatedByService: input.CreatedByService,
+		CreatedByRole:    input.CreatedByRole,
+	}
+
+	err = dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&tx.ID, &tx.BalanceAfter, &tx.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s - insert transaction: %v", ErrExecQuery, method, err)
+	}
+
+	return tx, nil
+}
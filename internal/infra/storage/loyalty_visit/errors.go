@@ -0,0 +1,14 @@
+package loyalty_visit
+
+import "errors"
+
+var (
+	// ErrCardNotFound возвращается, когда активная карта для записи визита не найдена
+	ErrCardNotFound = errors.New("repository.loyalty_visit: active card not found")
+
+	// ErrBuildQuery возвращается при ошибке построения SQL запроса
+	ErrBuildQuery = errors.New("repository.loyalty_visit: failed to build SQL query")
+
+	// ErrExecQuery возвращается при ошибке выполнения SQL запроса
+	ErrExecQuery = errors.New("repository.loyalty_visit: failed to execute SQL query")
+)
@@ -0,0 +1,97 @@
+package loyalty_visit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/psqlbuilder"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Repository репозиторий визитов по картам лояльности
+type Repository struct {
+	db DBExecutor
+}
+
+// NewRepository создает новый экземпляр репозитория визитов
+func NewRepository(db DBExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// Create записывает визит по активной карте
+// Увеличение visits_count, пересчёт скидки по уровням и вставка визита выполняются
+// одним SQL запросом (CTE), поэтому счётчик и уровень карты обновляются атомарно
+func (r *Repository) Create(ctx context.Context, input domain.CreateLoyaltyVisitInput) (*domain.LoyaltyVisit, error) {
+	cardUpdate := squirrel.Update("loyalty_cards").
+		Set("visits_count", squirrel.Expr("visits_count + 1")).
+		Where(squirrel.Eq{"id": input.CardID, "status": string(domain.CardStatusActive)}).
+		Where(squirrel.Expr("(expires_at IS NULL OR expires_at > NOW())")).
+		Suffix("RETURNING id, visits_count")
+
+	if len(input.Tiers) > 0 {
+		cardUpdate = cardUpdate.Set("discount_percentage", tierDiscountExpr(input.Tiers))
+	}
+
+	query, args, err := psqlbuilder.Insert("loyalty_visits").
+		PrefixExpr(squirrel.Expr("WITH card AS (?)", cardUpdate)).
+		Columns("card_id", "amount", "service_id", "visited_at", "visit_number", "recorded_by", "recorded_by_service", "recorded_by_role").
+		Select(
+			squirrel.Select("id").
+				Column("?::DECIMAL", input.Amount).
+				Column("?::BIGINT", input.ServiceID).
+				Column("?::TIMESTAMPTZ", input.VisitedAt).
+				Column("visits_count").
+				Column("?::BIGINT", input.RecordedBy).
+				Column("?::VARCHAR", input.RecordedByService).
+				Column("?::VARCHAR", string(input.RecordedByRole)).
+				From("card"),
+		).
+		Suffix("RETURNING id, visit_number, created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - build insert query: %v", ErrBuildQuery, err)
+	}
+
+	visit := &domain.LoyaltyVisit{
+		CardID:            input.CardID,
+		Amount:            input.Amount,
+		ServiceID:         input.ServiceID,
+		VisitedAt:         input.VisitedAt,
+		RecordedBy:        input.RecordedBy,
+		RecordedByService: input.RecordedByService,
+		RecordedByRole:    input.RecordedByRole,
+	}
+
+	err = dbmetrics.GetExecutor(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&visit.ID, &visit.VisitNumber, &visit.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Create - insert visit: %v", ErrExecQuery, err)
+	}
+
+	return visit, nil
+}
+
+// tierDiscountExpr строит выражение скидки для нового значения visits_count (visits_count + 1)
+// Уровни перебираются от старшего к младшему, первый уровень используется по умолчанию
+func tierDiscountExpr(tiers []domain.ProgressiveTier) squirrel.Sqlizer {
+	if len(tiers) == 1 {
+		return squirrel.Expr("?::DECIMAL", tiers[0].DiscountPercentage)
+	}
+
+	caseExpr := squirrel.Case()
+	for i := len(tiers) - 1; i > 0; i-- {
+		caseExpr = caseExpr.When(
+			squirrel.Expr("visits_count + 1 >= ?", tiers[i].MinVisits),
+			squirrel.Expr("?::DECIMAL", tiers[i].DiscountPercentage),
+		)
+	}
+
+	return caseExpr.Else(squirrel.Expr("?::DECIMAL", tiers[0].DiscountPercentage))
+}
@@ -0,0 +1,131 @@
+package sellerservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/ttlcache"
+)
+
+// companyCacheName метка cache в метриках кэша компаний
+const companyCacheName = "sellerservice_companies"
+
+// Метки result в метриках кэша
+const (
+	cacheResultHit         = "hit"
+	cacheResultNegativeHit = "negative_hit"
+	cacheResultMiss        = "miss"
+)
+
+// CacheConfig параметры кэша компаний
+type CacheConfig struct {
+	// TTL время жизни найденной компании
+	TTL time.Duration
+	// NegativeTTL время жизни ответа "компания не найдена" (0 - не кэшировать)
+	NegativeTTL time.Duration
+	// MaxEntries максимальное количество компаний в кэше
+	MaxEntries int
+
+	// ServiceName метка service в метриках
+	ServiceName string
+}
+
+// companyEntry запись кэша: компания или отметка, что компания не найдена
+type companyEntry struct {
+	company  *Company
+	notFound bool
+}
+
+// CachedClient кэширует данные компаний из SellerService поверх API
+//
+// Компании (в первую очередь manager_ids для проверки прав менеджера) меняются редко,
+// поэтому GetCompany отвечает из кэша, а одновременные запросы одной компании выполняются одним запросом
+type CachedClient struct {
+	api     API
+	cfg     CacheConfig
+	cache   *ttlcache.Cache[int64, companyEntry]
+	group   singleflight.Group
+	metrics CacheMetrics
+}
+
+// NewCachedClient создает клиент с кэшем компаний
+// metrics может быть nil, если метрики выключены
+func NewCachedClient(api API, cfg CacheConfig, metrics CacheMetrics) *CachedClient {
+	return &CachedClient{
+		api:     api,
+		cfg:     cfg,
+		cache:   ttlcache.New[int64, companyEntry](cfg.MaxEntries),
+		metrics: metrics,
+	}
+}
+
+// GetCompany получает информацию о компании по ID из кэша или SellerService
+// Возвращаемая компания общая для всех вызывающих и не должна изменяться
+func (c *CachedClient) GetCompany(ctx context.Context, companyID int64) (*Company, error) {
+	if entry, ok := c.cache.Get(companyID); ok {
+		if entry.notFound {
+			c.recordRequest(cacheResultNegativeHit)
+			return nil, ErrCompanyNotFound
+		}
+		c.recordRequest(cacheResultHit)
+		return entry.company, nil
+	}
+
+	c.recordRequest(cacheResultMiss)
+
+	// Запрос выполняется без отмены контекста первого вызывающего, чтобы его отмена
+	// не завершила ошибкой ожидающие тот же ответ запросы; время запроса ограничено таймаутом клиента
+	fetchCtx := context.WithoutCancel(ctx)
+	ch := c.group.DoChan(companyKey(companyID), func() (interface{}, error) {
+		return c.fetchCompany(fetchCtx, companyID)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: GetCompany canceled: %v", ErrInternal, ctx.Err())
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*Company), nil
+	}
+}
+
+// fetchCompany запрашивает компанию в SellerService и сохраняет результат в кэш
+// Ошибки, кроме ErrCompanyNotFound, не кэшируются
+func (c *CachedClient) fetchCompany(ctx context.Context, companyID int64) (*Company, error) {
+	company, err := c.api.GetCompany(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, ErrCompanyNotFound) {
+			c.cache.Set(companyID, companyEntry{notFound: true}, c.cfg.NegativeTTL)
+			c.recordEntries()
+		}
+		return nil, err
+	}
+
+	c.cache.Set(companyID, companyEntry{company: company}, c.cfg.TTL)
+	c.recordEntries()
+
+	return company, nil
+}
+
+func (c *CachedClient) recordRequest(result string) {
+	if c.metrics != nil {
+		c.metrics.RecordCacheRequest(c.cfg.ServiceName, companyCacheName, result)
+	}
+}
+
+func (c *CachedClient) recordEntries() {
+	if c.metrics != nil {
+		c.metrics.SetCacheEntries(c.cfg.ServiceName, companyCacheName, c.cache.Len())
+	}
+}
+
+func companyKey(companyID int64) string {
+	return strconv.FormatInt(companyID, 10)
+}
@@ -3,61 +3,232 @@ package sellerservice
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/circuitbreaker"
+)
+
+// metricsTarget метка target в метриках исходящих запросов
+const metricsTarget = "sellerservice"
+
+// Метки status в метриках исходящих запросов
+const (
+	statusSuccess         = "success"
+	statusNotFound        = "not_found"
+	statusClientError     = "client_error"
+	statusServerError     = "server_error"
+	statusNetworkError    = "network_error"
+	statusTimeout         = "timeout"
+	statusInvalidResponse = "invalid_response"
+	statusCanceled        = "canceled"
+	statusCircuitOpen     = "circuit_open"
 )
 
+// Config параметры клиента SellerService
+type Config struct {
+	BaseURL string
+	// Timeout таймаут одной попытки запроса
+	Timeout time.Duration
+
+	// MaxRetries количество повторов после первой попытки (0 - без повторов)
+	// Повторяются только сетевые ошибки и ответы 5xx, все запросы клиента - идемпотентные GET
+	MaxRetries int
+	// RetryBaseDelay и RetryMaxDelay границы экспоненциальной задержки между попытками (с jitter)
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	// BreakerFailureThreshold количество сбоев подряд, после которого запросы перестают отправляться
+	BreakerFailureThreshold int
+	// BreakerOpenTimeout время до пробного запроса после размыкания выключателя
+	BreakerOpenTimeout time.Duration
+
+	// Timezone часовой пояс IANA, в котором SellerService задаёт часы работы компаний:
+	// в ответе он не передаётся и подставляется клиентом в WorkingHours.Timezone
+	Timezone string
+
+	// ServiceName метка service в метриках
+	ServiceName string
+}
+
 // Client клиент для работы с SellerService
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	cfg        Config
+	breaker    *circuitbreaker.Breaker
+	metrics    Metrics
 	log        Logger
 }
 
 // NewClient создает новый экземпляр клиента SellerService
-func NewClient(baseURL string, timeout time.Duration, log Logger) *Client {
-	return &Client{
-		baseURL: baseURL,
+// metrics может быть nil, если метрики выключены
+func NewClient(cfg Config, metrics Metrics, log Logger) *Client {
+	c := &Client{
+		baseURL: cfg.BaseURL,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout: cfg.Timeout,
 		},
-		log: log,
+		cfg:     cfg,
+		metrics: metrics,
+		log:     log,
 	}
+
+	c.breaker = circuitbreaker.New(circuitbreaker.Config{
+		FailureThreshold: cfg.BreakerFailureThreshold,
+		OpenTimeout:      cfg.BreakerOpenTimeout,
+		OnStateChange:    c.onBreakerStateChange,
+	})
+
+	if c.metrics != nil {
+		c.metrics.SetCircuitBreakerState(cfg.ServiceName, metricsTarget, int(circuitbreaker.StateClosed))
+	}
+
+	return c
 }
 
 // GetCompany получает информацию о компании по ID
 func (c *Client) GetCompany(ctx context.Context, companyID int64) (*Company, error) {
 	url := fmt.Sprintf("%s/api/v1/companies/%d", c.baseURL, companyID)
 
+	var company Company
+	if err := c.getJSON(ctx, "GetCompany", url, ErrCompanyNotFound, &company); err != nil {
+		return nil, err
+	}
+	company.WorkingHours.Timezone = c.cfg.Timezone
+
+	return &company, nil
+}
+
+// attemptResult итог одной попытки запроса
+type attemptResult struct {
+	// status метка status в метриках
+	status string
+	// retryable сбой сети или SellerService: попытку можно повторить, выключатель учитывает её как сбой
+	retryable bool
+	// canceled запрос отменён вызывающей стороной и не говорит о состоянии SellerService
+	canceled bool
+}
+
+// getJSON выполняет GET запрос с повторами и декодирует ответ в out
+// Ответ 404 возвращается как notFoundErr, при разомкнутом выключателе запрос не отправляется
+func (c *Client) getJSON(ctx context.Context, operation, url string, notFoundErr error, out interface{}) error {
+	for attempt := 0; ; attempt++ {
+		if err := c.breaker.Allow(); err != nil {
+			c.recordRequest(operation, statusCircuitOpen, 0)
+			return ErrCircuitOpen
+		}
+
+		start := time.Now()
+		result, err := c.doGet(ctx, url, notFoundErr, out)
+		c.recordRequest(operation, result.status, time.Since(start).Seconds())
+
+		switch {
+		case result.canceled:
+			c.breaker.Ignore()
+		case result.retryable:
+			c.breaker.Failure()
+		default:
+			c.breaker.Success()
+		}
+
+		if !result.retryable || attempt >= c.cfg.MaxRetries {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		c.log.Warn("SellerService %s failed, retrying in %s: attempt=%d, error=%v", operation, delay, attempt+1, err)
+		if c.metrics != nil {
+			c.metrics.RecordOutboundRetry(c.cfg.ServiceName, metricsTarget, operation)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// doGet выполняет одну попытку GET запроса
+func (c *Client) doGet(ctx context.Context, url string, notFoundErr error, out interface{}) (attemptResult, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to create request: %v", ErrInternal, err)
+		return attemptResult{status: statusClientError}, fmt.Errorf("%w: failed to create request: %v", ErrInternal, err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to execute request: %v", ErrInternal, err)
+		if ctx.Err() != nil {
+			return attemptResult{status: statusCanceled, canceled: true}, fmt.Errorf("%w: request canceled: %v", ErrInternal, err)
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return attemptResult{status: statusTimeout, retryable: true}, fmt.Errorf("%w: request timed out: %v", ErrTimeout, err)
+		}
+		return attemptResult{status: statusNetworkError, retryable: true}, fmt.Errorf("%w: failed to execute request: %v", ErrUnavailable, err)
 	}
 	defer resp.Body.Close()
 
 	// Обработка статус-кодов
-	switch resp.StatusCode {
-	case http.StatusOK:
+	switch {
+	case resp.StatusCode == http.StatusOK:
 		// Продолжаем обработку
-	case http.StatusNotFound:
-		return nil, ErrCompanyNotFound
+	case resp.StatusCode == http.StatusNotFound:
+		return attemptResult{status: statusNotFound}, notFoundErr
+	case resp.StatusCode == http.StatusGatewayTimeout:
+		body, _ := io.ReadAll(resp.Body)
+		return attemptResult{status: statusServerError, retryable: true},
+			fmt.Errorf("%w: unexpected status code %d: %s", ErrTimeout, resp.StatusCode, string(body))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		body, _ := io.ReadAll(resp.Body)
+		return attemptResult{status: statusServerError, retryable: true},
+			fmt.Errorf("%w: unexpected status code %d: %s", ErrUnavailable, resp.StatusCode, string(body))
 	default:
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("%w: unexpected status code %d: %s", ErrInvalidResponse, resp.StatusCode, string(body))
+		return attemptResult{status: statusClientError},
+			fmt.Errorf("%w: unexpected status code %d: %s", ErrInvalidResponse, resp.StatusCode, string(body))
 	}
 
 	// Парсим ответ
-	var company Company
-	if err := json.NewDecoder(resp.Body).Decode(&company); err != nil {
-		return nil, fmt.Errorf("%w: failed to decode response: %v", ErrInvalidResponse, err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return attemptResult{status: statusInvalidResponse}, fmt.Errorf("%w: failed to decode response: %v", ErrInvalidResponse, err)
 	}
 
-	return &company, nil
+	return attemptResult{status: statusSuccess}, nil
+}
+
+// backoff возвращает задержку перед повтором: случайное значение до RetryBaseDelay * 2^attempt,
+// но не больше RetryMaxDelay (full jitter), чтобы повторы разных запросов не совпадали по времени
+func (c *Client) backoff(attempt int) time.Duration {
+	limit := c.cfg.RetryMaxDelay
+	if attempt < 32 {
+		if delay := c.cfg.RetryBaseDelay << attempt; delay > 0 && delay < limit {
+			limit = delay
+		}
+	}
+
+	if limit <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(limit))) + 1
+}
+
+func (c *Client) recordRequest(operation, status string, duration float64) {
+	if c.metrics != nil {
+		c.metrics.RecordOutboundRequest(c.cfg.ServiceName, metricsTarget, operation, status, duration)
+	}
+}
+
+func (c *Client) onBreakerStateChange(from, to circuitbreaker.State) {
+	c.log.Warn("SellerService circuit breaker state changed: %s -> %s", from, to)
+	if c.metrics != nil {
+		c.metrics.SetCircuitBreakerState(c.cfg.ServiceName, metricsTarget, int(to))
+	}
 }
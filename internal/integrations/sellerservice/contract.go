@@ -1,8 +1,28 @@
 package sellerservice
 
+import "context"
+
 // Logger интерфейс для логирования
 type Logger interface {
 	Info(format string, v ...interface{})
 	Warn(format string, v ...interface{})
 	Error(format string, v ...interface{})
 }
+
+// Metrics интерфейс сбора метрик исходящих запросов (nil, если метрики выключены)
+type Metrics interface {
+	RecordOutboundRequest(service, target, operation, status string, duration float64)
+	RecordOutboundRetry(service, target, operation string)
+	SetCircuitBreakerState(service, target string, state int)
+}
+
+// API методы SellerService, которые использует сервис лояльности (реализуется Client)
+type API interface {
+	GetCompany(ctx context.Context, companyID int64) (*Company, error)
+}
+
+// CacheMetrics интерфейс сбора метрик кэша (nil, если метрики выключены)
+type CacheMetrics interface {
+	RecordCacheRequest(service, cache, result string)
+	SetCacheEntries(service, cache string, count int)
+}
@@ -1,17 +1,26 @@
 package sellerservice
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrCompanyNotFound возвращается, когда компания не найдена
 	ErrCompanyNotFound = errors.New("company not found")
 
-	// ErrServiceNotFound возвращается, когда услуга не найдена
-	ErrServiceNotFound = errors.New("service not found")
-
 	// ErrInternal возвращается при внутренних ошибках клиента
 	ErrInternal = errors.New("sellerservice client: internal error")
 
-	// ErrInvalidResponse возвращается при некорректном ответе от сервиса
+	// ErrTimeout возвращается, когда сервис не ответил за отведённое время (или сам ответил 504)
+	ErrTimeout = errors.New("sellerservice client: timeout")
+
+	// ErrUnavailable возвращается, когда сервис недоступен: соединение не установлено или ответ 5xx
+	ErrUnavailable = errors.New("sellerservice client: service unavailable")
+
+	// ErrCircuitOpen возвращается без обращения к сервису, пока автоматический выключатель разомкнут
+	ErrCircuitOpen = fmt.Errorf("%w: circuit breaker open", ErrUnavailable)
+
+	// ErrInvalidResponse возвращается при некорректном ответе от сервиса: неожиданный статус или тело
 	ErrInvalidResponse = errors.New("sellerservice client: invalid response")
 )
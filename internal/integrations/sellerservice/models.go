@@ -30,6 +30,29 @@ type WorkingHours struct {
 	Friday    DaySchedule `json:"friday"`
 	Saturday  DaySchedule `json:"saturday"`
 	Sunday    DaySchedule `json:"sunday"`
+
+	// Timezone часовой пояс IANA, в котором заданы часы (SellerService его не передаёт, см. Config.Timezone)
+	Timezone string `json:"-"`
+}
+
+// ForWeekday возвращает расписание на день недели
+func (w WorkingHours) ForWeekday(day time.Weekday) DaySchedule {
+	switch day {
+	case time.Monday:
+		return w.Monday
+	case time.Tuesday:
+		return w.Tuesday
+	case time.Wednesday:
+		return w.Wednesday
+	case time.Thursday:
+		return w.Thursday
+	case time.Friday:
+		return w.Friday
+	case time.Saturday:
+		return w.Saturday
+	default:
+		return w.Sunday
+	}
 }
 
 // DaySchedule расписание на день
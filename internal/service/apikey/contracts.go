@@ -0,0 +1,15 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+// APIKeyRepository интерфейс репозитория API-ключей
+type APIKeyRepository interface {
+	Create(ctx context.Context, input domain.CreateAPIKeyInput) (*domain.APIKey, error)
+	GetActiveByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	List(ctx context.Context) ([]*domain.APIKey, error)
+	RevokeByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+}
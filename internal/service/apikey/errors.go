@@ -0,0 +1,17 @@
+package apikey
+
+import "errors"
+
+var (
+	// ErrInvalidKey возвращается, когда ключ неизвестен, отозван или повреждён
+	ErrInvalidKey = errors.New("invalid api key")
+
+	// ErrKeyNotFound возвращается, когда действующий ключ с указанным префиксом не найден
+	ErrKeyNotFound = errors.New("api key not found")
+
+	// ErrInvalidInput возвращается при некорректных входных данных
+	ErrInvalidInput = errors.New("invalid input data")
+
+	// ErrInternal возвращается при внутренних ошибках сервиса
+	ErrInternal = errors.New("service: internal error")
+)
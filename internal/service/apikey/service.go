@@ -0,0 +1,131 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	apiKeyRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/api_key"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/apikey"
+)
+
+// maxNameLength ограничение колонки api_keys.name
+const maxNameLength = 100
+
+type Service struct {
+	repo APIKeyRepository
+}
+
+func NewService(repo APIKeyRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Issue выпускает ключ для внутреннего сервиса name с правами scopes
+// Открытое значение ключа возвращается только здесь, в БД сохраняется его хеш
+func (s *Service) Issue(ctx context.Context, name string, scopes []string) (string, *domain.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return "", nil, fmt.Errorf("%w: name is required and must be at most %d characters", ErrInvalidInput, maxNameLength)
+	}
+
+	parsedScopes, err := parseScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rawKey, err := apikey.Generate()
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: Issue - generate key: %v", ErrInternal, err)
+	}
+
+	prefix, err := apikey.Prefix(rawKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: Issue - key prefix: %v", ErrInternal, err)
+	}
+
+	key, err := s.repo.Create(ctx, domain.CreateAPIKeyInput{
+		Name:   name,
+		Prefix: prefix,
+		Hash:   apikey.Hash(rawKey),
+		Scopes: parsedScopes,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: Issue - repository error: %v", ErrInternal, err)
+	}
+
+	return rawKey, key, nil
+}
+
+// Revoke отзывает действующий ключ по префиксу
+func (s *Service) Revoke(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	key, err := s.repo.RevokeByPrefix(ctx, strings.TrimSpace(prefix))
+	if err != nil {
+		if errors.Is(err, apiKeyRepo.ErrKeyNotFound) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("%w: Revoke - repository error: %v", ErrInternal, err)
+	}
+
+	return key, nil
+}
+
+// List возвращает все выпущенные ключи, включая отозванные
+func (s *Service) List(ctx context.Context) ([]*domain.APIKey, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: List - repository error: %v", ErrInternal, err)
+	}
+
+	return keys, nil
+}
+
+// Authenticate находит действующий ключ по его открытому значению
+func (s *Service) Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	if _, err := apikey.Prefix(rawKey); err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := s.repo.GetActiveByHash(ctx, apikey.Hash(rawKey))
+	if err != nil {
+		if errors.Is(err, apiKeyRepo.ErrKeyNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, fmt.Errorf("%w: Authenticate - repository error: %v", ErrInternal, err)
+	}
+
+	return key, nil
+}
+
+// parseScopes проверяет, что все права поддерживаются, и убирает повторы
+func parseScopes(scopes []string) ([]domain.APIKeyScope, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidInput)
+	}
+
+	result := make([]domain.APIKeyScope, 0, len(scopes))
+	seen := make(map[domain.APIKeyScope]bool, len(scopes))
+
+	for _, value := range scopes {
+		scope := domain.APIKeyScope(strings.TrimSpace(value))
+
+		supported := false
+		for _, known := range domain.APIKeyScopes {
+			if scope == known {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("%w: unsupported scope %q", ErrInvalidInput, value)
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return result, nil
+}
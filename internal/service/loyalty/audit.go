@@ -0,0 +1,174 @@
+package loyalty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/jsondiff"
+)
+
+// auditIgnoredFields поля снимков, которые меняются при любом изменении и не попадают в changes
+var auditIgnoredFields = []string{"updated_at"}
+
+// auditChange изменение сущности для записи в журнал
+type auditChange struct {
+	companyID  int64
+	entityType domain.AuditEntityType
+	entityID   int64
+	action     domain.AuditAction
+	// before и after снимки сущности (before nil - сущность создана)
+	before interface{}
+	after  interface{}
+}
+
+// recordAudit записывает изменение в журнал от имени actor
+// Изменения фонового процесса (role system) записываются без автора, actor не учитывается.
+// Вызывается внутри транзакции изменения: ошибка записи откатывает и само изменение
+func (s *Service) recordAudit(ctx context.Context, actor models.Actor, role domain.ActorRole, change auditChange) error {
+	var before json.RawMessage
+	if change.before != nil {
+		data, err := json.Marshal(change.before)
+		if err != nil {
+			return fmt.Errorf("%w: recordAudit - marshal before: %v", ErrInternal, err)
+		}
+		before = data
+	}
+
+	after, err := json.Marshal(change.after)
+	if err != nil {
+		return fmt.Errorf("%w: recordAudit - marshal after: %v", ErrInternal, err)
+	}
+
+	diff, err := jsondiff.Diff(before, after, auditIgnoredFields...)
+	if err != nil {
+		return fmt.Errorf("%w: recordAudit - diff: %v", ErrInternal, err)
+	}
+
+	changes, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("%w: recordAudit - marshal changes: %v", ErrInternal, err)
+	}
+
+	input := domain.CreateAuditEventInput{
+		CompanyID:  change.companyID,
+		EntityType: change.entityType,
+		EntityID:   change.entityID,
+		Action:     change.action,
+		ActorRole:  role,
+		Before:     before,
+		After:      after,
+		Changes:    changes,
+	}
+	if role != domain.ActorRoleSystem {
+		input.ActorID, input.ActorService = actorAuthor(actor)
+	}
+
+	if _, err := s.auditRepo.Create(ctx, input); err != nil {
+		return fmt.Errorf("%w: recordAudit - failed to create event: %v", ErrInternal, err)
+	}
+
+	return nil
+}
+
+// configAuditChange описывает создание или изменение конфигурации программы
+func configAuditChange(existing, updated *domain.LoyaltyConfig, cardsUpdated *int64) auditChange {
+	change := auditChange{
+		companyID:  updated.CompanyID,
+		entityType: domain.AuditEntityLoyaltyConfig,
+		entityID:   updated.ID,
+		action:     domain.AuditActionConfigCreated,
+	}
+
+	if existing != nil {
+		change.action = domain.AuditActionConfigUpdated
+		change.before = models.FromDomainLoyaltyConfig(existing)
+	}
+
+	after := models.FromDomainLoyaltyConfig(updated)
+	after.CardsUpdated = cardsUpdated
+	change.after = after
+
+	return change
+}
+
+// cardAuditChange описывает изменение карты действием action (before nil - карта выпущена)
+func cardAuditChange(action domain.AuditAction, before, after *domain.LoyaltyCard) auditChange {
+	change := auditChange{
+		companyID:  after.CompanyID,
+		entityType: domain.AuditEntityLoyaltyCard,
+		entityID:   after.ID,
+		action:     action,
+		after:      models.FromDomainLoyaltyCard(after),
+	}
+
+	if before != nil {
+		change.before = models.FromDomainLoyaltyCard(before)
+	}
+
+	return change
+}
+
+// ListAuditEvents возвращает страницу журнала изменений компании от новых событий к старым
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) ListAuditEvents(ctx context.Context, companyID int64, actor models.Actor, req *models.ListAuditEventsRequest) (*models.AuditEventsResponse, error) {
+	input := domain.ListAuditEventsInput{
+		CompanyID: companyID,
+		EntityID:  req.EntityID,
+		Limit:     models.DefaultAuditEventsLimit,
+	}
+
+	if req.Limit < 0 || req.Limit > models.MaxAuditEventsLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, models.MaxAuditEventsLimit)
+	}
+	if req.Limit > 0 {
+		input.Limit = req.Limit
+	}
+
+	if req.EntityType != nil {
+		entityType := domain.AuditEntityType(*req.EntityType)
+		if !entityType.IsValid() {
+			return nil, fmt.Errorf("%w: unsupported entity_type %q", ErrInvalidInput, *req.EntityType)
+		}
+		input.EntityType = &entityType
+	}
+
+	if req.Cursor != nil {
+		beforeID, err := decodeIDCursor(*req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		input.BeforeID = &beforeID
+	}
+
+	if _, err := s.checkManagerAccess(ctx, companyID, actor, "ListAuditEvents"); err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одно событие больше, чтобы понять, есть ли следующая страница
+	pageSize := input.Limit
+	input.Limit++
+
+	events, err := s.auditRepo.ListByCompany(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListAuditEvents - failed to list events: %v", ErrInternal, err)
+	}
+
+	resp := &models.AuditEventsResponse{
+		Events: make([]*models.AuditEventResponse, 0, pageSize),
+	}
+
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		cursor := encodeIDCursor(events[pageSize-1].ID)
+		resp.NextCursor = &cursor
+	}
+
+	for _, event := range events {
+		resp.Events = append(resp.Events, models.FromDomainAuditEvent(event))
+	}
+
+	return resp, nil
+}
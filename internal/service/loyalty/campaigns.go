@@ -0,0 +1,180 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	campaignRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_campaign"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// CreateCampaign создаёт промо-акцию компании
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) CreateCampaign(ctx context.Context, companyID int64, actor models.Actor, req *models.CreateCampaignRequest) (*models.CampaignResponse, error) {
+	role, err := s.checkManagerAccess(ctx, companyID, actor, "CreateCampaign")
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := req.ToDomain(companyID)
+	campaign.CreatedBy = actor.UserID
+	campaign.CreatedByRole = role
+
+	if err := campaign.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	if !campaign.EndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: campaign ends_at must be in the future", ErrInvalidInput)
+	}
+
+	// Акция и запись журнала сохраняются в одной транзакции
+	var created *domain.LoyaltyCampaign
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		created, err = s.campaignRepo.Create(ctx, campaign)
+		if err != nil {
+			return fmt.Errorf("%w: CreateCampaign - failed to create campaign: %v", ErrInternal, err)
+		}
+
+		return s.recordAudit(ctx, actor, role, auditChange{
+			companyID:  companyID,
+			entityType: domain.AuditEntityLoyaltyCampaign,
+			entityID:   created.ID,
+			action:     domain.AuditActionCampaignCreated,
+			after:      models.FromDomainLoyaltyCampaign(created),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.FromDomainLoyaltyCampaign(created), nil
+}
+
+// ListCampaigns возвращает акции компании в порядке начала периода
+// Завершившиеся акции возвращаются только с req.IncludeFinished. Доступно менеджеру компании и суперпользователю
+func (s *Service) ListCampaigns(ctx context.Context, companyID int64, actor models.Actor, req *models.ListCampaignsRequest) (*models.CampaignsResponse, error) {
+	if _, err := s.checkManagerAccess(ctx, companyID, actor, "ListCampaigns"); err != nil {
+		return nil, err
+	}
+
+	input := domain.ListLoyaltyCampaignsInput{CompanyID: companyID}
+	if !req.IncludeFinished {
+		now := time.Now()
+		input.EndsAfter = &now
+	}
+
+	campaigns, err := s.campaignRepo.ListByCompany(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListCampaigns - failed to list campaigns: %v", ErrInternal, err)
+	}
+
+	resp := &models.CampaignsResponse{
+		CompanyID: companyID,
+		Campaigns: make([]*models.CampaignResponse, 0, len(campaigns)),
+	}
+	for _, campaign := range campaigns {
+		resp.Campaigns = append(resp.Campaigns, models.FromDomainLoyaltyCampaign(campaign))
+	}
+
+	return resp, nil
+}
+
+// DeleteCampaign удаляет акцию компании; акция перестаёт действовать сразу
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) DeleteCampaign(ctx context.Context, companyID, campaignID int64, actor models.Actor) (*models.CampaignResponse, error) {
+	role, err := s.checkManagerAccess(ctx, companyID, actor, "DeleteCampaign")
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted *domain.LoyaltyCampaign
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		deleted, err = s.campaignRepo.Delete(ctx, companyID, campaignID)
+		if err != nil {
+			if errors.Is(err, campaignRepo.ErrCampaignNotFound) {
+				return ErrCampaignNotFound
+			}
+			return fmt.Errorf("%w: DeleteCampaign - failed to delete campaign: %v", ErrInternal, err)
+		}
+
+		return s.recordAudit(ctx, actor, role, auditChange{
+			companyID:  companyID,
+			entityType: domain.AuditEntityLoyaltyCampaign,
+			entityID:   deleted.ID,
+			action:     domain.AuditActionCampaignDeleted,
+			before:     models.FromDomainLoyaltyCampaign(deleted),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.FromDomainLoyaltyCampaign(deleted), nil
+}
+
+// activeCampaigns возвращает акции компании, период которых включает момент at
+// Дни недели и интервал времени проверяются при применении акций (domain.ApplyCampaigns)
+func (s *Service) activeCampaigns(ctx context.Context, companyID int64, at time.Time, action string) ([]*domain.LoyaltyCampaign, error) {
+	campaigns, err := s.campaignRepo.ListByCompany(ctx, domain.ListLoyaltyCampaignsInput{
+		CompanyID: companyID,
+		ActiveAt:  &at,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s - failed to list campaigns: %v", ErrInternal, action, err)
+	}
+
+	return campaigns, nil
+}
+
+// applyPromotions дополняет ответ карты скидкой и множителем баллов с учётом «счастливых часов»
+// и акций, действующих в момент at
+// Надбавка «счастливых часов» прибавляется к скидке карты до применения акций.
+// Скидки действуют только для карт, которые их дают: для остальных итоговая скидка 0.
+// Карте накопительной системы акции дают только множитель баллов, «счастливые часы» к ней не применяются
+func (s *Service) applyPromotions(ctx context.Context, resp *models.LoyaltyCardResponse, at time.Time, action string) (domain.CampaignEffect, error) {
+	effect := domain.CampaignEffect{
+		DiscountPercentage: resp.DiscountPercentage,
+		PointsMultiplier:   1,
+	}
+
+	if domain.CardStatus(resp.Status).GrantsDiscount() {
+		campaigns, err := s.activeCampaigns(ctx, resp.CompanyID, at, action)
+		if err != nil {
+			return effect, err
+		}
+
+		if resp.CardType == string(domain.CardTypePointsBased) {
+			effect = domain.ApplyPointsCampaigns(campaigns, at)
+		} else {
+			baseDiscount := resp.DiscountPercentage
+
+			happyHours, window, err := s.activeHappyHour(ctx, resp.CompanyID, at, action)
+			if err != nil {
+				return effect, err
+			}
+			if window != nil {
+				baseDiscount = window.ApplyTo(baseDiscount)
+				resp.HappyHour = models.FromDomainCardHappyHour(happyHours, window)
+			}
+
+			effect = domain.ApplyCampaigns(baseDiscount, campaigns, at)
+		}
+	}
+
+	resp.EffectiveDiscountPercentage = &effect.DiscountPercentage
+	if effect.DiscountCampaign != nil {
+		resp.DiscountCampaignID = &effect.DiscountCampaign.ID
+	}
+	if resp.CardType == string(domain.CardTypePointsBased) {
+		resp.PointsMultiplier = &effect.PointsMultiplier
+	}
+	for _, campaign := range effect.Active {
+		resp.Campaigns = append(resp.Campaigns, models.FromDomainCardCampaign(campaign))
+	}
+
+	return effect, nil
+}
@@ -0,0 +1,111 @@
+package loyalty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+func TestService_applyPromotions(t *testing.T) {
+	const companyID = 1
+	// 2025-06-03 - вторник
+	at := time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	discountCampaign := &domain.LoyaltyCampaign{
+		ID:                 10,
+		CompanyID:          companyID,
+		StartsAt:           at.Add(-time.Hour),
+		EndsAt:             at.Add(time.Hour),
+		Timezone:           "UTC",
+		DiscountPercentage: 20,
+		PointsMultiplier:   1,
+		StackingRule:       domain.CampaignStackingMax,
+	}
+	pointsCampaign := &domain.LoyaltyCampaign{
+		ID:               11,
+		CompanyID:        companyID,
+		StartsAt:         at.Add(-time.Hour),
+		EndsAt:           at.Add(time.Hour),
+		Timezone:         "UTC",
+		PointsMultiplier: 3,
+		StackingRule:     domain.CampaignStackingMax,
+	}
+
+	service := &Service{
+		campaignRepo: &fakeCampaignRepo{campaigns: []*domain.LoyaltyCampaign{discountCampaign, pointsCampaign}},
+		happyHoursRepo: &fakeHappyHoursRepo{happyHours: map[int64]*domain.HappyHours{
+			companyID: {
+				CompanyID: companyID,
+				Timezone:  "UTC",
+				Windows:   []domain.HappyHourWindow{{Weekday: 2, Start: "11:00", End: "13:00", DiscountPercentage: 15}},
+			},
+		}},
+	}
+
+	t.Run("discount card", func(t *testing.T) {
+		resp := &models.LoyaltyCardResponse{
+			CompanyID:          companyID,
+			CardType:           string(domain.CardTypeFixedDiscount),
+			Status:             string(domain.CardStatusActive),
+			DiscountPercentage: 10,
+		}
+
+		effect, err := service.applyPromotions(context.Background(), resp, at, "Test")
+		require.NoError(t, err)
+
+		// Надбавка «счастливых часов» 10 + 15 = 25 больше скидки акции
+		assert.Equal(t, 25.0, effect.DiscountPercentage)
+		assert.Nil(t, effect.DiscountCampaign)
+		require.NotNil(t, resp.EffectiveDiscountPercentage)
+		assert.Equal(t, 25.0, *resp.EffectiveDiscountPercentage)
+		assert.NotNil(t, resp.HappyHour)
+		assert.Nil(t, resp.PointsMultiplier)
+		assert.Len(t, resp.Campaigns, 2)
+	})
+
+	t.Run("points card keeps only multiplier", func(t *testing.T) {
+		resp := &models.LoyaltyCardResponse{
+			CompanyID: companyID,
+			CardType:  string(domain.CardTypePointsBased),
+			Status:    string(domain.CardStatusActive),
+		}
+
+		effect, err := service.applyPromotions(context.Background(), resp, at, "Test")
+		require.NoError(t, err)
+
+		assert.Zero(t, effect.DiscountPercentage)
+		assert.Nil(t, effect.DiscountCampaign)
+		assert.Equal(t, 3.0, effect.PointsMultiplier)
+
+		require.NotNil(t, resp.EffectiveDiscountPercentage)
+		assert.Zero(t, *resp.EffectiveDiscountPercentage)
+		assert.Nil(t, resp.DiscountCampaignID)
+		assert.Nil(t, resp.HappyHour)
+		require.NotNil(t, resp.PointsMultiplier)
+		assert.Equal(t, 3.0, *resp.PointsMultiplier)
+		require.Len(t, resp.Campaigns, 1)
+		assert.Equal(t, pointsCampaign.ID, resp.Campaigns[0].CampaignID)
+	})
+
+	t.Run("inactive card", func(t *testing.T) {
+		resp := &models.LoyaltyCardResponse{
+			CompanyID: companyID,
+			CardType:  string(domain.CardTypeFixedDiscount),
+			Status:    string(domain.CardStatusSuspended),
+		}
+
+		effect, err := service.applyPromotions(context.Background(), resp, at, "Test")
+		require.NoError(t, err)
+
+		assert.Zero(t, effect.DiscountPercentage)
+		assert.Equal(t, 1.0, effect.PointsMultiplier)
+		assert.Nil(t, resp.HappyHour)
+		assert.Empty(t, resp.Campaigns)
+	})
+}
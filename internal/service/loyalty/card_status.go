@@ -0,0 +1,167 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	cardRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_card"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// cardExpiryReason причина смены статуса, сохраняемая в карте при истечении срока действия
+const cardExpiryReason = "card validity period ended"
+
+// ChangeCardStatus приостанавливает, выключает или реактивирует карту клиента
+// Требует проверки прав: пользователь должен быть менеджером компании карты или суперпользователем
+// Допустимость перехода определяется domain.CardStatus.CanTransitionTo
+func (s *Service) ChangeCardStatus(ctx context.Context, cardID int64, actor models.Actor, action models.CardStatusAction, req *models.ChangeCardStatusRequest) (*models.LoyaltyCardResponse, error) {
+	// 1. Определяем целевой статус и валидируем причину
+	target, reasonRequired, err := statusForAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reasonRequired && reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(reason) > domain.MaxStatusReasonLength {
+		return nil, fmt.Errorf("%w: reason must be at most %d characters", ErrInvalidInput, domain.MaxStatusReasonLength)
+	}
+
+	// 2. Загружаем карту и проверяем права менеджера
+	card, err := s.cardRepo.GetByID(ctx, cardID)
+	if err != nil {
+		if errors.Is(err, cardRepo.ErrCardNotFound) {
+			return nil, ErrCardNotFound
+		}
+		return nil, fmt.Errorf("%w: ChangeCardStatus - failed to get card: %v", ErrInternal, err)
+	}
+
+	role, err := s.checkManagerAccess(ctx, card.CompanyID, actor, "ChangeCardStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Проверяем допустимость перехода (просроченная карта считается expired)
+	now := time.Now()
+	current := card.EffectiveStatus(now)
+	if !current.CanTransitionTo(target) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, current, target)
+	}
+
+	config, err := s.activeConfig(ctx, card.CompanyID, now, "ChangeCardStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Меняем статус, только если он не изменился с момента чтения карты
+	var statusReason *string
+	if reason != "" {
+		statusReason = &reason
+	}
+
+	updateInput := domain.UpdateLoyaltyCardInput{
+		CardID:              &card.ID,
+		ExpectedStatus:      &card.Status,
+		Status:              &target,
+		StatusReason:        statusReason,
+		StatusChangedBy:     &actor.UserID,
+		StatusChangedByRole: &role,
+	}
+
+	// Реактивированная истёкшая карта получает новый срок действия по текущей конфигурации
+	if current == domain.CardStatusExpired && target == domain.CardStatusActive {
+		updateInput.RenewExpiry = true
+		updateInput.ExpiresAt = config.CardExpiresAt(now)
+	}
+
+	// 5. Сохраняем новый статус и запись журнала в одной транзакции
+	var updatedCard *domain.LoyaltyCard
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		updated, err := s.cardRepo.Update(ctx, updateInput)
+		if err != nil {
+			if errors.Is(err, cardRepo.ErrCardNotFound) {
+				return fmt.Errorf("%w: card status was changed concurrently", ErrInvalidStatusTransition)
+			}
+			return fmt.Errorf("%w: ChangeCardStatus - failed to update card: %v", ErrInternal, err)
+		}
+		updatedCard = updated
+
+		return s.recordAudit(ctx, actor, role, cardAuditChange(domain.AuditActionCardStatusChanged, card, updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCardResponse(updatedCard, config), nil
+}
+
+// ExpireOverdueCards переводит в expired не более limit карт с истёкшим сроком действия от имени
+// фонового процесса и записывает смену статуса каждой карты в журнал в той же транзакции.
+// Возвращает количество переведённых карт (меньше limit - просроченных карт не осталось)
+func (s *Service) ExpireOverdueCards(ctx context.Context, limit int) (int64, error) {
+	var expired int64
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		overdue, err := s.cardRepo.LockOverdue(ctx, limit)
+		if err != nil {
+			return fmt.Errorf("%w: ExpireOverdueCards - failed to lock overdue cards: %v", ErrInternal, err)
+		}
+		if len(overdue) == 0 {
+			return nil
+		}
+
+		cardIDs := make([]int64, 0, len(overdue))
+		for _, card := range overdue {
+			cardIDs = append(cardIDs, card.ID)
+		}
+
+		cards, err := s.cardRepo.Expire(ctx, cardIDs, cardExpiryReason)
+		if err != nil {
+			return fmt.Errorf("%w: ExpireOverdueCards - failed to expire cards: %v", ErrInternal, err)
+		}
+
+		updated := make(map[int64]*domain.LoyaltyCard, len(cards))
+		for _, card := range cards {
+			updated[card.ID] = card
+		}
+
+		for _, before := range overdue {
+			after, ok := updated[before.ID]
+			if !ok {
+				continue
+			}
+			if err := s.recordAudit(ctx, models.Actor{}, domain.ActorRoleSystem, cardAuditChange(domain.AuditActionCardStatusChanged, before, after)); err != nil {
+				return err
+			}
+		}
+
+		expired = int64(len(cards))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
+}
+
+// statusForAction возвращает целевой статус для действия менеджера
+// и признак обязательности причины (для блокирующих действий)
+func statusForAction(action models.CardStatusAction) (domain.CardStatus, bool, error) {
+	switch action {
+	case models.CardStatusActionSuspend:
+		return domain.CardStatusSuspended, true, nil
+	case models.CardStatusActionDisable:
+		return domain.CardStatusDisabled, true, nil
+	case models.CardStatusActionReactivate:
+		return domain.CardStatusActive, false, nil
+	default:
+		return "", false, fmt.Errorf("%w: unsupported card status action %q", ErrInvalidInput, action)
+	}
+}
@@ -0,0 +1,89 @@
+package loyalty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+func TestService_ExpireOverdueCards(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	newCards := func() []*domain.LoyaltyCard {
+		return []*domain.LoyaltyCard{
+			{ID: 1, CompanyID: testCompanyID, Status: domain.CardStatusActive, ExpiresAt: &past},
+			{ID: 2, CompanyID: testCompanyID, Status: domain.CardStatusSuspended, ExpiresAt: &past},
+			{ID: 3, CompanyID: testCompanyID, Status: domain.CardStatusActive, ExpiresAt: &future},
+			{ID: 4, CompanyID: testCompanyID, Status: domain.CardStatusDisabled, ExpiresAt: &past},
+			{ID: 5, CompanyID: testCompanyID, Status: domain.CardStatusActive},
+		}
+	}
+
+	t.Run("expires overdue cards with system audit", func(t *testing.T) {
+		log := &callLog{}
+		cards, audit := &fakeCardRepo{cards: newCards()}, &fakeAuditRepo{}
+		service := newTestService(log, &fakeConfigRepo{}, cards, audit)
+
+		expired, err := service.ExpireOverdueCards(context.Background(), 10)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), expired)
+		assert.Equal(t, []string{
+			"begin", "card.lock_overdue", "card.expire",
+			"audit.card_status_changed", "audit.card_status_changed", "commit",
+		}, log.calls)
+
+		statuses := make(map[int64]domain.CardStatus)
+		for _, card := range cards.cards {
+			statuses[card.ID] = card.Status
+		}
+		assert.Equal(t, map[int64]domain.CardStatus{
+			1: domain.CardStatusExpired,
+			2: domain.CardStatusExpired,
+			3: domain.CardStatusActive,
+			4: domain.CardStatusDisabled,
+			5: domain.CardStatusActive,
+		}, statuses)
+
+		require.Len(t, audit.events, 2)
+		for i, event := range audit.events {
+			assert.Equal(t, int64(i+1), event.EntityID)
+			assert.Equal(t, domain.AuditEntityLoyaltyCard, event.EntityType)
+			assert.Equal(t, domain.ActorRoleSystem, event.ActorRole)
+			assert.Nil(t, event.ActorID)
+			assert.Contains(t, string(event.Changes), `"status"`)
+		}
+	})
+
+	t.Run("respects limit", func(t *testing.T) {
+		log := &callLog{}
+		cards, audit := &fakeCardRepo{cards: newCards()}, &fakeAuditRepo{}
+		service := newTestService(log, &fakeConfigRepo{}, cards, audit)
+
+		expired, err := service.ExpireOverdueCards(context.Background(), 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), expired)
+		require.Len(t, audit.events, 1)
+		assert.Equal(t, int64(1), audit.events[0].EntityID)
+	})
+
+	t.Run("nothing to expire", func(t *testing.T) {
+		log := &callLog{}
+		audit := &fakeAuditRepo{}
+		service := newTestService(log, &fakeConfigRepo{}, &fakeCardRepo{}, audit)
+
+		expired, err := service.ExpireOverdueCards(context.Background(), 10)
+		require.NoError(t, err)
+
+		assert.Zero(t, expired)
+		assert.Equal(t, []string{"begin", "card.lock_overdue", "commit"}, log.calls)
+		assert.Empty(t, audit.events)
+	})
+}
@@ -0,0 +1,215 @@
+package loyalty
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// companyCardsCursor содержимое курсора списка карт компании
+// Сортировка сохраняется в курсоре, чтобы курсор нельзя было применить к другому порядку выборки
+type companyCardsCursor struct {
+	Sort       domain.CardSortField `json:"s"`
+	Descending bool                 `json:"d"`
+	Value      string               `json:"v"`
+	ID         int64                `json:"id"`
+}
+
+// ListCompanyCards возвращает страницу карт компании для менеджера
+func (s *Service) ListCompanyCards(ctx context.Context, companyID int64, actor models.Actor, req *models.ListCompanyCardsRequest) (*models.CompanyLoyaltyCardsResponse, error) {
+	input, err := companyCardsInput(companyID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Limit < 0 || req.Limit > models.MaxCompanyCardsLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, models.MaxCompanyCardsLimit)
+	}
+	input.Limit = models.DefaultCompanyCardsLimit
+	if req.Limit > 0 {
+		input.Limit = req.Limit
+	}
+
+	if req.Cursor != nil {
+		cursor, err := decodeCompanyCardsCursor(*req.Cursor, input.SortBy, input.Descending)
+		if err != nil {
+			return nil, err
+		}
+		input.After = cursor
+	}
+
+	config, err := s.prepareCompanyCards(ctx, companyID, actor, "ListCompanyCards")
+	if err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одну карту больше, чтобы понять, есть ли следующая страница
+	pageSize := input.Limit
+	input.Limit++
+
+	cards, err := s.cardRepo.ListByCompany(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListCompanyCards - failed to list cards: %v", ErrInternal, err)
+	}
+
+	resp := &models.CompanyLoyaltyCardsResponse{
+		Cards: make([]*models.LoyaltyCardResponse, 0, pageSize),
+	}
+
+	if len(cards) > pageSize {
+		cards = cards[:pageSize]
+		last := cards[pageSize-1]
+		cursor := encodeCompanyCardsCursor(companyCardsCursor{
+			Sort:       input.SortBy,
+			Descending: input.Descending,
+			Value:      last.SortValue(input.SortBy),
+			ID:         last.ID,
+		})
+		resp.NextCursor = &cursor
+	}
+
+	for _, card := range cards {
+		resp.Cards = append(resp.Cards, buildCardResponse(card, config))
+	}
+
+	return resp, nil
+}
+
+// StreamCompanyCards передаёт в fn все карты компании, подходящие под фильтры, по одной
+// Используется для выгрузки CSV: карты не накапливаются в памяти, курсор и limit игнорируются
+func (s *Service) StreamCompanyCards(
+	ctx context.Context,
+	companyID int64,
+	actor models.Actor,
+	req *models.ListCompanyCardsRequest,
+	fn func(card *models.LoyaltyCardResponse) error,
+) error {
+	input, err := companyCardsInput(companyID, req)
+	if err != nil {
+		return err
+	}
+
+	config, err := s.prepareCompanyCards(ctx, companyID, actor, "StreamCompanyCards")
+	if err != nil {
+		return err
+	}
+
+	err = s.cardRepo.StreamByCompany(ctx, input, func(card *domain.LoyaltyCard) error {
+		return fn(buildCardResponse(card, config))
+	})
+	if err != nil {
+		return fmt.Errorf("%w: StreamCompanyCards - failed to stream cards: %w", ErrInternal, err)
+	}
+
+	return nil
+}
+
+// prepareCompanyCards проверяет права менеджера и возвращает конфигурацию программы компании
+func (s *Service) prepareCompanyCards(ctx context.Context, companyID int64, actor models.Actor, action string) (*domain.LoyaltyConfig, error) {
+	if _, err := s.checkManagerAccess(ctx, companyID, actor, action); err != nil {
+		return nil, err
+	}
+
+	return s.activeConfig(ctx, companyID, time.Now(), action)
+}
+
+// companyCardsInput проверяет фильтры и сортировку списка карт компании
+func companyCardsInput(companyID int64, req *models.ListCompanyCardsRequest) (domain.ListCompanyCardsInput, error) {
+	input := domain.ListCompanyCardsInput{
+		CompanyID:   companyID,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		SortBy:      domain.CardSortCreatedAt,
+		Descending:  true,
+	}
+
+	if req.Status != nil {
+		status, err := parseCardStatus(*req.Status)
+		if err != nil {
+			return input, err
+		}
+		input.Status = &status
+	}
+
+	if req.CardType != nil {
+		cardType, err := parseCardType(*req.CardType)
+		if err != nil {
+			return input, err
+		}
+		input.CardType = &cardType
+	}
+
+	if req.CreatedFrom != nil && req.CreatedTo != nil && !req.CreatedFrom.Before(*req.CreatedTo) {
+		return input, fmt.Errorf("%w: created_from must be before created_to", ErrInvalidInput)
+	}
+
+	switch sort := domain.CardSortField(req.Sort); sort {
+	case "":
+	case domain.CardSortCreatedAt, domain.CardSortVisitsCount, domain.CardSortPointsBalance:
+		input.SortBy = sort
+	default:
+		return input, fmt.Errorf("%w: unsupported sort field %q", ErrInvalidInput, req.Sort)
+	}
+
+	switch req.Order {
+	case "", "desc":
+	case "asc":
+		input.Descending = false
+	default:
+		return input, fmt.Errorf("%w: unsupported sort order %q", ErrInvalidInput, req.Order)
+	}
+
+	return input, nil
+}
+
+// encodeCompanyCardsCursor кодирует позицию пагинации в непрозрачную строку
+func encodeCompanyCardsCursor(cursor companyCardsCursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// decodeCompanyCardsCursor разбирает курсор и проверяет, что он выдан для той же сортировки
+func decodeCompanyCardsCursor(value string, sort domain.CardSortField, descending bool) (*domain.CardCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+
+	var cursor companyCardsCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+
+	if cursor.Sort != sort || cursor.Descending != descending {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", ErrInvalidInput)
+	}
+
+	// Значение подставляется в запрос с приведением к типу колонки сортировки,
+	// поэтому подделанный курсор должен отсекаться здесь, а не ошибкой базы
+	if err := validateCursorValue(sort, cursor.Value); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+
+	return &domain.CardCursor{Value: cursor.Value, ID: cursor.ID}, nil
+}
+
+// validateCursorValue проверяет, что значение курсора соответствует типу поля сортировки
+func validateCursorValue(sort domain.CardSortField, value string) error {
+	var err error
+	switch sort {
+	case domain.CardSortVisitsCount:
+		_, err = strconv.ParseInt(value, 10, 32)
+	case domain.CardSortPointsBalance:
+		_, err = strconv.ParseInt(value, 10, 64)
+	default:
+		_, err = time.Parse(time.RFC3339Nano, value)
+	}
+
+	return err
+}
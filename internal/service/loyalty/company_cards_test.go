@@ -0,0 +1,85 @@
+package loyalty
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+func TestDecodeCompanyCardsCursor(t *testing.T) {
+	createdAt := time.Date(2025, 6, 2, 10, 30, 0, 123, time.UTC)
+
+	t.Run("round trip", func(t *testing.T) {
+		card := &domain.LoyaltyCard{ID: 42, CreatedAt: createdAt, VisitsCount: 7, PointsBalance: 1500}
+
+		for _, sort := range []domain.CardSortField{domain.CardSortCreatedAt, domain.CardSortVisitsCount, domain.CardSortPointsBalance} {
+			encoded := encodeCompanyCardsCursor(companyCardsCursor{Sort: sort, Descending: true, Value: card.SortValue(sort), ID: card.ID})
+
+			cursor, err := decodeCompanyCardsCursor(encoded, sort, true)
+			require.NoError(t, err, sort)
+			assert.Equal(t, &domain.CardCursor{Value: card.SortValue(sort), ID: 42}, cursor, sort)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		cursor     string
+		sort       domain.CardSortField
+		descending bool
+	}{
+		{name: "not base64", cursor: "%%%", sort: domain.CardSortCreatedAt, descending: true},
+		{
+			name:       "not json",
+			cursor:     encodeCompanyCardsCursor(companyCardsCursor{})[:4],
+			sort:       domain.CardSortCreatedAt,
+			descending: true,
+		},
+		{
+			name:       "different sort",
+			cursor:     encodeCompanyCardsCursor(companyCardsCursor{Sort: domain.CardSortVisitsCount, Descending: true, Value: "7", ID: 1}),
+			sort:       domain.CardSortPointsBalance,
+			descending: true,
+		},
+		{
+			name:   "different order",
+			cursor: encodeCompanyCardsCursor(companyCardsCursor{Sort: domain.CardSortVisitsCount, Descending: true, Value: "7", ID: 1}),
+			sort:   domain.CardSortVisitsCount,
+		},
+		{
+			name:       "tampered date",
+			cursor:     encodeCompanyCardsCursor(companyCardsCursor{Sort: domain.CardSortCreatedAt, Descending: true, Value: "yesterday", ID: 1}),
+			sort:       domain.CardSortCreatedAt,
+			descending: true,
+		},
+		{
+			name:       "tampered counter",
+			cursor:     encodeCompanyCardsCursor(companyCardsCursor{Sort: domain.CardSortVisitsCount, Descending: true, Value: "7; DROP TABLE", ID: 1}),
+			sort:       domain.CardSortVisitsCount,
+			descending: true,
+		},
+		{
+			name:       "visits out of integer range",
+			cursor:     encodeCompanyCardsCursor(companyCardsCursor{Sort: domain.CardSortVisitsCount, Descending: true, Value: "9999999999", ID: 1}),
+			sort:       domain.CardSortVisitsCount,
+			descending: true,
+		},
+		{
+			name:       "fractional balance",
+			cursor:     encodeCompanyCardsCursor(companyCardsCursor{Sort: domain.CardSortPointsBalance, Descending: true, Value: "1.5", ID: 1}),
+			sort:       domain.CardSortPointsBalance,
+			descending: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeCompanyCardsCursor(tt.cursor, tt.sort, tt.descending)
+			assert.ErrorIs(t, err, ErrInvalidInput)
+			assert.Nil(t, cursor)
+		})
+	}
+}
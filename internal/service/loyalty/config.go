@@ -0,0 +1,75 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	configRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_config"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// GetLoyaltyConfig возвращает конфигурацию программы лояльности компании
+// Менеджер компании и суперпользователь получают расширенный вид со статистикой карт, остальные - публичный.
+// Если права проверить не удалось (анонимный запрос, SellerService недоступен), возвращается публичный вид
+func (s *Service) GetLoyaltyConfig(ctx context.Context, companyID int64, actor *models.Actor) (*models.LoyaltyConfigViewResponse, error) {
+	config, err := s.activeConfig(ctx, companyID, time.Now(), "GetLoyaltyConfig")
+	if err != nil {
+		return nil, err
+	}
+
+	if actor == nil {
+		return models.NewPublicLoyaltyConfigView(config), nil
+	}
+
+	if _, err := s.checkManagerAccess(ctx, companyID, *actor, "GetLoyaltyConfig"); err != nil {
+		return models.NewPublicLoyaltyConfigView(config), nil
+	}
+
+	counts, err := s.cardRepo.CountByStatus(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: GetLoyaltyConfig - failed to count cards: %v", ErrInternal, err)
+	}
+
+	return models.NewManagerLoyaltyConfigView(config, counts), nil
+}
+
+// DisableCompanyConfig выключает программу лояльности компании от имени фонового процесса
+// (компания удалена в SellerService) и отменяет её ожидающие изменения.
+// Блокировка компании исключает гонку с настройкой программы и переносом запланированных изменений.
+// Выключение записывается в журнал от имени фонового процесса в той же транзакции.
+// Возвращает false, если программа уже выключена или не настроена
+func (s *Service) DisableCompanyConfig(ctx context.Context, companyID int64) (bool, error) {
+	var disabled bool
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.configRepo.LockCompany(ctx, companyID); err != nil {
+			return fmt.Errorf("%w: DisableCompanyConfig - failed to lock company config: %v", ErrInternal, err)
+		}
+
+		existing, err := s.configRepo.GetByCompanyID(ctx, companyID)
+		if err != nil {
+			if errors.Is(err, configRepo.ErrConfigNotFound) {
+				return nil
+			}
+			return fmt.Errorf("%w: DisableCompanyConfig - failed to get config: %v", ErrInternal, err)
+		}
+		if !existing.IsEnabled {
+			return nil
+		}
+
+		config, err := s.configRepo.Disable(ctx, companyID)
+		if err != nil {
+			return fmt.Errorf("%w: DisableCompanyConfig - failed to disable config: %v", ErrInternal, err)
+		}
+		disabled = true
+
+		return s.recordAudit(ctx, models.Actor{}, domain.ActorRoleSystem, configAuditChange(existing, config, nil))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return disabled, nil
+}
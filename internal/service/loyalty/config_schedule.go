@@ -0,0 +1,372 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	configRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_config"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// activeConfig возвращает конфигурацию программы компании, действующую в момент at
+// Наступившее запланированное изменение учитывается, даже если фоновый процесс ещё не перенёс его
+func (s *Service) activeConfig(ctx context.Context, companyID int64, at time.Time, action string) (*domain.LoyaltyConfig, error) {
+	config, err := s.configRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, configRepo.ErrConfigNotFound) {
+			return nil, ErrConfigNotFound
+		}
+		return nil, fmt.Errorf("%w: %s - failed to get config: %v", ErrInternal, action, err)
+	}
+
+	return s.configAt(ctx, config, at, action)
+}
+
+// configAt накладывает на сохранённую конфигурацию ожидающие изменения, наступившие к моменту at
+func (s *Service) configAt(ctx context.Context, config *domain.LoyaltyConfig, at time.Time, action string) (*domain.LoyaltyConfig, error) {
+	configs, err := s.configsAt(ctx, map[int64]*domain.LoyaltyConfig{config.CompanyID: config}, at, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return configs[config.CompanyID], nil
+}
+
+// configsAt накладывает на сохранённые конфигурации компаний (ключ - ID компании)
+// ожидающие изменения, наступившие к моменту at, в порядке их наступления
+func (s *Service) configsAt(ctx context.Context, configs map[int64]*domain.LoyaltyConfig, at time.Time, action string) (map[int64]*domain.LoyaltyConfig, error) {
+	companyIDs := make([]int64, 0, len(configs))
+	for companyID := range configs {
+		companyIDs = append(companyIDs, companyID)
+	}
+
+	schedules, err := s.configRepo.ListDueSchedules(ctx, companyIDs, at)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s - failed to list config schedules: %v", ErrInternal, action, err)
+	}
+
+	result := make(map[int64]*domain.LoyaltyConfig, len(configs))
+	for companyID, config := range configs {
+		result[companyID] = config
+	}
+	for _, schedule := range schedules {
+		// Несовместимое изменение не действует, фоновый процесс отменит его при переносе
+		if config, err := schedule.ApplyTo(result[schedule.CompanyID]); err == nil {
+			result[schedule.CompanyID] = config
+		}
+	}
+
+	return result, nil
+}
+
+// projectSchedules возвращает конфигурацию после каждого из ожидающих изменений schedules
+// (в порядке наступления), накладывая их по очереди на сохранённую конфигурацию stored
+func projectSchedules(stored *domain.LoyaltyConfig, schedules []*domain.LoyaltyConfigSchedule) []*domain.LoyaltyConfig {
+	projected := make([]*domain.LoyaltyConfig, 0, len(schedules))
+	config := stored
+	for _, schedule := range schedules {
+		if next, err := schedule.ApplyTo(config); err == nil {
+			config = next
+		}
+		projected = append(projected, config)
+	}
+
+	return projected
+}
+
+// scheduleConfig сохраняет изменение из запроса, которое начнёт действовать с req.EffectiveFrom
+// Сохраняются только заданные в запросе поля: при наступлении они накладываются на конфигурацию,
+// действующую к этому моменту. Изменение проверяется на конфигурации, которая будет действовать
+// к req.EffectiveFrom (с учётом более ранних запланированных изменений), так же, как немедленное
+func (s *Service) scheduleConfig(
+	ctx context.Context,
+	companyID int64,
+	actor models.Actor,
+	role domain.ActorRole,
+	req *models.ConfigureLoyaltyRequest,
+) (*models.LoyaltyConfigResponse, error) {
+	var current, candidate *domain.LoyaltyConfig
+	var schedule *domain.LoyaltyConfigSchedule
+
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.configRepo.LockCompany(ctx, companyID); err != nil {
+			return fmt.Errorf("%w: ScheduleConfig - failed to lock company config: %v", ErrInternal, err)
+		}
+
+		// Запланировать изменение можно только для настроенной программы
+		stored, err := s.configRepo.GetByCompanyID(ctx, companyID)
+		if err != nil {
+			if errors.Is(err, configRepo.ErrConfigNotFound) {
+				return ErrConfigNotFound
+			}
+			return fmt.Errorf("%w: ScheduleConfig - failed to get config: %v", ErrInternal, err)
+		}
+
+		current, err = s.configAt(ctx, stored, time.Now(), "ScheduleConfig")
+		if err != nil {
+			return err
+		}
+
+		base, err := s.configAt(ctx, stored, *req.EffectiveFrom, "ScheduleConfig")
+		if err != nil {
+			return err
+		}
+
+		change, err := configChange(req)
+		if err != nil {
+			return err
+		}
+
+		candidate = change.ApplyTo(base)
+		if err := candidate.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+
+		schedule, err = s.configRepo.CreateSchedule(ctx, domain.CreateLoyaltyConfigScheduleInput{
+			CompanyID:     companyID,
+			EffectiveFrom: *req.EffectiveFrom,
+			Change:        change,
+			CreatedBy:     actor.UserID,
+			CreatedByRole: role,
+		})
+		if err != nil {
+			if errors.Is(err, configRepo.ErrScheduleAlreadyExists) {
+				return ErrConfigScheduleConflict
+			}
+			return fmt.Errorf("%w: ScheduleConfig - failed to create schedule: %v", ErrInternal, err)
+		}
+
+		return s.recordAudit(ctx, actor, role, auditChange{
+			companyID:  companyID,
+			entityType: domain.AuditEntityLoyaltyConfig,
+			entityID:   stored.ID,
+			action:     domain.AuditActionConfigScheduled,
+			before:     models.FromDomainLoyaltyConfig(base),
+			after:      models.FromDomainLoyaltyConfigSchedule(schedule, candidate),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := models.FromDomainLoyaltyConfig(current)
+	resp.ScheduledChange = models.FromDomainLoyaltyConfigSchedule(schedule, candidate)
+
+	return resp, nil
+}
+
+// ListConfigSchedules возвращает ожидающие изменения программы компании в порядке наступления
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) ListConfigSchedules(ctx context.Context, companyID int64, actor models.Actor) (*models.LoyaltyConfigSchedulesResponse, error) {
+	if _, err := s.checkManagerAccess(ctx, companyID, actor, "ListConfigSchedules"); err != nil {
+		return nil, err
+	}
+
+	resp := &models.LoyaltyConfigSchedulesResponse{
+		CompanyID: companyID,
+		Schedules: []*models.LoyaltyConfigScheduleResponse{},
+	}
+
+	// Без настроенной программы запланированных изменений нет
+	config, err := s.configRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, configRepo.ErrConfigNotFound) {
+			return resp, nil
+		}
+		return nil, fmt.Errorf("%w: ListConfigSchedules - failed to get config: %v", ErrInternal, err)
+	}
+
+	schedules, err := s.configRepo.ListPendingSchedules(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListConfigSchedules - failed to list schedules: %v", ErrInternal, err)
+	}
+
+	projected := projectSchedules(config, schedules)
+	for i, schedule := range schedules {
+		resp.Schedules = append(resp.Schedules, models.FromDomainLoyaltyConfigSchedule(schedule, projected[i]))
+	}
+
+	return resp, nil
+}
+
+// CancelConfigSchedule отменяет ожидающее изменение программы компании
+// Наступившее изменение уже действует (даже если фоновый процесс его не перенёс), поэтому отменить его нельзя.
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) CancelConfigSchedule(ctx context.Context, companyID, scheduleID int64, actor models.Actor) (*models.LoyaltyConfigScheduleResponse, error) {
+	role, err := s.checkManagerAccess(ctx, companyID, actor, "CancelConfigSchedule")
+	if err != nil {
+		return nil, err
+	}
+
+	var canceledResp *models.LoyaltyConfigScheduleResponse
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		// Блокировка исключает гонку с фоновым переносом изменений компании
+		if err := s.configRepo.LockCompany(ctx, companyID); err != nil {
+			return fmt.Errorf("%w: CancelConfigSchedule - failed to lock company config: %v", ErrInternal, err)
+		}
+
+		config, err := s.configRepo.GetByCompanyID(ctx, companyID)
+		if err != nil {
+			if errors.Is(err, configRepo.ErrConfigNotFound) {
+				return ErrConfigScheduleNotFound
+			}
+			return fmt.Errorf("%w: CancelConfigSchedule - failed to get config: %v", ErrInternal, err)
+		}
+
+		pendingSchedules, err := s.configRepo.ListPendingSchedules(ctx, companyID)
+		if err != nil {
+			return fmt.Errorf("%w: CancelConfigSchedule - failed to list schedules: %v", ErrInternal, err)
+		}
+
+		// projected - конфигурация, которая действовала бы после изменения
+		var target *domain.LoyaltyConfigSchedule
+		var projected *domain.LoyaltyConfig
+		for i, after := range projectSchedules(config, pendingSchedules) {
+			if pendingSchedules[i].ID == scheduleID {
+				target, projected = pendingSchedules[i], after
+				break
+			}
+		}
+		if target == nil {
+			return ErrConfigScheduleNotFound
+		}
+		if !target.EffectiveFrom.After(time.Now()) {
+			return ErrConfigScheduleAlreadyDue
+		}
+
+		schedule, err := s.configRepo.ResolveSchedule(ctx, companyID, scheduleID, domain.ConfigScheduleStatusCanceled)
+		if err != nil {
+			if errors.Is(err, configRepo.ErrScheduleNotFound) {
+				return ErrConfigScheduleNotFound
+			}
+			return fmt.Errorf("%w: CancelConfigSchedule - failed to cancel schedule: %v", ErrInternal, err)
+		}
+		canceledResp = models.FromDomainLoyaltyConfigSchedule(schedule, projected)
+
+		pending := *schedule
+		pending.Status = domain.ConfigScheduleStatusPending
+		pending.ResolvedAt = nil
+
+		return s.recordAudit(ctx, actor, role, auditChange{
+			companyID:  companyID,
+			entityType: domain.AuditEntityLoyaltyConfig,
+			entityID:   config.ID,
+			action:     domain.AuditActionConfigScheduleCanceled,
+			before:     models.FromDomainLoyaltyConfigSchedule(&pending, projected),
+			after:      canceledResp,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return canceledResp, nil
+}
+
+// ActivateDueConfigs переносит в конфигурацию наступившие к now изменения одной компании
+// (той, чьё изменение наступило раньше остальных). Возвращает обработанные изменения со статусом
+// applied или canceled (несовместимое изменение), пустой список - наступивших изменений нет.
+// Вызывается фоновым процессом до пустого результата
+func (s *Service) ActivateDueConfigs(ctx context.Context, now time.Time) ([]*domain.LoyaltyConfigSchedule, error) {
+	next, err := s.configRepo.GetNextDueSchedule(ctx, now)
+	if err != nil {
+		if errors.Is(err, configRepo.ErrScheduleNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: ActivateDueConfigs - failed to get due schedule: %v", ErrInternal, err)
+	}
+
+	var resolved []*domain.LoyaltyConfigSchedule
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.configRepo.LockCompany(ctx, next.CompanyID); err != nil {
+			return fmt.Errorf("%w: ActivateDueConfigs - failed to lock company config: %v", ErrInternal, err)
+		}
+
+		var err error
+		_, resolved, err = s.activateDueSchedules(ctx, next.CompanyID, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// activateDueSchedules переносит в конфигурацию компании ожидающие изменения, наступившие к now,
+// в порядке наступления. Вызывается в транзакции под блокировкой компании (LockCompany)
+// Возвращает конфигурацию после переноса (nil - программа не настроена) и обработанные изменения
+// (перенесённые и отменённые как несовместимые)
+func (s *Service) activateDueSchedules(ctx context.Context, companyID int64, now time.Time) (*domain.LoyaltyConfig, []*domain.LoyaltyConfigSchedule, error) {
+	config, err := s.configRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, configRepo.ErrConfigNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("%w: ActivateSchedules - failed to get config: %v", ErrInternal, err)
+	}
+
+	schedules, err := s.configRepo.ListPendingSchedules(ctx, companyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: ActivateSchedules - failed to list schedules: %v", ErrInternal, err)
+	}
+
+	var resolved []*domain.LoyaltyConfigSchedule
+	for _, schedule := range schedules {
+		if schedule.EffectiveFrom.After(now) {
+			break
+		}
+
+		var processed *domain.LoyaltyConfigSchedule
+		config, processed, err = s.activateSchedule(ctx, config, schedule)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolved = append(resolved, processed)
+	}
+
+	return config, resolved, nil
+}
+
+// activateSchedule переносит запланированное изменение в конфигурацию от имени его автора:
+// накладывает изменение на текущую конфигурацию, обновляет скидку выпущенных карт, отмечает изменение
+// перенесённым и записывает его в журнал. Изменение, с которым конфигурация больше не проходит проверку
+// (её успели изменить несовместимо), отменяется, и конфигурация остаётся прежней
+// Возвращает конфигурацию и изменение с итоговым статусом
+func (s *Service) activateSchedule(
+	ctx context.Context,
+	previous *domain.LoyaltyConfig,
+	schedule *domain.LoyaltyConfigSchedule,
+) (*domain.LoyaltyConfig, *domain.LoyaltyConfigSchedule, error) {
+	target, err := schedule.ApplyTo(previous)
+	if err != nil {
+		s.logger.Warn("Config schedule is incompatible with current config, canceling: company_id=%d, schedule_id=%d, error=%v",
+			schedule.CompanyID, schedule.ID, err)
+
+		canceled, err := s.configRepo.ResolveSchedule(ctx, schedule.CompanyID, schedule.ID, domain.ConfigScheduleStatusCanceled)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: ActivateSchedules - failed to cancel schedule %d: %v", ErrInternal, schedule.ID, err)
+		}
+		return previous, canceled, nil
+	}
+
+	config, err := s.saveConfig(ctx, schedule.CompanyID, previous, target, schedule.Change.IsEnabled, schedule.CreatedBy, schedule.CreatedByRole)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applied, err := s.configRepo.ResolveSchedule(ctx, schedule.CompanyID, schedule.ID, domain.ConfigScheduleStatusApplied)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: ActivateSchedules - failed to resolve schedule %d: %v", ErrInternal, schedule.ID, err)
+	}
+
+	author := models.Actor{UserID: schedule.CreatedBy}
+	if _, err := s.finishConfigChange(ctx, previous, config, author, schedule.CreatedByRole, domain.AuditActionConfigScheduleApplied); err != nil {
+		return nil, nil, err
+	}
+
+	return config, applied, nil
+}
@@ -0,0 +1,230 @@
+package loyalty
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/jsondiff"
+)
+
+// newScheduledConfigs возвращает включённую программу со скидкой 10% без запланированных изменений
+func newScheduledConfigs() *fakeConfigRepo {
+	discount := 10.0
+	return &fakeConfigRepo{config: &domain.LoyaltyConfig{
+		ID:                   1,
+		CompanyID:            testCompanyID,
+		CardType:             domain.CardTypeFixedDiscount,
+		IsEnabled:            true,
+		DiscountPercentage:   &discount,
+		DiscountUpdatePolicy: domain.DiscountUpdatePolicyNewCardsOnly,
+	}}
+}
+
+// newSchedule возвращает ожидающее изменение компании, созданное менеджером
+func newSchedule(id int64, effectiveFrom time.Time, change domain.LoyaltyConfigChange) *domain.LoyaltyConfigSchedule {
+	return &domain.LoyaltyConfigSchedule{
+		ID:            id,
+		CompanyID:     testCompanyID,
+		EffectiveFrom: effectiveFrom,
+		Status:        domain.ConfigScheduleStatusPending,
+		Change:        change,
+		CreatedBy:     testManagerID,
+		CreatedByRole: domain.ActorRoleManager,
+	}
+}
+
+func TestService_ScheduleConfig(t *testing.T) {
+	manager := models.Actor{UserID: testManagerID}
+	discount := 20.0
+	effectiveFrom := time.Now().Add(24 * time.Hour)
+
+	t.Run("stores future change under company lock", func(t *testing.T) {
+		log := &callLog{}
+		configs, audit := newScheduledConfigs(), &fakeAuditRepo{}
+		service := newTestService(log, configs, &fakeCardRepo{}, audit)
+
+		resp, err := service.ConfigureLoyalty(context.Background(), testCompanyID, manager, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &discount,
+			EffectiveFrom:      &effectiveFrom,
+		})
+		require.NoError(t, err)
+
+		// Ответ - действующая конфигурация, изменение отдаётся отдельно
+		assert.Equal(t, 10.0, resp.DiscountPercentage)
+		require.NotNil(t, resp.ScheduledChange)
+		assert.Equal(t, discount, resp.ScheduledChange.DiscountPercentage)
+		assert.Equal(t, []string{"discount_percentage"}, resp.ScheduledChange.ChangedFields)
+
+		assert.Equal(t, 10.0, *configs.config.DiscountPercentage)
+		require.Len(t, configs.schedules, 1)
+		assert.Equal(t, domain.LoyaltyConfigChange{DiscountPercentage: &discount}, configs.schedules[0].Change)
+
+		assert.Equal(t, []string{
+			"begin", "config.lock", "config.get", "config.create_schedule", "audit.config_scheduled", "commit",
+		}, log.calls)
+	})
+
+	t.Run("conflicting schedule", func(t *testing.T) {
+		log := &callLog{}
+		configs := newScheduledConfigs()
+		configs.schedules = []*domain.LoyaltyConfigSchedule{newSchedule(1, effectiveFrom, domain.LoyaltyConfigChange{})}
+		service := newTestService(log, configs, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.ConfigureLoyalty(context.Background(), testCompanyID, manager, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &discount,
+			EffectiveFrom:      &effectiveFrom,
+		})
+		require.ErrorIs(t, err, ErrConfigScheduleConflict)
+		assert.Equal(t, "rollback", log.calls[len(log.calls)-1])
+	})
+
+	t.Run("program not configured", func(t *testing.T) {
+		log := &callLog{}
+		service := newTestService(log, &fakeConfigRepo{}, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.ConfigureLoyalty(context.Background(), testCompanyID, manager, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &discount,
+			EffectiveFrom:      &effectiveFrom,
+		})
+		require.ErrorIs(t, err, ErrConfigNotFound)
+	})
+}
+
+func TestService_activeConfig(t *testing.T) {
+	now := time.Now()
+	due, future := 20.0, 30.0
+
+	configs := newScheduledConfigs()
+	configs.schedules = []*domain.LoyaltyConfigSchedule{
+		newSchedule(1, now.Add(-time.Minute), domain.LoyaltyConfigChange{DiscountPercentage: &due}),
+		newSchedule(2, now.Add(time.Hour), domain.LoyaltyConfigChange{DiscountPercentage: &future}),
+	}
+	service := newTestService(&callLog{}, configs, &fakeCardRepo{}, &fakeAuditRepo{})
+
+	// Наступившее изменение действует до переноса фоновым процессом, будущее - ещё нет
+	config, err := service.activeConfig(context.Background(), testCompanyID, now, "Test")
+	require.NoError(t, err)
+	assert.Equal(t, due, *config.DiscountPercentage)
+
+	config, err = service.activeConfig(context.Background(), testCompanyID, now.Add(2*time.Hour), "Test")
+	require.NoError(t, err)
+	assert.Equal(t, future, *config.DiscountPercentage)
+
+	assert.Equal(t, 10.0, *configs.config.DiscountPercentage)
+}
+
+func TestService_ActivateDueConfigs(t *testing.T) {
+	now := time.Now()
+	due := 20.0
+	progressive := domain.CardTypeProgressiveDiscount
+
+	t.Run("applies due changes and cancels incompatible ones", func(t *testing.T) {
+		log := &callLog{}
+		configs, audit := newScheduledConfigs(), &fakeAuditRepo{}
+		configs.schedules = []*domain.LoyaltyConfigSchedule{
+			newSchedule(1, now.Add(-2*time.Minute), domain.LoyaltyConfigChange{DiscountPercentage: &due}),
+			// Прогрессивная система без уровней не проходит проверку
+			newSchedule(2, now.Add(-time.Minute), domain.LoyaltyConfigChange{CardType: &progressive}),
+			newSchedule(3, now.Add(time.Hour), domain.LoyaltyConfigChange{DiscountPercentage: &due}),
+		}
+		service := newTestService(log, configs, &fakeCardRepo{}, audit)
+
+		resolved, err := service.ActivateDueConfigs(context.Background(), now)
+		require.NoError(t, err)
+
+		require.Len(t, resolved, 2)
+		assert.Equal(t, domain.ConfigScheduleStatusApplied, resolved[0].Status)
+		assert.Equal(t, domain.ConfigScheduleStatusCanceled, resolved[1].Status)
+		assert.Equal(t, domain.ConfigScheduleStatusPending, configs.schedules[2].Status)
+
+		assert.Equal(t, domain.CardTypeFixedDiscount, configs.config.CardType)
+		assert.Equal(t, due, *configs.config.DiscountPercentage)
+
+		assert.Equal(t, []string{
+			"begin", "config.lock", "config.get",
+			"config.update", "config.resolve_schedule.applied", "audit.config_schedule_applied",
+			"config.resolve_schedule.canceled",
+			"commit",
+		}, log.calls)
+
+		// Перенос записывается в журнал от имени автора изменения
+		require.Len(t, audit.events, 1)
+		require.NotNil(t, audit.events[0].ActorID)
+		assert.Equal(t, int64(testManagerID), *audit.events[0].ActorID)
+		assert.Equal(t, domain.ActorRoleManager, audit.events[0].ActorRole)
+	})
+
+	t.Run("nothing due", func(t *testing.T) {
+		log := &callLog{}
+		configs := newScheduledConfigs()
+		configs.schedules = []*domain.LoyaltyConfigSchedule{
+			newSchedule(1, now.Add(time.Hour), domain.LoyaltyConfigChange{DiscountPercentage: &due}),
+		}
+		service := newTestService(log, configs, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		resolved, err := service.ActivateDueConfigs(context.Background(), now)
+		require.NoError(t, err)
+
+		assert.Empty(t, resolved)
+		assert.Empty(t, log.calls)
+	})
+}
+
+func TestService_CancelConfigSchedule(t *testing.T) {
+	manager := models.Actor{UserID: testManagerID}
+	discount := 20.0
+
+	t.Run("cancels pending change under company lock", func(t *testing.T) {
+		log := &callLog{}
+		configs, audit := newScheduledConfigs(), &fakeAuditRepo{}
+		configs.schedules = []*domain.LoyaltyConfigSchedule{
+			newSchedule(1, time.Now().Add(time.Hour), domain.LoyaltyConfigChange{DiscountPercentage: &discount}),
+		}
+		service := newTestService(log, configs, &fakeCardRepo{}, audit)
+
+		resp, err := service.CancelConfigSchedule(context.Background(), testCompanyID, 1, manager)
+		require.NoError(t, err)
+
+		assert.Equal(t, string(domain.ConfigScheduleStatusCanceled), resp.Status)
+		assert.Equal(t, discount, resp.DiscountPercentage)
+		assert.Equal(t, domain.ConfigScheduleStatusCanceled, configs.schedules[0].Status)
+		assert.Equal(t, []string{
+			"begin", "config.lock", "config.get", "config.resolve_schedule.canceled", "audit.config_schedule_canceled", "commit",
+		}, log.calls)
+		require.Len(t, audit.events, 1)
+		var changes map[string]jsondiff.Change
+		require.NoError(t, json.Unmarshal(audit.events[0].Changes, &changes))
+		assert.JSONEq(t, `"pending"`, string(changes["status"].Before))
+		assert.JSONEq(t, `"canceled"`, string(changes["status"].After))
+	})
+
+	t.Run("due change cannot be canceled", func(t *testing.T) {
+		log := &callLog{}
+		configs := newScheduledConfigs()
+		configs.schedules = []*domain.LoyaltyConfigSchedule{
+			newSchedule(1, time.Now().Add(-time.Minute), domain.LoyaltyConfigChange{DiscountPercentage: &discount}),
+		}
+		service := newTestService(log, configs, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.CancelConfigSchedule(context.Background(), testCompanyID, 1, manager)
+		require.ErrorIs(t, err, ErrConfigScheduleAlreadyDue)
+
+		assert.Equal(t, domain.ConfigScheduleStatusPending, configs.schedules[0].Status)
+		assert.Equal(t, "rollback", log.calls[len(log.calls)-1])
+	})
+
+	t.Run("unknown change", func(t *testing.T) {
+		log := &callLog{}
+		service := newTestService(log, newScheduledConfigs(), &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.CancelConfigSchedule(context.Background(), testCompanyID, 1, manager)
+		require.ErrorIs(t, err, ErrConfigScheduleNotFound)
+	})
+}
@@ -0,0 +1,73 @@
+package loyalty
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+func TestService_DisableCompanyConfig(t *testing.T) {
+	discount := 10.0
+	enabledConfig := func() *domain.LoyaltyConfig {
+		return &domain.LoyaltyConfig{
+			ID:                   1,
+			CompanyID:            testCompanyID,
+			CardType:             domain.CardTypeFixedDiscount,
+			IsEnabled:            true,
+			DiscountPercentage:   &discount,
+			DiscountUpdatePolicy: domain.DiscountUpdatePolicyNewCardsOnly,
+		}
+	}
+
+	t.Run("disables under company lock with system audit", func(t *testing.T) {
+		log := &callLog{}
+		configs, audit := &fakeConfigRepo{config: enabledConfig()}, &fakeAuditRepo{}
+		service := newTestService(log, configs, &fakeCardRepo{}, audit)
+
+		disabled, err := service.DisableCompanyConfig(context.Background(), testCompanyID)
+		require.NoError(t, err)
+
+		assert.True(t, disabled)
+		assert.False(t, configs.config.IsEnabled)
+		assert.Equal(t, []string{
+			"begin", "config.lock", "config.get", "config.disable", "audit.config_updated", "commit",
+		}, log.calls)
+
+		require.Len(t, audit.events, 1)
+		event := audit.events[0]
+		assert.Equal(t, domain.ActorRoleSystem, event.ActorRole)
+		assert.Nil(t, event.ActorID)
+		assert.Nil(t, event.ActorService)
+		assert.JSONEq(t, `{"is_enabled": {"before": true, "after": false}}`, string(event.Changes))
+	})
+
+	t.Run("already disabled", func(t *testing.T) {
+		log := &callLog{}
+		config := enabledConfig()
+		config.IsEnabled = false
+		audit := &fakeAuditRepo{}
+		service := newTestService(log, &fakeConfigRepo{config: config}, &fakeCardRepo{}, audit)
+
+		disabled, err := service.DisableCompanyConfig(context.Background(), testCompanyID)
+		require.NoError(t, err)
+
+		assert.False(t, disabled)
+		assert.Equal(t, []string{"begin", "config.lock", "config.get", "commit"}, log.calls)
+		assert.Empty(t, audit.events)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		log := &callLog{}
+		service := newTestService(log, &fakeConfigRepo{}, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		disabled, err := service.DisableCompanyConfig(context.Background(), testCompanyID)
+		require.NoError(t, err)
+
+		assert.False(t, disabled)
+		assert.Equal(t, []string{"begin", "config.lock", "config.get", "commit"}, log.calls)
+	})
+}
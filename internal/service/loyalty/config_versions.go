@@ -0,0 +1,86 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	configRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_config"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// ListConfigVersions возвращает страницу истории версий конфигурации компании от новых версий к старым
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) ListConfigVersions(ctx context.Context, companyID int64, actor models.Actor, req *models.ListConfigVersionsRequest) (*models.LoyaltyConfigVersionsResponse, error) {
+	input := domain.ListLoyaltyConfigVersionsInput{
+		CompanyID: companyID,
+		Limit:     models.DefaultConfigVersionsLimit,
+	}
+
+	if req.Limit < 0 || req.Limit > models.MaxConfigVersionsLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, models.MaxConfigVersionsLimit)
+	}
+	if req.Limit > 0 {
+		input.Limit = req.Limit
+	}
+
+	if req.Cursor != nil {
+		beforeVersion, err := decodeIDCursor(*req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		version := int(beforeVersion)
+		input.BeforeVersion = &version
+	}
+
+	if _, err := s.checkManagerAccess(ctx, companyID, actor, "ListConfigVersions"); err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одну версию больше, чтобы понять, есть ли следующая страница
+	pageSize := input.Limit
+	input.Limit++
+
+	versions, err := s.configRepo.ListVersions(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListConfigVersions - failed to list versions: %v", ErrInternal, err)
+	}
+
+	resp := &models.LoyaltyConfigVersionsResponse{
+		CompanyID: companyID,
+		Versions:  make([]*models.LoyaltyConfigVersionResponse, 0, pageSize),
+	}
+
+	if len(versions) > pageSize {
+		versions = versions[:pageSize]
+		cursor := encodeIDCursor(int64(versions[pageSize-1].Version))
+		resp.NextCursor = &cursor
+	}
+
+	for _, version := range versions {
+		resp.Versions = append(resp.Versions, models.FromDomainLoyaltyConfigVersion(version))
+	}
+
+	return resp, nil
+}
+
+// RestoreConfigVersion возвращает программу лояльности компании к сохранённой версии
+// Параметры версии применяются как запрос настройки программы: с той же проверкой, обновлением скидки
+// выпущенных карт по политике версии и записью в журнал. Восстановление создаёт новую версию
+func (s *Service) RestoreConfigVersion(ctx context.Context, companyID int64, version int, actor models.Actor) (*models.LoyaltyConfigResponse, error) {
+	role, err := s.checkManagerAccess(ctx, companyID, actor, "RestoreConfigVersion")
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.configRepo.GetVersion(ctx, companyID, version)
+	if err != nil {
+		if errors.Is(err, configRepo.ErrVersionNotFound) {
+			return nil, ErrConfigVersionNotFound
+		}
+		return nil, fmt.Errorf("%w: RestoreConfigVersion - failed to get version: %v", ErrInternal, err)
+	}
+
+	return s.applyConfig(ctx, companyID, actor, role, models.NewRestoreConfigRequest(saved), domain.AuditActionConfigRestored)
+}
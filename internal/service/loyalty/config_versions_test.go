@@ -0,0 +1,95 @@
+package loyalty
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+func TestService_RestoreConfigVersion(t *testing.T) {
+	manager := models.Actor{UserID: testManagerID}
+	current, saved := 10.0, 15.0
+
+	newConfigs := func() *fakeConfigRepo {
+		return &fakeConfigRepo{
+			config: &domain.LoyaltyConfig{
+				ID:                   1,
+				CompanyID:            testCompanyID,
+				CardType:             domain.CardTypeFixedDiscount,
+				IsEnabled:            false,
+				DiscountPercentage:   &current,
+				DiscountUpdatePolicy: domain.DiscountUpdatePolicyNewCardsOnly,
+			},
+			versions: []*domain.LoyaltyConfigVersion{{
+				Version: 1,
+				Config: domain.LoyaltyConfig{
+					ID:                   1,
+					CompanyID:            testCompanyID,
+					CardType:             domain.CardTypeFixedDiscount,
+					IsEnabled:            true,
+					DiscountPercentage:   &saved,
+					CardValidityDays:     30,
+					DiscountUpdatePolicy: domain.DiscountUpdatePolicyNeverLower,
+				},
+				CreatedByRole: domain.ActorRoleManager,
+			}},
+		}
+	}
+
+	t.Run("restores version like a config request", func(t *testing.T) {
+		log := &callLog{}
+		configs, audit := newConfigs(), &fakeAuditRepo{}
+		cards := &fakeCardRepo{cards: []*domain.LoyaltyCard{
+			{ID: 1, CompanyID: testCompanyID, CardType: domain.CardTypeFixedDiscount, DiscountPercentage: current},
+			{ID: 2, CompanyID: testCompanyID, CardType: domain.CardTypeFixedDiscount, DiscountPercentage: 20},
+		}}
+		service := newTestService(log, configs, cards, audit)
+
+		resp, err := service.RestoreConfigVersion(context.Background(), testCompanyID, 1, manager)
+		require.NoError(t, err)
+
+		assert.Equal(t, saved, resp.DiscountPercentage)
+		assert.True(t, resp.IsEnabled)
+		assert.Equal(t, 30, configs.config.CardValidityDays)
+		assert.Equal(t, domain.DiscountUpdatePolicyNeverLower, configs.config.DiscountUpdatePolicy)
+
+		// Политика версии never_lower: скидка выше восстановленной остаётся у карты
+		require.NotNil(t, resp.CardsUpdated)
+		assert.Equal(t, int64(1), *resp.CardsUpdated)
+		assert.Equal(t, saved, cards.cards[0].DiscountPercentage)
+		assert.Equal(t, 20.0, cards.cards[1].DiscountPercentage)
+
+		assert.Equal(t, []string{
+			"begin", "config.lock", "config.get", "config.update", "card.update_discount", "audit.config_restored", "commit",
+		}, log.calls)
+		require.Len(t, audit.events, 1)
+		assert.Equal(t, domain.ActorRoleManager, audit.events[0].ActorRole)
+	})
+
+	t.Run("version not found", func(t *testing.T) {
+		log := &callLog{}
+		service := newTestService(log, newConfigs(), &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.RestoreConfigVersion(context.Background(), testCompanyID, 2, manager)
+		require.ErrorIs(t, err, ErrConfigVersionNotFound)
+
+		assert.Empty(t, log.calls)
+	})
+
+	t.Run("not a manager", func(t *testing.T) {
+		log := &callLog{}
+		configs := newConfigs()
+		service := newTestService(log, configs, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.RestoreConfigVersion(context.Background(), testCompanyID, 1, models.Actor{UserID: 99})
+		require.ErrorIs(t, err, ErrAccessDenied)
+
+		assert.Empty(t, log.calls)
+		assert.Equal(t, current, *configs.config.DiscountPercentage)
+	})
+}
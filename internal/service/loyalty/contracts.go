@@ -2,23 +2,73 @@ package loyalty
 
 import (
 	"context"
+	"time"
 
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/qrtoken"
 )
 
 // LoyaltyCardRepository интерфейс репозитория карт лояльности
 type LoyaltyCardRepository interface {
+	GetByID(ctx context.Context, cardID int64) (*domain.LoyaltyCard, error)
 	GetByUserAndCompany(ctx context.Context, userID, companyID int64) (*domain.LoyaltyCard, error)
 	Create(ctx context.Context, card *domain.LoyaltyCard) (*domain.LoyaltyCard, error)
 	Update(ctx context.Context, input domain.UpdateLoyaltyCardInput) (*domain.LoyaltyCard, error)
+	UpdateDiscountForCompany(ctx context.Context, input domain.BulkUpdateCardDiscountInput) (int64, error)
+	CountByStatus(ctx context.Context, companyID int64) (map[domain.CardStatus]int64, error)
+	ListByUser(ctx context.Context, input domain.ListUserCardsInput) ([]*domain.LoyaltyCard, error)
+	ListByCompany(ctx context.Context, input domain.ListCompanyCardsInput) ([]*domain.LoyaltyCard, error)
+	StreamByCompany(ctx context.Context, input domain.ListCompanyCardsInput, fn func(card *domain.LoyaltyCard) error) error
+	LockOverdue(ctx context.Context, limit int) ([]*domain.LoyaltyCard, error)
+	Expire(ctx context.Context, cardIDs []int64, reason string) ([]*domain.LoyaltyCard, error)
 }
 
 // LoyaltyConfigRepository интерфейс репозитория конфигураций программ лояльности
 type LoyaltyConfigRepository interface {
 	GetByCompanyID(ctx context.Context, companyID int64) (*domain.LoyaltyConfig, error)
+	GetByCompanyIDs(ctx context.Context, companyIDs []int64) (map[int64]*domain.LoyaltyConfig, error)
+	LockCompany(ctx context.Context, companyID int64) error
 	Create(ctx context.Context, input domain.CreateLoyaltyConfigInput) (*domain.LoyaltyConfig, error)
 	Update(ctx context.Context, input domain.UpdateLoyaltyConfigInput) (*domain.LoyaltyConfig, error)
+	ListVersions(ctx context.Context, input domain.ListLoyaltyConfigVersionsInput) ([]*domain.LoyaltyConfigVersion, error)
+	GetVersion(ctx context.Context, companyID int64, version int) (*domain.LoyaltyConfigVersion, error)
+	CreateSchedule(ctx context.Context, input domain.CreateLoyaltyConfigScheduleInput) (*domain.LoyaltyConfigSchedule, error)
+	ListPendingSchedules(ctx context.Context, companyID int64) ([]*domain.LoyaltyConfigSchedule, error)
+	ListDueSchedules(ctx context.Context, companyIDs []int64, at time.Time) ([]*domain.LoyaltyConfigSchedule, error)
+	GetNextDueSchedule(ctx context.Context, now time.Time) (*domain.LoyaltyConfigSchedule, error)
+	ResolveSchedule(ctx context.Context, companyID, scheduleID int64, status domain.ConfigScheduleStatus) (*domain.LoyaltyConfigSchedule, error)
+	Disable(ctx context.Context, companyID int64) (*domain.LoyaltyConfig, error)
+}
+
+// LoyaltyCampaignRepository интерфейс репозитория промо-акций компаний
+type LoyaltyCampaignRepository interface {
+	Create(ctx context.Context, campaign *domain.LoyaltyCampaign) (*domain.LoyaltyCampaign, error)
+	ListByCompany(ctx context.Context, input domain.ListLoyaltyCampaignsInput) ([]*domain.LoyaltyCampaign, error)
+	Delete(ctx context.Context, companyID, campaignID int64) (*domain.LoyaltyCampaign, error)
+}
+
+// HappyHoursRepository интерфейс репозитория «счастливых часов» компаний
+type HappyHoursRepository interface {
+	GetByCompanyID(ctx context.Context, companyID int64) (*domain.HappyHours, error)
+	Save(ctx context.Context, happyHours *domain.HappyHours) (*domain.HappyHours, error)
+}
+
+// LoyaltyTransactionRepository интерфейс репозитория журнала операций с баллами
+type LoyaltyTransactionRepository interface {
+	Accrue(ctx context.Context, input domain.CreateLoyaltyTransactionInput) (*domain.LoyaltyTransaction, error)
+	Redeem(ctx context.Context, input domain.CreateLoyaltyTransactionInput) (*domain.LoyaltyTransaction, error)
+}
+
+// LoyaltyVisitRepository интерфейс репозитория визитов по картам лояльности
+type LoyaltyVisitRepository interface {
+	Create(ctx context.Context, input domain.CreateLoyaltyVisitInput) (*domain.LoyaltyVisit, error)
+}
+
+// AuditEventRepository интерфейс репозитория журнала изменений
+type AuditEventRepository interface {
+	Create(ctx context.Context, input domain.CreateAuditEventInput) (*domain.AuditEvent, error)
+	ListByCompany(ctx context.Context, input domain.ListAuditEventsInput) ([]*domain.AuditEvent, error)
 }
 
 // SellerServiceClient интерфейс клиента для взаимодействия с SellerService
@@ -26,3 +76,22 @@ type SellerServiceClient interface {
 	// GetCompany получает данные компании по ID
 	GetCompany(ctx context.Context, companyID int64) (*sellerservice.Company, error)
 }
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
+
+// TransactionManager интерфейс менеджера транзакций
+// Репозитории получают транзакцию из контекста через dbmetrics.GetExecutor
+type TransactionManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// QRTokenSigner интерфейс выпуска и проверки подписанных QR-токенов карт
+type QRTokenSigner interface {
+	Issue(claims qrtoken.Claims) (string, time.Time, error)
+	Verify(token string) (*qrtoken.Claims, error)
+}
@@ -9,21 +9,69 @@ var (
 	// ErrCardAlreadyExists возвращается, когда карта лояльности уже существует
 	ErrCardAlreadyExists = errors.New("loyalty card already exists")
 
+	// ErrCardNotActive возвращается, когда операция недоступна для карты в текущем статусе
+	ErrCardNotActive = errors.New("loyalty card is not active")
+
+	// ErrCardBlocked возвращается, когда карта клиента уже существует, но приостановлена или выключена
+	ErrCardBlocked = errors.New("loyalty card is suspended or disabled")
+
+	// ErrInvalidStatusTransition возвращается при недопустимой смене статуса карты
+	ErrInvalidStatusTransition = errors.New("invalid card status transition")
+
+	// ErrPointsNotSupported возвращается, когда программа компании не является накопительной
+	ErrPointsNotSupported = errors.New("loyalty program is not points based")
+
+	// ErrInsufficientPoints возвращается, когда на карте недостаточно баллов для списания
+	ErrInsufficientPoints = errors.New("insufficient points balance")
+
+	// ErrInvalidQRToken возвращается, когда QR-токен повреждён, подделан или не соответствует карте
+	ErrInvalidQRToken = errors.New("invalid qr token")
+
+	// ErrQRTokenExpired возвращается, когда срок действия QR-токена истёк
+	ErrQRTokenExpired = errors.New("qr token expired")
+
 	// ErrConfigNotFound возвращается, когда программа лояльности не настроена для компании
 	ErrConfigNotFound = errors.New("loyalty program not configured for this company")
 
 	// ErrConfigDisabled возвращается, когда программа лояльности выключена
 	ErrConfigDisabled = errors.New("loyalty program is disabled for this company")
 
+	// ErrConfigVersionNotFound возвращается, когда версия конфигурации не найдена
+	ErrConfigVersionNotFound = errors.New("loyalty config version not found")
+
+	// ErrConfigScheduleNotFound возвращается, когда ожидающее изменение конфигурации не найдено
+	ErrConfigScheduleNotFound = errors.New("loyalty config schedule not found")
+
+	// ErrConfigScheduleConflict возвращается, когда на этот момент у компании уже запланировано изменение
+	ErrConfigScheduleConflict = errors.New("loyalty config change already scheduled at this time")
+
+	// ErrConfigScheduleAlreadyDue возвращается при отмене изменения, момент которого уже наступил
+	ErrConfigScheduleAlreadyDue = errors.New("loyalty config schedule is already in effect")
+
+	// ErrCampaignNotFound возвращается, когда акция компании не найдена
+	ErrCampaignNotFound = errors.New("campaign not found")
+
+	// ErrHappyHoursNotFound возвращается, когда «счастливые часы» компании не заданы
+	ErrHappyHoursNotFound = errors.New("happy hours not configured for this company")
+
 	// ErrConfigAlreadyExists возвращается, когда программа лояльности уже настроена
 	ErrConfigAlreadyExists = errors.New("loyalty program already configured for this company")
 
 	// ErrAccessDenied возвращается, когда у пользователя нет прав доступа
 	ErrAccessDenied = errors.New("access denied: user is not a manager of this company")
 
+	// ErrCompanyNotFound возвращается, когда компания удалена или не найдена в SellerService
+	ErrCompanyNotFound = errors.New("company not found")
+
 	// ErrSellerServiceUnavailable возвращается, когда SellerService недоступен
 	ErrSellerServiceUnavailable = errors.New("seller service unavailable")
 
+	// ErrSellerServiceTimeout возвращается, когда SellerService не ответил вовремя
+	ErrSellerServiceTimeout = errors.New("seller service timeout")
+
+	// ErrSellerServiceBadResponse возвращается, когда SellerService вернул некорректный ответ
+	ErrSellerServiceBadResponse = errors.New("seller service bad response")
+
 	// ErrInvalidInput возвращается при некорректных входных данных
 	ErrInvalidInput = errors.New("invalid input data")
 
@@ -0,0 +1,415 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	cardRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_card"
+	configRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_config"
+	happyHoursRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_happy_hours"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+)
+
+// callLog порядок вызовов фейков: по нему тесты проверяют, что изменения выполняются
+// в транзакции и под блокировкой компании
+type callLog struct {
+	calls []string
+}
+
+func (l *callLog) add(call string) {
+	if l != nil {
+		l.calls = append(l.calls, call)
+	}
+}
+
+// fakeTxManager отмечает в журнале вызовов начало и завершение транзакции
+// Состояние фейков при откате не восстанавливается: тесты проверяют только rollback в журнале
+type fakeTxManager struct {
+	log *callLog
+}
+
+func (m *fakeTxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.log.add("begin")
+	if err := fn(ctx); err != nil {
+		m.log.add("rollback")
+		return err
+	}
+	m.log.add("commit")
+	return nil
+}
+
+// fakeConfigRepo конфигурация одной компании, её версии и запланированные изменения в памяти
+// Методы, не нужные тестам, не реализованы (вызов паникует на встроенном nil интерфейсе)
+type fakeConfigRepo struct {
+	LoyaltyConfigRepository
+
+	log       *callLog
+	config    *domain.LoyaltyConfig
+	versions  []*domain.LoyaltyConfigVersion
+	schedules []*domain.LoyaltyConfigSchedule
+}
+
+func (r *fakeConfigRepo) GetByCompanyID(_ context.Context, companyID int64) (*domain.LoyaltyConfig, error) {
+	r.log.add("config.get")
+	if r.config == nil || r.config.CompanyID != companyID {
+		return nil, configRepo.ErrConfigNotFound
+	}
+	config := *r.config
+	return &config, nil
+}
+
+func (r *fakeConfigRepo) LockCompany(_ context.Context, _ int64) error {
+	r.log.add("config.lock")
+	return nil
+}
+
+func (r *fakeConfigRepo) Create(_ context.Context, input domain.CreateLoyaltyConfigInput) (*domain.LoyaltyConfig, error) {
+	r.log.add("config.create")
+	if r.config != nil {
+		return nil, configRepo.ErrConfigAlreadyExists
+	}
+	r.config = &domain.LoyaltyConfig{
+		ID:                   1,
+		CompanyID:            input.CompanyID,
+		CardType:             input.CardType,
+		IsEnabled:            input.IsEnabled,
+		DiscountPercentage:   input.DiscountPercentage,
+		ProgressiveConfig:    input.ProgressiveConfig,
+		PointsConfig:         input.PointsConfig,
+		CardValidityDays:     input.CardValidityDays,
+		DiscountUpdatePolicy: input.DiscountUpdatePolicy,
+	}
+	config := *r.config
+	return &config, nil
+}
+
+func (r *fakeConfigRepo) Update(_ context.Context, input domain.UpdateLoyaltyConfigInput) (*domain.LoyaltyConfig, error) {
+	r.log.add("config.update")
+	if r.config == nil || r.config.CompanyID != input.CompanyID {
+		return nil, configRepo.ErrConfigNotFound
+	}
+	if input.CardType != nil {
+		r.config.CardType = *input.CardType
+	}
+	if input.IsEnabled != nil {
+		r.config.IsEnabled = *input.IsEnabled
+	}
+	r.config.DiscountPercentage = input.DiscountPercentage
+	r.config.ProgressiveConfig = input.ProgressiveConfig
+	r.config.PointsConfig = input.PointsConfig
+	if input.CardValidityDays != nil {
+		r.config.CardValidityDays = *input.CardValidityDays
+	}
+	if input.DiscountUpdatePolicy != nil {
+		r.config.DiscountUpdatePolicy = *input.DiscountUpdatePolicy
+	}
+	config := *r.config
+	return &config, nil
+}
+
+func (r *fakeConfigRepo) GetVersion(_ context.Context, companyID int64, version int) (*domain.LoyaltyConfigVersion, error) {
+	for _, saved := range r.versions {
+		if saved.Config.CompanyID == companyID && saved.Version == version {
+			return saved, nil
+		}
+	}
+	return nil, configRepo.ErrVersionNotFound
+}
+
+func (r *fakeConfigRepo) CreateSchedule(_ context.Context, input domain.CreateLoyaltyConfigScheduleInput) (*domain.LoyaltyConfigSchedule, error) {
+	r.log.add("config.create_schedule")
+	for _, schedule := range r.schedules {
+		if schedule.CompanyID == input.CompanyID && schedule.Status == domain.ConfigScheduleStatusPending &&
+			schedule.EffectiveFrom.Equal(input.EffectiveFrom) {
+			return nil, configRepo.ErrScheduleAlreadyExists
+		}
+	}
+	schedule := &domain.LoyaltyConfigSchedule{
+		ID:            int64(len(r.schedules) + 1),
+		CompanyID:     input.CompanyID,
+		EffectiveFrom: input.EffectiveFrom,
+		Status:        domain.ConfigScheduleStatusPending,
+		Change:        input.Change,
+		CreatedBy:     input.CreatedBy,
+		CreatedByRole: input.CreatedByRole,
+		CreatedAt:     time.Now(),
+	}
+	r.schedules = append(r.schedules, schedule)
+	created := *schedule
+	return &created, nil
+}
+
+func (r *fakeConfigRepo) ListPendingSchedules(_ context.Context, companyID int64) ([]*domain.LoyaltyConfigSchedule, error) {
+	var schedules []*domain.LoyaltyConfigSchedule
+	for _, schedule := range r.schedules {
+		if schedule.CompanyID == companyID && schedule.Status == domain.ConfigScheduleStatusPending {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (r *fakeConfigRepo) ListDueSchedules(_ context.Context, companyIDs []int64, at time.Time) ([]*domain.LoyaltyConfigSchedule, error) {
+	var schedules []*domain.LoyaltyConfigSchedule
+	for _, schedule := range r.schedules {
+		if schedule.Status != domain.ConfigScheduleStatusPending || schedule.EffectiveFrom.After(at) {
+			continue
+		}
+		for _, companyID := range companyIDs {
+			if schedule.CompanyID == companyID {
+				schedules = append(schedules, schedule)
+			}
+		}
+	}
+	return schedules, nil
+}
+
+func (r *fakeConfigRepo) GetNextDueSchedule(_ context.Context, now time.Time) (*domain.LoyaltyConfigSchedule, error) {
+	for _, schedule := range r.schedules {
+		if schedule.Status == domain.ConfigScheduleStatusPending && !schedule.EffectiveFrom.After(now) {
+			return schedule, nil
+		}
+	}
+	return nil, configRepo.ErrScheduleNotFound
+}
+
+func (r *fakeConfigRepo) ResolveSchedule(_ context.Context, companyID, scheduleID int64, status domain.ConfigScheduleStatus) (*domain.LoyaltyConfigSchedule, error) {
+	r.log.add("config.resolve_schedule." + string(status))
+	for _, schedule := range r.schedules {
+		if schedule.CompanyID == companyID && schedule.ID == scheduleID && schedule.Status == domain.ConfigScheduleStatusPending {
+			resolvedAt := time.Now()
+			schedule.Status = status
+			schedule.ResolvedAt = &resolvedAt
+			resolved := *schedule
+			return &resolved, nil
+		}
+	}
+	return nil, configRepo.ErrScheduleNotFound
+}
+
+func (r *fakeConfigRepo) Disable(_ context.Context, companyID int64) (*domain.LoyaltyConfig, error) {
+	r.log.add("config.disable")
+	if r.config == nil || r.config.CompanyID != companyID {
+		return nil, configRepo.ErrConfigNotFound
+	}
+	r.config.IsEnabled = false
+	config := *r.config
+	return &config, nil
+}
+
+// fakeCardRepo карты лояльности в памяти
+// Методы, не нужные тестам, не реализованы (вызов паникует на встроенном nil интерфейсе)
+type fakeCardRepo struct {
+	LoyaltyCardRepository
+
+	log   *callLog
+	cards []*domain.LoyaltyCard
+}
+
+func (r *fakeCardRepo) GetByID(_ context.Context, cardID int64) (*domain.LoyaltyCard, error) {
+	for _, card := range r.cards {
+		if card.ID == cardID {
+			found := *card
+			return &found, nil
+		}
+	}
+	return nil, cardRepo.ErrCardNotFound
+}
+
+func (r *fakeCardRepo) UpdateDiscountForCompany(_ context.Context, input domain.BulkUpdateCardDiscountInput) (int64, error) {
+	r.log.add("card.update_discount")
+	var updated int64
+	for _, card := range r.cards {
+		if card.CompanyID != input.CompanyID {
+			continue
+		}
+		if input.OnlyIncrease && card.DiscountPercentage >= input.DiscountPercentage {
+			continue
+		}
+		card.CardType = input.CardType
+		card.DiscountPercentage = input.DiscountPercentage
+		updated++
+	}
+	return updated, nil
+}
+
+func (r *fakeCardRepo) LockOverdue(_ context.Context, limit int) ([]*domain.LoyaltyCard, error) {
+	r.log.add("card.lock_overdue")
+	now := time.Now()
+	var overdue []*domain.LoyaltyCard
+	for _, card := range r.cards {
+		if len(overdue) == limit {
+			break
+		}
+		if card.ExpiresAt == nil || card.ExpiresAt.After(now) {
+			continue
+		}
+		if card.Status != domain.CardStatusActive && card.Status != domain.CardStatusSuspended {
+			continue
+		}
+		locked := *card
+		overdue = append(overdue, &locked)
+	}
+	return overdue, nil
+}
+
+func (r *fakeCardRepo) Expire(_ context.Context, cardIDs []int64, reason string) ([]*domain.LoyaltyCard, error) {
+	r.log.add("card.expire")
+	now := time.Now()
+	var expired []*domain.LoyaltyCard
+	for _, card := range r.cards {
+		for _, cardID := range cardIDs {
+			if card.ID != cardID {
+				continue
+			}
+			card.Status = domain.CardStatusExpired
+			card.StatusReason = &reason
+			card.StatusChangedAt = &now
+			updated := *card
+			expired = append(expired, &updated)
+		}
+	}
+	return expired, nil
+}
+
+// fakeTransactionRepo журнал операций с баллами одной карты
+// Ошибка err возвращается вместо записи операции
+type fakeTransactionRepo struct {
+	log     *callLog
+	balance int64
+	err     error
+}
+
+func (r *fakeTransactionRepo) Accrue(_ context.Context, input domain.CreateLoyaltyTransactionInput) (*domain.LoyaltyTransaction, error) {
+	r.log.add("transaction.accrue")
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.balance += input.Points
+	return r.transaction(domain.TransactionTypeAccrual, input), nil
+}
+
+func (r *fakeTransactionRepo) Redeem(_ context.Context, input domain.CreateLoyaltyTransactionInput) (*domain.LoyaltyTransaction, error) {
+	r.log.add("transaction.redeem")
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.balance -= input.Points
+	return r.transaction(domain.TransactionTypeRedemption, input), nil
+}
+
+func (r *fakeTransactionRepo) transaction(txType domain.TransactionType, input domain.CreateLoyaltyTransactionInput) *domain.LoyaltyTransaction {
+	return &domain.LoyaltyTransaction{
+		ID:               1,
+		CardID:           input.CardID,
+		Type:             txType,
+		Points:           input.Points,
+		Amount:           input.Amount,
+		BalanceAfter:     r.balance,
+		CreatedBy:        input.CreatedBy,
+		CreatedByRole:    input.CreatedByRole,
+		CreatedByService: input.CreatedByService,
+		CreatedAt:        time.Now(),
+	}
+}
+
+// fakeAuditRepo записанные события журнала изменений
+// Ошибка err возвращается вместо записи события
+type fakeAuditRepo struct {
+	AuditEventRepository
+
+	log    *callLog
+	events []domain.CreateAuditEventInput
+	err    error
+}
+
+func (r *fakeAuditRepo) Create(_ context.Context, input domain.CreateAuditEventInput) (*domain.AuditEvent, error) {
+	r.log.add("audit." + string(input.Action))
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.events = append(r.events, input)
+	return &domain.AuditEvent{ID: int64(len(r.events))}, nil
+}
+
+// fakeSellerClient компании SellerService в памяти
+type fakeSellerClient struct {
+	companies map[int64]*sellerservice.Company
+}
+
+func (c *fakeSellerClient) GetCompany(_ context.Context, companyID int64) (*sellerservice.Company, error) {
+	company, ok := c.companies[companyID]
+	if !ok {
+		return nil, sellerservice.ErrCompanyNotFound
+	}
+	return company, nil
+}
+
+// nopLogger логгер без вывода
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// fakeCampaignRepo акции компаний в памяти
+type fakeCampaignRepo struct {
+	campaigns []*domain.LoyaltyCampaign
+}
+
+func (r *fakeCampaignRepo) Create(_ context.Context, campaign *domain.LoyaltyCampaign) (*domain.LoyaltyCampaign, error) {
+	created := *campaign
+	created.ID = int64(len(r.campaigns) + 1)
+	r.campaigns = append(r.campaigns, &created)
+	return &created, nil
+}
+
+func (r *fakeCampaignRepo) ListByCompany(_ context.Context, input domain.ListLoyaltyCampaignsInput) ([]*domain.LoyaltyCampaign, error) {
+	var campaigns []*domain.LoyaltyCampaign
+	for _, campaign := range r.campaigns {
+		if campaign.CompanyID != input.CompanyID {
+			continue
+		}
+		if input.ActiveAt != nil && (input.ActiveAt.Before(campaign.StartsAt) || !input.ActiveAt.Before(campaign.EndsAt)) {
+			continue
+		}
+		if input.EndsAfter != nil && !campaign.EndsAt.After(*input.EndsAfter) {
+			continue
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	return campaigns, nil
+}
+
+func (r *fakeCampaignRepo) Delete(_ context.Context, companyID, campaignID int64) (*domain.LoyaltyCampaign, error) {
+	for i, campaign := range r.campaigns {
+		if campaign.CompanyID == companyID && campaign.ID == campaignID {
+			r.campaigns = append(r.campaigns[:i], r.campaigns[i+1:]...)
+			return campaign, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeHappyHoursRepo «счастливые часы» компаний в памяти
+type fakeHappyHoursRepo struct {
+	happyHours map[int64]*domain.HappyHours
+}
+
+func (r *fakeHappyHoursRepo) GetByCompanyID(_ context.Context, companyID int64) (*domain.HappyHours, error) {
+	happyHours, ok := r.happyHours[companyID]
+	if !ok {
+		return nil, happyHoursRepo.ErrHappyHoursNotFound
+	}
+	return happyHours, nil
+}
+
+func (r *fakeHappyHoursRepo) Save(_ context.Context, happyHours *domain.HappyHours) (*domain.HappyHours, error) {
+	if r.happyHours == nil {
+		r.happyHours = make(map[int64]*domain.HappyHours)
+	}
+	saved := *happyHours
+	r.happyHours[happyHours.CompanyID] = &saved
+	return &saved, nil
+}
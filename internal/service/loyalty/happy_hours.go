@@ -0,0 +1,145 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	happyHoursRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_happy_hours"
+	sellerClient "github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+)
+
+// GetHappyHours возвращает «счастливые часы» компании
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) GetHappyHours(ctx context.Context, companyID int64, actor models.Actor) (*models.HappyHoursResponse, error) {
+	if _, err := s.checkManagerAccess(ctx, companyID, actor, "GetHappyHours"); err != nil {
+		return nil, err
+	}
+
+	happyHours, err := s.happyHoursRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, happyHoursRepo.ErrHappyHoursNotFound) {
+			return nil, ErrHappyHoursNotFound
+		}
+		return nil, fmt.Errorf("%w: GetHappyHours - failed to get happy hours: %v", ErrInternal, err)
+	}
+
+	return models.FromDomainHappyHours(happyHours), nil
+}
+
+// SaveHappyHours заменяет «счастливые часы» компании целиком
+// Каждый интервал должен приходиться на часы работы компании в SellerService в свой день недели.
+// Часовой пояс берётся из часов работы компании: пояс из запроса, если задан, должен с ним совпадать.
+// Доступно менеджеру компании и суперпользователю
+func (s *Service) SaveHappyHours(ctx context.Context, companyID int64, actor models.Actor, req *models.SaveHappyHoursRequest) (*models.HappyHoursResponse, error) {
+	role, err := s.checkManagerAccess(ctx, companyID, actor, "SaveHappyHours")
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Получаем часы работы компании: интервалы задаются в их часовом поясе
+	company, err := s.sellerClient.GetCompany(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, sellerClient.ErrCompanyNotFound) {
+			return nil, ErrCompanyNotFound
+		}
+		return nil, sellerServiceError(err)
+	}
+
+	timezone := company.WorkingHours.Timezone
+	if req.Timezone != "" && req.Timezone != timezone {
+		return nil, fmt.Errorf("%w: timezone %q does not match company working hours timezone %q", ErrInvalidInput, req.Timezone, timezone)
+	}
+
+	// 2. Проверяем интервалы и сверяем их с часами работы компании
+	happyHours := req.ToDomain(companyID)
+	happyHours.Timezone = timezone
+	happyHours.UpdatedBy = actor.UserID
+	happyHours.UpdatedByRole = role
+
+	if err := happyHours.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	happyHours.Windows = happyHours.SortedWindows()
+
+	if err := happyHours.FitWorkingHours(workingDays(company.WorkingHours)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	// 3. Сохраняем «счастливые часы» и запись журнала в одной транзакции
+	var saved *domain.HappyHours
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		var before *models.HappyHoursResponse
+		existing, err := s.happyHoursRepo.GetByCompanyID(ctx, companyID)
+		switch {
+		case err == nil:
+			before = models.FromDomainHappyHours(existing)
+		case !errors.Is(err, happyHoursRepo.ErrHappyHoursNotFound):
+			return fmt.Errorf("%w: SaveHappyHours - failed to get happy hours: %v", ErrInternal, err)
+		}
+
+		saved, err = s.happyHoursRepo.Save(ctx, happyHours)
+		if err != nil {
+			return fmt.Errorf("%w: SaveHappyHours - failed to save happy hours: %v", ErrInternal, err)
+		}
+
+		change := auditChange{
+			companyID:  companyID,
+			entityType: domain.AuditEntityHappyHours,
+			entityID:   saved.ID,
+			action:     domain.AuditActionHappyHoursUpdated,
+			after:      models.FromDomainHappyHours(saved),
+		}
+		if before != nil {
+			change.before = before
+		}
+
+		return s.recordAudit(ctx, actor, role, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.FromDomainHappyHours(saved), nil
+}
+
+// activeHappyHour возвращает «счастливые часы» компании и интервал, действующий в момент at
+// по местному времени компании (интервал nil - «счастливые часы» не заданы или сейчас не действуют)
+func (s *Service) activeHappyHour(ctx context.Context, companyID int64, at time.Time, action string) (*domain.HappyHours, *domain.HappyHourWindow, error) {
+	happyHours, err := s.happyHoursRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, happyHoursRepo.ErrHappyHoursNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("%w: %s - failed to get happy hours: %v", ErrInternal, action, err)
+	}
+
+	return happyHours, happyHours.WindowAt(at), nil
+}
+
+// workingDays переводит часы работы компании из SellerService в часы работы по дням недели ISO 8601
+func workingDays(hours sellerClient.WorkingHours) map[int]domain.WorkingDay {
+	days := make(map[int]domain.WorkingDay, 7)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		schedule := hours.ForWeekday(weekday)
+
+		day := domain.WorkingDay{IsOpen: schedule.IsOpen}
+		if schedule.OpenTime != nil && schedule.CloseTime != nil {
+			day.OpenTime = types.TimeString(*schedule.OpenTime)
+			day.CloseTime = types.TimeString(*schedule.CloseTime)
+		}
+
+		// time.Weekday: воскресенье - 0, в ISO 8601 - 7
+		isoDay := int(weekday)
+		if isoDay == 0 {
+			isoDay = 7
+		}
+		days[isoDay] = day
+	}
+
+	return days
+}
@@ -0,0 +1,81 @@
+package loyalty
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+func TestService_SaveHappyHours(t *testing.T) {
+	manager := models.Actor{UserID: testManagerID}
+	openTime, closeTime := "10:00", "20:00"
+
+	newService := func(happyHours *fakeHappyHoursRepo, audit *fakeAuditRepo) *Service {
+		return &Service{
+			happyHoursRepo: happyHours,
+			auditRepo:      audit,
+			sellerClient: &fakeSellerClient{companies: map[int64]*sellerservice.Company{
+				testCompanyID: {
+					ID:         testCompanyID,
+					ManagerIDs: []int64{testManagerID},
+					WorkingHours: sellerservice.WorkingHours{
+						Monday:   sellerservice.DaySchedule{IsOpen: true, OpenTime: &openTime, CloseTime: &closeTime},
+						Timezone: "Europe/Moscow",
+					},
+				},
+			}},
+			txManager: &fakeTxManager{},
+			logger:    nopLogger{},
+		}
+	}
+	windows := []models.HappyHourWindowDTO{{Weekday: 1, Start: "12:00", End: "14:00", DiscountPercentage: 5}}
+
+	tests := []struct {
+		name     string
+		timezone string
+		wantErr  error
+	}{
+		{name: "timezone taken from working hours"},
+		{name: "matching timezone", timezone: "Europe/Moscow"},
+		{name: "different timezone", timezone: "Asia/Vladivostok", wantErr: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			happyHours, audit := &fakeHappyHoursRepo{}, &fakeAuditRepo{}
+			service := newService(happyHours, audit)
+
+			resp, err := service.SaveHappyHours(context.Background(), testCompanyID, manager, &models.SaveHappyHoursRequest{
+				Timezone: tt.timezone,
+				Windows:  windows,
+			})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, happyHours.happyHours)
+				assert.Empty(t, audit.events)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "Europe/Moscow", resp.Timezone)
+			assert.Equal(t, "Europe/Moscow", happyHours.happyHours[testCompanyID].Timezone)
+			assert.Len(t, audit.events, 1)
+		})
+	}
+
+	t.Run("window outside working hours", func(t *testing.T) {
+		happyHours := &fakeHappyHoursRepo{}
+		service := newService(happyHours, &fakeAuditRepo{})
+
+		_, err := service.SaveHappyHours(context.Background(), testCompanyID, manager, &models.SaveHappyHoursRequest{
+			Windows: []models.HappyHourWindowDTO{{Weekday: 1, Start: "19:00", End: "21:00", DiscountPercentage: 5}},
+		})
+		require.ErrorIs(t, err, ErrInvalidInput)
+		assert.Empty(t, happyHours.happyHours)
+	})
+}
@@ -0,0 +1,169 @@
+package loyalty
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// parseCardType проверяет, что тип карты поддерживается сервисом
+func parseCardType(value string) (domain.CardType, error) {
+	switch cardType := domain.CardType(value); cardType {
+	case domain.CardTypeFixedDiscount, domain.CardTypeProgressiveDiscount, domain.CardTypePointsBased:
+		return cardType, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported card type %q", ErrInvalidInput, value)
+	}
+}
+
+// parseCardStatus проверяет, что статус карты поддерживается сервисом
+func parseCardStatus(value string) (domain.CardStatus, error) {
+	switch status := domain.CardStatus(value); status {
+	case domain.CardStatusActive, domain.CardStatusSuspended, domain.CardStatusDisabled, domain.CardStatusExpired:
+		return status, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported card status %q", ErrInvalidInput, value)
+	}
+}
+
+// mergeConfigRequest собирает итоговую конфигурацию из запроса и текущей конфигурации
+// Поля, отсутствующие в запросе, сохраняют текущие значения (или дефолты для новой программы)
+func mergeConfigRequest(existing *domain.LoyaltyConfig, req *models.ConfigureLoyaltyRequest) (*domain.LoyaltyConfig, error) {
+	candidate := &domain.LoyaltyConfig{
+		CardType:             domain.CardTypeFixedDiscount,
+		DiscountUpdatePolicy: domain.DiscountUpdatePolicyNewCardsOnly,
+	}
+
+	if existing != nil {
+		candidate.CardType = existing.CardType
+		candidate.DiscountPercentage = existing.DiscountPercentage
+		candidate.ProgressiveConfig = existing.ProgressiveConfig
+		candidate.PointsConfig = existing.PointsConfig
+		candidate.CardValidityDays = existing.CardValidityDays
+		candidate.DiscountUpdatePolicy = existing.DiscountUpdatePolicy
+	}
+
+	if req.CardType != nil {
+		cardType, err := parseCardType(*req.CardType)
+		if err != nil {
+			return nil, err
+		}
+		candidate.CardType = cardType
+	}
+
+	if req.DiscountPercentage != nil {
+		candidate.DiscountPercentage = req.DiscountPercentage
+	}
+
+	if req.ProgressiveConfig != nil {
+		candidate.ProgressiveConfig = req.ProgressiveConfig.ToDomain()
+	}
+
+	if req.PointsConfig != nil {
+		candidate.PointsConfig = req.PointsConfig.ToDomain()
+	}
+
+	if req.CardValidityDays != nil {
+		candidate.CardValidityDays = *req.CardValidityDays
+	}
+
+	if req.DiscountUpdatePolicy != nil {
+		candidate.DiscountUpdatePolicy = domain.DiscountUpdatePolicy(*req.DiscountUpdatePolicy)
+	}
+
+	return candidate, nil
+}
+
+// configChange строит изменение программы из параметров, заданных в запросе
+func configChange(req *models.ConfigureLoyaltyRequest) (domain.LoyaltyConfigChange, error) {
+	change := domain.LoyaltyConfigChange{
+		IsEnabled:          req.IsEnabled,
+		DiscountPercentage: req.DiscountPercentage,
+		CardValidityDays:   req.CardValidityDays,
+	}
+
+	if req.CardType != nil {
+		cardType, err := parseCardType(*req.CardType)
+		if err != nil {
+			return change, err
+		}
+		change.CardType = &cardType
+	}
+
+	if req.ProgressiveConfig != nil {
+		change.ProgressiveConfig = req.ProgressiveConfig.ToDomain()
+	}
+
+	if req.PointsConfig != nil {
+		change.PointsConfig = req.PointsConfig.ToDomain()
+	}
+
+	if req.DiscountUpdatePolicy != nil {
+		policy := domain.DiscountUpdatePolicy(*req.DiscountUpdatePolicy)
+		change.DiscountUpdatePolicy = &policy
+	}
+
+	return change, nil
+}
+
+// buildCardResponse формирует ответ с картой с учётом текущей программы компании
+// Для прогрессивной скидки уровень и скидка вычисляются по количеству визитов,
+// карты накопительной системы и неактивные (в том числе просроченные) карты процентной скидки не дают
+func buildCardResponse(card *domain.LoyaltyCard, config *domain.LoyaltyConfig) *models.LoyaltyCardResponse {
+	resp := models.FromDomainLoyaltyCard(card)
+
+	status := card.EffectiveStatus(time.Now())
+	resp.Status = string(status)
+
+	switch config.CardType {
+	case domain.CardTypeProgressiveDiscount:
+		if config.ProgressiveConfig != nil && len(config.ProgressiveConfig.Tiers) > 0 {
+			tierStatus := config.ProgressiveConfig.StatusFor(card.VisitsCount)
+
+			resp.CardType = string(config.CardType)
+			resp.DiscountPercentage = tierStatus.CurrentTier.DiscountPercentage
+			resp.Progressive = models.FromDomainProgressiveStatus(tierStatus, len(config.ProgressiveConfig.Tiers), card.VisitsCount)
+		}
+	case domain.CardTypePointsBased:
+		resp.CardType = string(config.CardType)
+		resp.DiscountPercentage = 0
+	}
+
+	if !status.GrantsDiscount() {
+		resp.DiscountPercentage = 0
+	}
+
+	return resp
+}
+
+// actorAuthor возвращает автора действия для сохраняемых записей:
+// ID пользователя или имя внутреннего сервиса, обратившегося по API-ключу
+func actorAuthor(actor models.Actor) (userID *int64, service *string) {
+	if actor.IsService() {
+		return nil, &actor.Service
+	}
+	return &actor.UserID, nil
+}
+
+// encodeIDCursor кодирует ID (номер) последней записи страницы в непрозрачный курсор
+func encodeIDCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeIDCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+
+	id, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import "github.com/m04kA/SMC-LoyaltySystemService/internal/service"
+
+// Actor пользователь или внутренний сервис, от имени которого выполняется запрос
+type Actor struct {
+	UserID int64
+	// Role роль из заголовка X-User-Role (пустая строка - роль не передана)
+	Role string
+	// Service имя внутреннего сервиса, вызвавшего API по API-ключу (пустая строка - запрос пользователя)
+	// У сервиса нет пользователя: UserID равен 0, права ограничены scopes ключа на уровне маршрутов
+	Service string
+}
+
+// IsSuperuser сообщает, есть ли у пользователя роль суперпользователя
+func (a Actor) IsSuperuser() bool {
+	return a.Service == "" && a.Role == service.RoleSuperuser
+}
+
+// IsService сообщает, что запрос выполняет внутренний сервис по API-ключу
+func (a Actor) IsService() bool {
+	return a.Service != ""
+}
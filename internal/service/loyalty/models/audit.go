@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+const (
+	// DefaultAuditEventsLimit размер страницы журнала изменений по умолчанию
+	DefaultAuditEventsLimit = 50
+	// MaxAuditEventsLimit максимальный размер страницы журнала изменений
+	MaxAuditEventsLimit = 200
+)
+
+// ListAuditEventsRequest параметры журнала изменений компании
+type ListAuditEventsRequest struct {
+	// EntityType фильтр по типу сущности: loyalty_config или loyalty_card
+	EntityType *string
+	// EntityID фильтр по ID сущности
+	EntityID *int64
+
+	// Cursor значение next_cursor предыдущей страницы
+	Cursor *string
+	// Limit размер страницы (0 - DefaultAuditEventsLimit)
+	Limit int
+}
+
+// AuditEventResponse событие журнала изменений
+type AuditEventResponse struct {
+	ID           int64           `json:"id"`
+	CompanyID    int64           `json:"company_id"`
+	EntityType   string          `json:"entity_type"`
+	EntityID     int64           `json:"entity_id"`
+	Action       string          `json:"action"`
+	ActorID      *int64          `json:"actor_id,omitempty"`
+	ActorRole    string          `json:"actor_role"`
+	ActorService *string         `json:"actor_service,omitempty"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	Changes      json.RawMessage `json:"changes"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// AuditEventsResponse страница журнала изменений компании
+type AuditEventsResponse struct {
+	Events []*AuditEventResponse `json:"events"`
+	// NextCursor курсор следующей страницы (nil - страница последняя)
+	NextCursor *string `json:"next_cursor"`
+}
+
+// FromDomainAuditEvent конвертирует domain модель события журнала в DTO
+func FromDomainAuditEvent(event *domain.AuditEvent) *AuditEventResponse {
+	before := event.Before
+	if len(before) == 0 {
+		before = json.RawMessage("null")
+	}
+
+	return &AuditEventResponse{
+		ID:           event.ID,
+		CompanyID:    event.CompanyID,
+		EntityType:   string(event.EntityType),
+		EntityID:     event.EntityID,
+		Action:       string(event.Action),
+		ActorID:      event.ActorID,
+		ActorRole:    string(event.ActorRole),
+		ActorService: event.ActorService,
+		Before:       before,
+		After:        event.After,
+		Changes:      event.Changes,
+		CreatedAt:    event.CreatedAt,
+	}
+}
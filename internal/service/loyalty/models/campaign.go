@@ -0,0 +1,137 @@
+package models
+
+import (
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+)
+
+// CreateCampaignRequest запрос на создание промо-акции
+type CreateCampaignRequest struct {
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	// Weekdays дни недели ISO 8601 (1 - понедельник, 7 - воскресенье), пусто - каждый день
+	Weekdays []int `json:"weekdays,omitempty"`
+	// WindowStart и WindowEnd интервал времени суток HH:MM, не заданы - весь день
+	WindowStart types.TimeString `json:"window_start,omitempty"`
+	WindowEnd   types.TimeString `json:"window_end,omitempty"`
+	// Timezone часовой пояс IANA (например, Europe/Moscow), в котором заданы дни недели и интервал
+	Timezone           string   `json:"timezone"`
+	DiscountPercentage *float64 `json:"discount_percentage,omitempty"`
+	// PointsMultiplier множитель баллов накопительной системы (по умолчанию 1)
+	PointsMultiplier *float64 `json:"points_multiplier,omitempty"`
+	// StackingRule max / additive / capped
+	StackingRule          string   `json:"stacking_rule"`
+	MaxDiscountPercentage *float64 `json:"max_discount_percentage,omitempty"`
+}
+
+// ListCampaignsRequest параметры списка акций компании
+type ListCampaignsRequest struct {
+	// IncludeFinished вернуть и завершившиеся акции
+	IncludeFinished bool
+}
+
+// CampaignResponse промо-акция компании
+type CampaignResponse struct {
+	CampaignID            int64            `json:"campaign_id"`
+	CompanyID             int64            `json:"company_id"`
+	Name                  string           `json:"name"`
+	StartsAt              time.Time        `json:"starts_at"`
+	EndsAt                time.Time        `json:"ends_at"`
+	Weekdays              []int            `json:"weekdays"`
+	WindowStart           types.TimeString `json:"window_start,omitempty"`
+	WindowEnd             types.TimeString `json:"window_end,omitempty"`
+	Timezone              string           `json:"timezone"`
+	DiscountPercentage    float64          `json:"discount_percentage"`
+	PointsMultiplier      float64          `json:"points_multiplier"`
+	StackingRule          string           `json:"stacking_rule"`
+	MaxDiscountPercentage *float64         `json:"max_discount_percentage,omitempty"`
+	CreatedBy             int64            `json:"created_by"`
+	CreatedByRole         string           `json:"created_by_role"`
+	CreatedAt             time.Time        `json:"created_at"`
+}
+
+// CampaignsResponse акции компании
+type CampaignsResponse struct {
+	CompanyID int64               `json:"company_id"`
+	Campaigns []*CampaignResponse `json:"campaigns"`
+}
+
+// CardCampaignResponse акция, действующая для карты в момент запроса
+type CardCampaignResponse struct {
+	CampaignID            int64     `json:"campaign_id"`
+	Name                  string    `json:"name"`
+	StackingRule          string    `json:"stacking_rule"`
+	DiscountPercentage    float64   `json:"discount_percentage"`
+	MaxDiscountPercentage *float64  `json:"max_discount_percentage,omitempty"`
+	PointsMultiplier      float64   `json:"points_multiplier"`
+	EndsAt                time.Time `json:"ends_at"`
+}
+
+// ToDomain конвертирует запрос в domain модель акции компании companyID
+func (r *CreateCampaignRequest) ToDomain(companyID int64) *domain.LoyaltyCampaign {
+	campaign := &domain.LoyaltyCampaign{
+		CompanyID:             companyID,
+		Name:                  r.Name,
+		StartsAt:              r.StartsAt,
+		EndsAt:                r.EndsAt,
+		Weekdays:              r.Weekdays,
+		WindowStart:           r.WindowStart,
+		WindowEnd:             r.WindowEnd,
+		Timezone:              r.Timezone,
+		PointsMultiplier:      1,
+		StackingRule:          domain.CampaignStackingRule(r.StackingRule),
+		MaxDiscountPercentage: r.MaxDiscountPercentage,
+	}
+
+	if r.DiscountPercentage != nil {
+		campaign.DiscountPercentage = *r.DiscountPercentage
+	}
+	if r.PointsMultiplier != nil {
+		campaign.PointsMultiplier = *r.PointsMultiplier
+	}
+
+	return campaign
+}
+
+// FromDomainLoyaltyCampaign конвертирует domain модель акции в DTO
+func FromDomainLoyaltyCampaign(campaign *domain.LoyaltyCampaign) *CampaignResponse {
+	weekdays := campaign.Weekdays
+	if weekdays == nil {
+		weekdays = []int{}
+	}
+
+	return &CampaignResponse{
+		CampaignID:            campaign.ID,
+		CompanyID:             campaign.CompanyID,
+		Name:                  campaign.Name,
+		StartsAt:              campaign.StartsAt,
+		EndsAt:                campaign.EndsAt,
+		Weekdays:              weekdays,
+		WindowStart:           campaign.WindowStart,
+		WindowEnd:             campaign.WindowEnd,
+		Timezone:              campaign.Timezone,
+		DiscountPercentage:    campaign.DiscountPercentage,
+		PointsMultiplier:      campaign.PointsMultiplier,
+		StackingRule:          string(campaign.StackingRule),
+		MaxDiscountPercentage: campaign.MaxDiscountPercentage,
+		CreatedBy:             campaign.CreatedBy,
+		CreatedByRole:         string(campaign.CreatedByRole),
+		CreatedAt:             campaign.CreatedAt,
+	}
+}
+
+// FromDomainCardCampaign конвертирует действующую акцию в DTO карты
+func FromDomainCardCampaign(campaign *domain.LoyaltyCampaign) *CardCampaignResponse {
+	return &CardCampaignResponse{
+		CampaignID:            campaign.ID,
+		Name:                  campaign.Name,
+		StackingRule:          string(campaign.StackingRule),
+		DiscountPercentage:    campaign.DiscountPercentage,
+		MaxDiscountPercentage: campaign.MaxDiscountPercentage,
+		PointsMultiplier:      campaign.PointsMultiplier,
+		EndsAt:                campaign.EndsAt,
+	}
+}
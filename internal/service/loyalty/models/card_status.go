@@ -0,0 +1,18 @@
+package models
+
+// CardStatusAction действие менеджера со статусом карты
+type CardStatusAction string
+
+const (
+	// CardStatusActionSuspend временно приостановить карту
+	CardStatusActionSuspend CardStatusAction = "suspend"
+	// CardStatusActionDisable выключить карту
+	CardStatusActionDisable CardStatusAction = "disable"
+	// CardStatusActionReactivate вернуть карту в активный статус
+	CardStatusActionReactivate CardStatusAction = "reactivate"
+)
+
+// ChangeCardStatusRequest запрос на смену статуса карты
+type ChangeCardStatusRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	// DefaultCompanyCardsLimit размер страницы списка карт компании по умолчанию
+	DefaultCompanyCardsLimit = 50
+	// MaxCompanyCardsLimit максимальный размер страницы списка карт компании
+	MaxCompanyCardsLimit = 500
+)
+
+// ListCompanyCardsRequest параметры списка карт компании
+type ListCompanyCardsRequest struct {
+	// Фильтры
+	Status      *string
+	CardType    *string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+
+	// Sort поле сортировки: created_at (по умолчанию), visits_count, points_balance
+	Sort string
+	// Order направление сортировки: desc (по умолчанию) или asc
+	Order string
+
+	// Cursor значение next_cursor предыдущей страницы (игнорируется при выгрузке CSV)
+	Cursor *string
+	// Limit размер страницы (0 - DefaultCompanyCardsLimit, игнорируется при выгрузке CSV)
+	Limit int
+}
+
+// CompanyLoyaltyCardsResponse страница списка карт компании
+type CompanyLoyaltyCardsResponse struct {
+	Cards []*LoyaltyCardResponse `json:"cards"`
+	// NextCursor курсор следующей страницы (nil - страница последняя)
+	NextCursor *string `json:"next_cursor"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+// LoyaltyConfigScheduleResponse запланированное изменение программы лояльности
+type LoyaltyConfigScheduleResponse struct {
+	ScheduleID    int64     `json:"schedule_id"`
+	CompanyID     int64     `json:"company_id"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	// Status pending / applied / canceled
+	Status string `json:"status"`
+	// ChangedFields параметры, заданные в изменении; остальные берутся из конфигурации,
+	// действующей к моменту EffectiveFrom
+	ChangedFields []string `json:"changed_fields"`
+	// Конфигурация с учётом изменения и более ранних ожидающих изменений
+	CardType             string                `json:"card_type"`
+	IsEnabled            bool                  `json:"is_enabled"`
+	DiscountPercentage   float64               `json:"discount_percentage"`
+	ProgressiveConfig    *ProgressiveConfigDTO `json:"progressive_config,omitempty"`
+	PointsConfig         *PointsConfigDTO      `json:"points_config,omitempty"`
+	CardValidityDays     int                   `json:"card_validity_days"`
+	DiscountUpdatePolicy string                `json:"discount_update_policy"`
+	CreatedBy            int64                 `json:"created_by"`
+	CreatedByRole        string                `json:"created_by_role"`
+	CreatedAt            time.Time             `json:"created_at"`
+	ResolvedAt           *time.Time            `json:"resolved_at,omitempty"`
+}
+
+// LoyaltyConfigSchedulesResponse ожидающие изменения программы лояльности компании
+type LoyaltyConfigSchedulesResponse struct {
+	CompanyID int64                            `json:"company_id"`
+	Schedules []*LoyaltyConfigScheduleResponse `json:"schedules"`
+}
+
+// FromDomainLoyaltyConfigSchedule конвертирует domain модель запланированного изменения в DTO
+// config - конфигурация, которая будет действовать после наступления изменения
+func FromDomainLoyaltyConfigSchedule(schedule *domain.LoyaltyConfigSchedule, config *domain.LoyaltyConfig) *LoyaltyConfigScheduleResponse {
+	resp := FromDomainLoyaltyConfig(config)
+
+	return &LoyaltyConfigScheduleResponse{
+		ScheduleID:           schedule.ID,
+		CompanyID:            schedule.CompanyID,
+		EffectiveFrom:        schedule.EffectiveFrom,
+		Status:               string(schedule.Status),
+		ChangedFields:        schedule.Change.Fields(),
+		CardType:             resp.CardType,
+		IsEnabled:            resp.IsEnabled,
+		DiscountPercentage:   resp.DiscountPercentage,
+		ProgressiveConfig:    resp.ProgressiveConfig,
+		PointsConfig:         resp.PointsConfig,
+		CardValidityDays:     resp.CardValidityDays,
+		DiscountUpdatePolicy: resp.DiscountUpdatePolicy,
+		CreatedBy:            schedule.CreatedBy,
+		CreatedByRole:        string(schedule.CreatedByRole),
+		CreatedAt:            schedule.CreatedAt,
+		ResolvedAt:           schedule.ResolvedAt,
+	}
+}
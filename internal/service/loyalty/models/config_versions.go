@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+const (
+	// DefaultConfigVersionsLimit размер страницы истории версий по умолчанию
+	DefaultConfigVersionsLimit = 50
+	// MaxConfigVersionsLimit максимальный размер страницы истории версий
+	MaxConfigVersionsLimit = 200
+)
+
+// ListConfigVersionsRequest параметры истории версий конфигурации
+type ListConfigVersionsRequest struct {
+	// Cursor значение next_cursor предыдущей страницы
+	Cursor *string
+	// Limit размер страницы (0 - DefaultConfigVersionsLimit)
+	Limit int
+}
+
+// LoyaltyConfigVersionResponse версия конфигурации программы лояльности
+type LoyaltyConfigVersionResponse struct {
+	Version              int                   `json:"version"`
+	CardType             string                `json:"card_type"`
+	IsEnabled            bool                  `json:"is_enabled"`
+	DiscountPercentage   float64               `json:"discount_percentage"`
+	ProgressiveConfig    *ProgressiveConfigDTO `json:"progressive_config,omitempty"`
+	PointsConfig         *PointsConfigDTO      `json:"points_config,omitempty"`
+	CardValidityDays     int                   `json:"card_validity_days"`
+	DiscountUpdatePolicy string                `json:"discount_update_policy"`
+	// CreatedBy автор версии (отсутствует, если изменение выполнил сервис)
+	CreatedBy     *int64    `json:"created_by,omitempty"`
+	CreatedByRole string    `json:"created_by_role,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LoyaltyConfigVersionsResponse страница истории версий конфигурации
+type LoyaltyConfigVersionsResponse struct {
+	CompanyID int64                           `json:"company_id"`
+	Versions  []*LoyaltyConfigVersionResponse `json:"versions"`
+	// NextCursor курсор следующей страницы (nil - страница последняя)
+	NextCursor *string `json:"next_cursor"`
+}
+
+// FromDomainLoyaltyConfigVersion конвертирует domain модель версии конфигурации в DTO
+func FromDomainLoyaltyConfigVersion(version *domain.LoyaltyConfigVersion) *LoyaltyConfigVersionResponse {
+	config := FromDomainLoyaltyConfig(&version.Config)
+
+	return &LoyaltyConfigVersionResponse{
+		Version:              version.Version,
+		CardType:             config.CardType,
+		IsEnabled:            config.IsEnabled,
+		DiscountPercentage:   config.DiscountPercentage,
+		ProgressiveConfig:    config.ProgressiveConfig,
+		PointsConfig:         config.PointsConfig,
+		CardValidityDays:     config.CardValidityDays,
+		DiscountUpdatePolicy: config.DiscountUpdatePolicy,
+		CreatedBy:            version.CreatedBy,
+		CreatedByRole:        string(version.CreatedByRole),
+		CreatedAt:            version.Config.UpdatedAt,
+	}
+}
+
+// NewRestoreConfigRequest формирует запрос настройки программы, возвращающий её к версии
+// Все параметры задаются явно, поэтому восстановление проходит ту же проверку, что и настройка
+func NewRestoreConfigRequest(version *domain.LoyaltyConfigVersion) *ConfigureLoyaltyRequest {
+	config := version.Config
+	cardType := string(config.CardType)
+	policy := string(config.DiscountUpdatePolicy)
+
+	return &ConfigureLoyaltyRequest{
+		CardType:             &cardType,
+		DiscountPercentage:   config.DiscountPercentage,
+		ProgressiveConfig:    FromDomainProgressiveConfig(config.ProgressiveConfig),
+		PointsConfig:         FromDomainPointsConfig(config.PointsConfig),
+		CardValidityDays:     &config.CardValidityDays,
+		DiscountUpdatePolicy: &policy,
+		IsEnabled:            &config.IsEnabled,
+	}
+}
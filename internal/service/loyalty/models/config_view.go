@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+const (
+	// LoyaltyConfigViewPublic публичный вид конфигурации, доступный клиентам
+	LoyaltyConfigViewPublic = "public"
+	// LoyaltyConfigViewManager расширенный вид конфигурации для менеджеров компании
+	LoyaltyConfigViewManager = "manager"
+)
+
+// LoyaltyConfigViewResponse конфигурация программы лояльности для чтения
+// Поля менеджерского вида не возвращаются в публичном виде
+type LoyaltyConfigViewResponse struct {
+	View               string                `json:"view"`
+	CompanyID          int64                 `json:"company_id"`
+	IsEnabled          bool                  `json:"is_enabled"`
+	CardType           string                `json:"card_type"`
+	DiscountPercentage float64               `json:"discount_percentage"`
+	ProgressiveConfig  *ProgressiveConfigDTO `json:"progressive_config,omitempty"`
+	PointsConfig       *PointsConfigDTO      `json:"points_config,omitempty"`
+	CardValidityDays   int                   `json:"card_validity_days"`
+
+	// Поля менеджерского вида
+	DiscountUpdatePolicy *string            `json:"discount_update_policy,omitempty"`
+	CardStats            *CardStatsResponse `json:"card_stats,omitempty"`
+	CreatedAt            *time.Time         `json:"created_at,omitempty"`
+	UpdatedAt            *time.Time         `json:"updated_at,omitempty"`
+}
+
+// CardStatsResponse количество выпущенных карт компании по статусам
+type CardStatsResponse struct {
+	Total     int64 `json:"total"`
+	Active    int64 `json:"active"`
+	Suspended int64 `json:"suspended"`
+	Disabled  int64 `json:"disabled"`
+	Expired   int64 `json:"expired"`
+}
+
+// NewPublicLoyaltyConfigView строит публичный вид конфигурации
+func NewPublicLoyaltyConfigView(config *domain.LoyaltyConfig) *LoyaltyConfigViewResponse {
+	discountPercentage := 0.0
+	if config.DiscountPercentage != nil {
+		discountPercentage = *config.DiscountPercentage
+	}
+
+	return &LoyaltyConfigViewResponse{
+		View:               LoyaltyConfigViewPublic,
+		CompanyID:          config.CompanyID,
+		IsEnabled:          config.IsEnabled,
+		CardType:           string(config.CardType),
+		DiscountPercentage: discountPercentage,
+		ProgressiveConfig:  FromDomainProgressiveConfig(config.ProgressiveConfig),
+		PointsConfig:       FromDomainPointsConfig(config.PointsConfig),
+		CardValidityDays:   config.CardValidityDays,
+	}
+}
+
+// NewManagerLoyaltyConfigView строит менеджерский вид конфигурации со статистикой карт
+func NewManagerLoyaltyConfigView(config *domain.LoyaltyConfig, counts map[domain.CardStatus]int64) *LoyaltyConfigViewResponse {
+	view := NewPublicLoyaltyConfigView(config)
+	policy := string(config.DiscountUpdatePolicy)
+
+	stats := &CardStatsResponse{
+		Active:    counts[domain.CardStatusActive],
+		Suspended: counts[domain.CardStatusSuspended],
+		Disabled:  counts[domain.CardStatusDisabled],
+		Expired:   counts[domain.CardStatusExpired],
+	}
+	for _, count := range counts {
+		stats.Total += count
+	}
+
+	view.View = LoyaltyConfigViewManager
+	view.DiscountUpdatePolicy = &policy
+	view.CardStats = stats
+	view.CreatedAt = &config.CreatedAt
+	view.UpdatedAt = &config.UpdatedAt
+
+	return view
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/types"
+)
+
+// HappyHourWindowDTO интервал «счастливых часов» в один день недели
+type HappyHourWindowDTO struct {
+	// Weekday день недели ISO 8601 (1 - понедельник, 7 - воскресенье)
+	Weekday int `json:"weekday"`
+	// Start и End интервал [start, end) в формате HH:MM по местному времени компании
+	Start types.TimeString `json:"start"`
+	End   types.TimeString `json:"end"`
+	// DiscountPercentage дополнительная скидка, прибавляется к скидке карты
+	DiscountPercentage float64 `json:"discount_percentage"`
+}
+
+// SaveHappyHoursRequest запрос на замену «счастливых часов» компании целиком
+// Пустой список windows отключает «счастливые часы»
+type SaveHappyHoursRequest struct {
+	// Timezone часовой пояс IANA (необязателен): должен совпадать с поясом часов работы компании
+	Timezone string               `json:"timezone,omitempty"`
+	Windows  []HappyHourWindowDTO `json:"windows"`
+}
+
+// HappyHoursResponse «счастливые часы» компании
+type HappyHoursResponse struct {
+	CompanyID     int64                `json:"company_id"`
+	Timezone      string               `json:"timezone"`
+	Windows       []HappyHourWindowDTO `json:"windows"`
+	UpdatedBy     int64                `json:"updated_by"`
+	UpdatedByRole string               `json:"updated_by_role"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// CardHappyHourResponse интервал «счастливых часов», действующий для карты в момент запроса
+type CardHappyHourResponse struct {
+	HappyHourWindowDTO
+	Timezone string `json:"timezone"`
+}
+
+// ToDomain конвертирует запрос в domain модель «счастливых часов» компании companyID
+func (r *SaveHappyHoursRequest) ToDomain(companyID int64) *domain.HappyHours {
+	happyHours := &domain.HappyHours{
+		CompanyID: companyID,
+		Timezone:  r.Timezone,
+		Windows:   make([]domain.HappyHourWindow, 0, len(r.Windows)),
+	}
+
+	for _, window := range r.Windows {
+		happyHours.Windows = append(happyHours.Windows, domain.HappyHourWindow{
+			Weekday:            window.Weekday,
+			Start:              window.Start,
+			End:                window.End,
+			DiscountPercentage: window.DiscountPercentage,
+		})
+	}
+
+	return happyHours
+}
+
+// FromDomainHappyHours конвертирует domain модель «счастливых часов» в DTO
+func FromDomainHappyHours(happyHours *domain.HappyHours) *HappyHoursResponse {
+	resp := &HappyHoursResponse{
+		CompanyID:     happyHours.CompanyID,
+		Timezone:      happyHours.Timezone,
+		Windows:       make([]HappyHourWindowDTO, 0, len(happyHours.Windows)),
+		UpdatedBy:     happyHours.UpdatedBy,
+		UpdatedByRole: string(happyHours.UpdatedByRole),
+		CreatedAt:     happyHours.CreatedAt,
+		UpdatedAt:     happyHours.UpdatedAt,
+	}
+
+	for _, window := range happyHours.Windows {
+		resp.Windows = append(resp.Windows, fromDomainHappyHourWindow(window))
+	}
+
+	return resp
+}
+
+// FromDomainCardHappyHour конвертирует действующий интервал в DTO карты
+func FromDomainCardHappyHour(happyHours *domain.HappyHours, window *domain.HappyHourWindow) *CardHappyHourResponse {
+	return &CardHappyHourResponse{
+		HappyHourWindowDTO: fromDomainHappyHourWindow(*window),
+		Timezone:           happyHours.Timezone,
+	}
+}
+
+func fromDomainHappyHourWindow(window domain.HappyHourWindow) HappyHourWindowDTO {
+	return HappyHourWindowDTO{
+		Weekday:            window.Weekday,
+		Start:              window.Start,
+		End:                window.End,
+		DiscountPercentage: window.DiscountPercentage,
+	}
+}
@@ -14,30 +14,93 @@ type CreateLoyaltyCardRequest struct {
 
 // LoyaltyCardResponse ответ с данными карты лояльности
 type LoyaltyCardResponse struct {
-	CardID             int64     `json:"card_id"`
-	UserID             int64     `json:"user_id"`
-	CompanyID          int64     `json:"company_id"`
-	CardType           string    `json:"card_type"`
-	Status             string    `json:"status"`
-	DiscountPercentage float64   `json:"discount_percentage"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	CardID             int64                      `json:"card_id"`
+	UserID             int64                      `json:"user_id"`
+	CompanyID          int64                      `json:"company_id"`
+	CardType           string                     `json:"card_type"`
+	Status             string                     `json:"status"`
+	StatusReason       *string                    `json:"status_reason,omitempty"`
+	StatusChangedAt    *time.Time                 `json:"status_changed_at,omitempty"`
+	DiscountPercentage float64                    `json:"discount_percentage"`
+	VisitsCount        int                        `json:"visits_count"`
+	PointsBalance      int64                      `json:"points_balance"`
+	Progressive        *ProgressiveStatusResponse `json:"progressive,omitempty"`
+	ExpiresAt          *time.Time                 `json:"expires_at,omitempty"`
+	QRToken            string                     `json:"qr_token,omitempty"`
+	QRTokenExpiresAt   *time.Time                 `json:"qr_token_expires_at,omitempty"`
+	CreatedAt          time.Time                  `json:"created_at"`
+	UpdatedAt          time.Time                  `json:"updated_at"`
+
+	// Скидка и множитель баллов с учётом «счастливых часов» и акций, действующих в момент запроса
+	// Заполняются только при чтении одной карты (GetCard, проверка QR-токена)
+	EffectiveDiscountPercentage *float64 `json:"effective_discount_percentage,omitempty"`
+	// DiscountCampaignID акция, которая дала итоговую скидку (нет - действует скидка карты)
+	DiscountCampaignID *int64                  `json:"discount_campaign_id,omitempty"`
+	PointsMultiplier   *float64                `json:"points_multiplier,omitempty"`
+	Campaigns          []*CardCampaignResponse `json:"campaigns,omitempty"`
+	// HappyHour интервал «счастливых часов», действующий по местному времени компании
+	HappyHour *CardHappyHourResponse `json:"happy_hour,omitempty"`
+}
+
+// ProgressiveStatusResponse текущий уровень карты с прогрессивной скидкой
+type ProgressiveStatusResponse struct {
+	CurrentTier                int      `json:"current_tier"`
+	TiersTotal                 int      `json:"tiers_total"`
+	TierMinVisits              int      `json:"tier_min_visits"`
+	TierDiscountPercentage     float64  `json:"tier_discount_percentage"`
+	NextTierMinVisits          *int     `json:"next_tier_min_visits,omitempty"`
+	NextTierDiscountPercentage *float64 `json:"next_tier_discount_percentage,omitempty"`
+	VisitsToNextTier           *int     `json:"visits_to_next_tier,omitempty"`
+}
+
+// ProgressiveTierDTO уровень прогрессивной скидки
+type ProgressiveTierDTO struct {
+	MinVisits          int     `json:"min_visits"`
+	DiscountPercentage float64 `json:"discount_percentage"`
+}
+
+// ProgressiveConfigDTO конфигурация прогрессивной скидки
+type ProgressiveConfigDTO struct {
+	Tiers []ProgressiveTierDTO `json:"tiers"`
+}
+
+// PointsConfigDTO конфигурация накопительной системы
+type PointsConfigDTO struct {
+	PointsPerRuble float64 `json:"points_per_ruble"`
+	RedemptionRate float64 `json:"redemption_rate"`
 }
 
 // ConfigureLoyaltyRequest запрос на настройку программы лояльности
 type ConfigureLoyaltyRequest struct {
-	DiscountPercentage float64 `json:"discount_percentage"`
-	IsEnabled          *bool   `json:"is_enabled,omitempty"`
+	CardType           *string               `json:"card_type,omitempty"`
+	DiscountPercentage *float64              `json:"discount_percentage,omitempty"`
+	ProgressiveConfig  *ProgressiveConfigDTO `json:"progressive_config,omitempty"`
+	PointsConfig       *PointsConfigDTO      `json:"points_config,omitempty"`
+	CardValidityDays   *int                  `json:"card_validity_days,omitempty"` // 0 - карты бессрочные
+	// DiscountUpdatePolicy apply_to_all / new_cards_only / never_lower
+	DiscountUpdatePolicy *string `json:"discount_update_policy,omitempty"`
+	IsEnabled            *bool   `json:"is_enabled,omitempty"`
+	// EffectiveFrom момент, с которого изменение начнёт действовать (nil или прошедший момент - сразу)
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
 }
 
 // LoyaltyConfigResponse ответ с данными конфигурации программы лояльности
 type LoyaltyConfigResponse struct {
-	CompanyID          int64     `json:"company_id"`
-	CardType           string    `json:"card_type"`
-	IsEnabled          bool      `json:"is_enabled"`
-	DiscountPercentage float64   `json:"discount_percentage"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	CompanyID          int64                 `json:"company_id"`
+	CardType           string                `json:"card_type"`
+	IsEnabled          bool                  `json:"is_enabled"`
+	DiscountPercentage float64               `json:"discount_percentage"`
+	ProgressiveConfig  *ProgressiveConfigDTO `json:"progressive_config,omitempty"`
+	PointsConfig       *PointsConfigDTO      `json:"points_config,omitempty"`
+	CardValidityDays   int                   `json:"card_validity_days"`
+	// DiscountUpdatePolicy политика применения изменённой скидки к выпущенным картам
+	DiscountUpdatePolicy string `json:"discount_update_policy"`
+	// CardsUpdated количество карт, получивших новую скидку при этом изменении
+	CardsUpdated *int64 `json:"cards_updated,omitempty"`
+	// ScheduledChange запланированное этим запросом изменение (конфигурация в ответе - действующая)
+	ScheduledChange *LoyaltyConfigScheduleResponse `json:"scheduled_change,omitempty"`
+	CreatedAt       time.Time                      `json:"created_at"`
+	UpdatedAt       time.Time                      `json:"updated_at"`
 }
 
 // FromDomainLoyaltyCard конвертирует domain модель карты в DTO
@@ -48,12 +111,36 @@ func FromDomainLoyaltyCard(card *domain.LoyaltyCard) *LoyaltyCardResponse {
 		CompanyID:          card.CompanyID,
 		CardType:           string(card.CardType),
 		Status:             string(card.Status),
+		StatusReason:       card.StatusReason,
+		StatusChangedAt:    card.StatusChangedAt,
 		DiscountPercentage: card.DiscountPercentage,
+		VisitsCount:        card.VisitsCount,
+		PointsBalance:      card.PointsBalance,
+		ExpiresAt:          card.ExpiresAt,
 		CreatedAt:          card.CreatedAt,
 		UpdatedAt:          card.UpdatedAt,
 	}
 }
 
+// FromDomainProgressiveStatus конвертирует уровень прогрессивной скидки в DTO
+func FromDomainProgressiveStatus(status domain.ProgressiveStatus, tiersTotal, visits int) *ProgressiveStatusResponse {
+	resp := &ProgressiveStatusResponse{
+		CurrentTier:            status.Level,
+		TiersTotal:             tiersTotal,
+		TierMinVisits:          status.CurrentTier.MinVisits,
+		TierDiscountPercentage: status.CurrentTier.DiscountPercentage,
+	}
+
+	if status.NextTier != nil {
+		visitsToNext := status.NextTier.MinVisits - visits
+		resp.NextTierMinVisits = &status.NextTier.MinVisits
+		resp.NextTierDiscountPercentage = &status.NextTier.DiscountPercentage
+		resp.VisitsToNextTier = &visitsToNext
+	}
+
+	return resp
+}
+
 // FromDomainLoyaltyConfig конвертирует domain модель конфигурации в DTO
 func FromDomainLoyaltyConfig(config *domain.LoyaltyConfig) *LoyaltyConfigResponse {
 	discountPercentage := 0.0
@@ -62,11 +149,77 @@ func FromDomainLoyaltyConfig(config *domain.LoyaltyConfig) *LoyaltyConfigRespons
 	}
 
 	return &LoyaltyConfigResponse{
-		CompanyID:          config.CompanyID,
-		CardType:           string(config.CardType),
-		IsEnabled:          config.IsEnabled,
-		DiscountPercentage: discountPercentage,
-		CreatedAt:          config.CreatedAt,
-		UpdatedAt:          config.UpdatedAt,
+		CompanyID:            config.CompanyID,
+		CardType:             string(config.CardType),
+		IsEnabled:            config.IsEnabled,
+		DiscountPercentage:   discountPercentage,
+		ProgressiveConfig:    FromDomainProgressiveConfig(config.ProgressiveConfig),
+		PointsConfig:         FromDomainPointsConfig(config.PointsConfig),
+		CardValidityDays:     config.CardValidityDays,
+		DiscountUpdatePolicy: string(config.DiscountUpdatePolicy),
+		CreatedAt:            config.CreatedAt,
+		UpdatedAt:            config.UpdatedAt,
+	}
+}
+
+// FromDomainProgressiveConfig конвертирует domain конфигурацию уровней в DTO
+func FromDomainProgressiveConfig(cfg *domain.ProgressiveConfig) *ProgressiveConfigDTO {
+	if cfg == nil {
+		return nil
+	}
+
+	dto := &ProgressiveConfigDTO{
+		Tiers: make([]ProgressiveTierDTO, 0, len(cfg.Tiers)),
+	}
+	for _, tier := range cfg.Tiers {
+		dto.Tiers = append(dto.Tiers, ProgressiveTierDTO{
+			MinVisits:          tier.MinVisits,
+			DiscountPercentage: tier.DiscountPercentage,
+		})
+	}
+
+	return dto
+}
+
+// ToDomain конвертирует DTO конфигурации уровней в domain модель
+func (dto *ProgressiveConfigDTO) ToDomain() *domain.ProgressiveConfig {
+	if dto == nil {
+		return nil
+	}
+
+	cfg := &domain.ProgressiveConfig{
+		Tiers: make([]domain.ProgressiveTier, 0, len(dto.Tiers)),
+	}
+	for _, tier := range dto.Tiers {
+		cfg.Tiers = append(cfg.Tiers, domain.ProgressiveTier{
+			MinVisits:          tier.MinVisits,
+			DiscountPercentage: tier.DiscountPercentage,
+		})
+	}
+
+	return cfg
+}
+
+// FromDomainPointsConfig конвертирует domain конфигурацию накопительной системы в DTO
+func FromDomainPointsConfig(cfg *domain.PointsConfig) *PointsConfigDTO {
+	if cfg == nil {
+		return nil
+	}
+
+	return &PointsConfigDTO{
+		PointsPerRuble: cfg.PointsPerRuble,
+		RedemptionRate: cfg.RedemptionRate,
+	}
+}
+
+// ToDomain конвертирует DTO конфигурации накопительной системы в domain модель
+func (dto *PointsConfigDTO) ToDomain() *domain.PointsConfig {
+	if dto == nil {
+		return nil
+	}
+
+	return &domain.PointsConfig{
+		PointsPerRuble: dto.PointsPerRuble,
+		RedemptionRate: dto.RedemptionRate,
 	}
 }
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+// AccruePointsRequest запрос на начисление баллов за покупку
+type AccruePointsRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// RedeemPointsRequest запрос на списание баллов
+type RedeemPointsRequest struct {
+	Points int64 `json:"points"`
+}
+
+// PointsTransactionResponse ответ с данными операции с баллами
+type PointsTransactionResponse struct {
+	TransactionID int64     `json:"transaction_id"`
+	CardID        int64     `json:"card_id"`
+	Type          string    `json:"type"`
+	Points        int64     `json:"points"`
+	Amount        float64   `json:"amount"`
+	PointsBalance int64     `json:"points_balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// FromDomainLoyaltyTransaction конвертирует domain модель операции с баллами в DTO
+func FromDomainLoyaltyTransaction(tx *domain.LoyaltyTransaction) *PointsTransactionResponse {
+	return &PointsTransactionResponse{
+		TransactionID: tx.ID,
+		CardID:        tx.CardID,
+		Type:          string(tx.Type),
+		Points:        tx.Points,
+		Amount:        tx.Amount,
+		PointsBalance: tx.BalanceAfter,
+		CreatedAt:     tx.CreatedAt,
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// VerifyCardRequest запрос на проверку QR-токена карты
+type VerifyCardRequest struct {
+	QRToken string `json:"qr_token"`
+}
+
+// VerifyCardResponse результат проверки QR-токена с актуальными данными карты
+type VerifyCardResponse struct {
+	// DiscountApplicable true, если карта активна и программа компании включена
+	DiscountApplicable bool `json:"discount_applicable"`
+	// DiscountPercentage скидка, которую нужно применить (0, если скидка неприменима)
+	DiscountPercentage float64              `json:"discount_percentage"`
+	ProgramEnabled     bool                 `json:"program_enabled"`
+	TokenExpiresAt     time.Time            `json:"token_expires_at"`
+	Card               *LoyaltyCardResponse `json:"card"`
+}
@@ -0,0 +1,31 @@
+package models
+
+const (
+	// DefaultUserCardsLimit размер страницы списка карт клиента по умолчанию
+	DefaultUserCardsLimit = 20
+	// MaxUserCardsLimit максимальный размер страницы списка карт клиента
+	MaxUserCardsLimit = 100
+)
+
+// ListUserCardsRequest параметры списка карт клиента
+type ListUserCardsRequest struct {
+	// Status фильтр по статусу карты
+	Status *string
+	// Cursor значение next_cursor предыдущей страницы
+	Cursor *string
+	// Limit размер страницы (0 - DefaultUserCardsLimit)
+	Limit int
+}
+
+// UserLoyaltyCardResponse карта клиента вместе с публичными параметрами программы компании
+type UserLoyaltyCardResponse struct {
+	*LoyaltyCardResponse
+	Program *LoyaltyConfigViewResponse `json:"program"`
+}
+
+// UserLoyaltyCardsResponse страница списка карт клиента
+type UserLoyaltyCardsResponse struct {
+	Cards []*UserLoyaltyCardResponse `json:"cards"`
+	// NextCursor курсор следующей страницы (nil - страница последняя)
+	NextCursor *string `json:"next_cursor"`
+}
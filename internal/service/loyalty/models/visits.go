@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+// RecordVisitRequest запрос на запись визита клиента
+type RecordVisitRequest struct {
+	Amount    float64    `json:"amount"`
+	ServiceID *int64     `json:"service_id,omitempty"`
+	VisitedAt *time.Time `json:"visited_at,omitempty"` // По умолчанию - время запроса
+}
+
+// VisitResponse ответ с данными записанного визита и обновлённой картой
+type VisitResponse struct {
+	VisitID     int64                `json:"visit_id"`
+	CardID      int64                `json:"card_id"`
+	Amount      float64              `json:"amount"`
+	ServiceID   *int64               `json:"service_id,omitempty"`
+	VisitedAt   time.Time            `json:"visited_at"`
+	VisitNumber int                  `json:"visit_number"`
+	Card        *LoyaltyCardResponse `json:"card"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+// FromDomainLoyaltyVisit конвертирует domain модель визита в DTO
+func FromDomainLoyaltyVisit(visit *domain.LoyaltyVisit, card *LoyaltyCardResponse) *VisitResponse {
+	return &VisitResponse{
+		VisitID:     visit.ID,
+		CardID:      visit.CardID,
+		Amount:      visit.Amount,
+		ServiceID:   visit.ServiceID,
+		VisitedAt:   visit.VisitedAt,
+		VisitNumber: visit.VisitNumber,
+		Card:        card,
+		CreatedAt:   visit.CreatedAt,
+	}
+}
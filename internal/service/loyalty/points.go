@@ -0,0 +1,170 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	cardRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_card"
+	txRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_transaction"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// AccruePoints начисляет баллы на карту за покупку на сумму req.Amount
+// Требует проверки прав: пользователь должен быть менеджером компании карты или суперпользователем
+func (s *Service) AccruePoints(ctx context.Context, cardID int64, actor models.Actor, req *models.AccruePointsRequest) (*models.PointsTransactionResponse, error) {
+	// 1. Валидируем сумму покупки
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than 0", ErrInvalidInput)
+	}
+
+	// 2. Загружаем карту, проверяем права и параметры накопительной системы
+	card, pointsConfig, role, err := s.preparePointsOperation(ctx, cardID, actor, "AccruePoints")
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Рассчитываем количество баллов с учётом множителя действующих акций
+	now := time.Now()
+	campaigns, err := s.activeCampaigns(ctx, card.CompanyID, now, "AccruePoints")
+	if err != nil {
+		return nil, err
+	}
+	multiplier := domain.ApplyPointsCampaigns(campaigns, now).PointsMultiplier
+
+	points := pointsConfig.PointsForAmount(req.Amount * multiplier)
+	if points <= 0 {
+		return nil, fmt.Errorf("%w: amount is too small to accrue points", ErrInvalidInput)
+	}
+
+	// 4. Начисляем баллы (баланс и журнал операций обновляются атомарно, вместе с журналом изменений)
+	var tx *domain.LoyaltyTransaction
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		input := domain.CreateLoyaltyTransactionInput{
+			CardID:        card.ID,
+			Points:        points,
+			Amount:        req.Amount,
+			CreatedByRole: role,
+		}
+		input.CreatedBy, input.CreatedByService = actorAuthor(actor)
+
+		accrued, err := s.transactionRepo.Accrue(ctx, input)
+		if err != nil {
+			if errors.Is(err, txRepo.ErrCardNotActive) {
+				// Карта была деактивирована или истекла между чтением и записью
+				return ErrCardNotActive
+			}
+			return fmt.Errorf("%w: AccruePoints - repository error: %v", ErrInternal, err)
+		}
+		tx = accrued
+
+		return s.recordAudit(ctx, actor, role, pointsAuditChange(domain.AuditActionPointsAccrued, card, accrued.BalanceAfter-points, accrued.BalanceAfter))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.FromDomainLoyaltyTransaction(tx), nil
+}
+
+// RedeemPoints списывает баллы с карты
+// Требует проверки прав: пользователь должен быть менеджером компании карты или суперпользователем
+// Баланс не может стать отрицательным: при нехватке баллов возвращается ErrInsufficientPoints
+func (s *Service) RedeemPoints(ctx context.Context, cardID int64, actor models.Actor, req *models.RedeemPointsRequest) (*models.PointsTransactionResponse, error) {
+	// 1. Валидируем количество баллов
+	if req.Points <= 0 {
+		return nil, fmt.Errorf("%w: points must be greater than 0", ErrInvalidInput)
+	}
+
+	// 2. Загружаем карту, проверяем права и параметры накопительной системы
+	card, pointsConfig, role, err := s.preparePointsOperation(ctx, cardID, actor, "RedeemPoints")
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Списываем баллы (проверка баланса выполняется в том же запросе, что и списание)
+	var tx *domain.LoyaltyTransaction
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		input := domain.CreateLoyaltyTransactionInput{
+			CardID:        card.ID,
+			Points:        req.Points,
+			Amount:        pointsConfig.RedemptionValue(req.Points),
+			CreatedByRole: role,
+		}
+		input.CreatedBy, input.CreatedByService = actorAuthor(actor)
+
+		redeemed, err := s.transactionRepo.Redeem(ctx, input)
+		if err != nil {
+			if errors.Is(err, txRepo.ErrInsufficientPoints) {
+				return ErrInsufficientPoints
+			}
+			if errors.Is(err, txRepo.ErrCardNotActive) {
+				return ErrCardNotActive
+			}
+			return fmt.Errorf("%w: RedeemPoints - repository error: %v", ErrInternal, err)
+		}
+		tx = redeemed
+
+		return s.recordAudit(ctx, actor, role, pointsAuditChange(domain.AuditActionPointsRedeemed, card, redeemed.BalanceAfter+req.Points, redeemed.BalanceAfter))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.FromDomainLoyaltyTransaction(tx), nil
+}
+
+// pointsAuditChange описывает изменение баланса карты
+// Баланс до операции вычисляется из баланса после неё: прочитанная ранее карта могла устареть
+func pointsAuditChange(action domain.AuditAction, card *domain.LoyaltyCard, balanceBefore, balanceAfter int64) auditChange {
+	before := *card
+	before.PointsBalance = balanceBefore
+
+	after := *card
+	after.PointsBalance = balanceAfter
+
+	return cardAuditChange(action, &before, &after)
+}
+
+// preparePointsOperation загружает карту, проверяет права менеджера, статус карты
+// и возвращает параметры накопительной системы компании и роль, в которой пользователь получил доступ
+func (s *Service) preparePointsOperation(
+	ctx context.Context,
+	cardID int64,
+	actor models.Actor,
+	action string,
+) (*domain.LoyaltyCard, *domain.PointsConfig, domain.ActorRole, error) {
+	card, err := s.cardRepo.GetByID(ctx, cardID)
+	if err != nil {
+		if errors.Is(err, cardRepo.ErrCardNotFound) {
+			return nil, nil, "", ErrCardNotFound
+		}
+		return nil, nil, "", fmt.Errorf("%w: preparePointsOperation - failed to get card: %v", ErrInternal, err)
+	}
+
+	role, err := s.checkManagerAccess(ctx, card.CompanyID, actor, action)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	config, err := s.activeConfig(ctx, card.CompanyID, time.Now(), action)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if !config.IsEnabled {
+		return nil, nil, "", ErrConfigDisabled
+	}
+
+	if config.CardType != domain.CardTypePointsBased || config.PointsConfig == nil {
+		return nil, nil, "", ErrPointsNotSupported
+	}
+
+	if !card.EffectiveStatus(time.Now()).GrantsDiscount() {
+		return nil, nil, "", ErrCardNotActive
+	}
+
+	return card, config.PointsConfig, role, nil
+}
@@ -0,0 +1,186 @@
+package loyalty
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	txRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_transaction"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/jsondiff"
+)
+
+// newPointsService собирает сервис с накопительной программой компании и картой с балансом 100 баллов
+func newPointsService(log *callLog, card *domain.LoyaltyCard, transactions *fakeTransactionRepo, audit *fakeAuditRepo, campaigns ...*domain.LoyaltyCampaign) *Service {
+	configs := &fakeConfigRepo{config: &domain.LoyaltyConfig{
+		ID:                   1,
+		CompanyID:            testCompanyID,
+		CardType:             domain.CardTypePointsBased,
+		IsEnabled:            true,
+		PointsConfig:         &domain.PointsConfig{PointsPerRuble: 1, RedemptionRate: 0.5},
+		DiscountUpdatePolicy: domain.DiscountUpdatePolicyNewCardsOnly,
+	}}
+
+	service := newTestService(log, configs, &fakeCardRepo{cards: []*domain.LoyaltyCard{card}}, audit)
+	// Чтения до начала транзакции в журнал вызовов не попадают
+	configs.log = nil
+	transactions.log = log
+	transactions.balance = card.PointsBalance
+	service.transactionRepo = transactions
+	service.campaignRepo = &fakeCampaignRepo{campaigns: campaigns}
+
+	return service
+}
+
+func newPointsCard() *domain.LoyaltyCard {
+	return &domain.LoyaltyCard{
+		ID:            1,
+		UserID:        100,
+		CompanyID:     testCompanyID,
+		CardType:      domain.CardTypePointsBased,
+		Status:        domain.CardStatusActive,
+		PointsBalance: 100,
+	}
+}
+
+// balanceChange возвращает изменение баланса карты из события журнала
+func balanceChange(t *testing.T, event domain.CreateAuditEventInput) (before, after int64) {
+	t.Helper()
+
+	var changes map[string]jsondiff.Change
+	require.NoError(t, json.Unmarshal(event.Changes, &changes))
+	require.NoError(t, json.Unmarshal(changes["points_balance"].Before, &before))
+	require.NoError(t, json.Unmarshal(changes["points_balance"].After, &after))
+
+	return before, after
+}
+
+func TestService_AccruePoints(t *testing.T) {
+	manager := models.Actor{UserID: testManagerID}
+
+	t.Run("accrues with campaign multiplier in one transaction", func(t *testing.T) {
+		log := &callLog{}
+		transactions, audit := &fakeTransactionRepo{}, &fakeAuditRepo{}
+		now := time.Now()
+		service := newPointsService(log, newPointsCard(), transactions, audit, &domain.LoyaltyCampaign{
+			ID:               1,
+			CompanyID:        testCompanyID,
+			StartsAt:         now.Add(-time.Hour),
+			EndsAt:           now.Add(time.Hour),
+			Timezone:         "UTC",
+			PointsMultiplier: 2,
+			StackingRule:     domain.CampaignStackingMax,
+		})
+
+		resp, err := service.AccruePoints(context.Background(), 1, manager, &models.AccruePointsRequest{Amount: 150})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(300), resp.Points)
+		assert.Equal(t, int64(400), resp.PointsBalance)
+		assert.Equal(t, []string{"begin", "transaction.accrue", "audit.points_accrued", "commit"}, log.calls)
+
+		require.Len(t, audit.events, 1)
+		before, after := balanceChange(t, audit.events[0])
+		assert.Equal(t, int64(100), before)
+		assert.Equal(t, int64(400), after)
+	})
+
+	t.Run("card deactivated before write", func(t *testing.T) {
+		log := &callLog{}
+		audit := &fakeAuditRepo{}
+		service := newPointsService(log, newPointsCard(), &fakeTransactionRepo{err: txRepo.ErrCardNotActive}, audit)
+
+		_, err := service.AccruePoints(context.Background(), 1, manager, &models.AccruePointsRequest{Amount: 150})
+		require.ErrorIs(t, err, ErrCardNotActive)
+
+		assert.Equal(t, []string{"begin", "transaction.accrue", "rollback"}, log.calls)
+		assert.Empty(t, audit.events)
+	})
+
+	t.Run("expired card", func(t *testing.T) {
+		log := &callLog{}
+		card := newPointsCard()
+		expiresAt := time.Now().Add(-time.Minute)
+		card.ExpiresAt = &expiresAt
+		service := newPointsService(log, card, &fakeTransactionRepo{}, &fakeAuditRepo{})
+
+		_, err := service.AccruePoints(context.Background(), 1, manager, &models.AccruePointsRequest{Amount: 150})
+		require.ErrorIs(t, err, ErrCardNotActive)
+
+		assert.Empty(t, log.calls)
+	})
+
+	t.Run("amount too small", func(t *testing.T) {
+		log := &callLog{}
+		service := newPointsService(log, newPointsCard(), &fakeTransactionRepo{}, &fakeAuditRepo{})
+
+		_, err := service.AccruePoints(context.Background(), 1, manager, &models.AccruePointsRequest{Amount: 0.5})
+		require.ErrorIs(t, err, ErrInvalidInput)
+
+		assert.Empty(t, log.calls)
+	})
+}
+
+func TestService_RedeemPoints(t *testing.T) {
+	manager := models.Actor{UserID: testManagerID}
+
+	t.Run("redeems in one transaction", func(t *testing.T) {
+		log := &callLog{}
+		audit := &fakeAuditRepo{}
+		service := newPointsService(log, newPointsCard(), &fakeTransactionRepo{}, audit)
+
+		resp, err := service.RedeemPoints(context.Background(), 1, manager, &models.RedeemPointsRequest{Points: 40})
+		require.NoError(t, err)
+
+		assert.Equal(t, 20.0, resp.Amount)
+		assert.Equal(t, int64(60), resp.PointsBalance)
+		assert.Equal(t, []string{"begin", "transaction.redeem", "audit.points_redeemed", "commit"}, log.calls)
+
+		require.Len(t, audit.events, 1)
+		before, after := balanceChange(t, audit.events[0])
+		assert.Equal(t, int64(100), before)
+		assert.Equal(t, int64(60), after)
+	})
+
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "insufficient balance", repoErr: txRepo.ErrInsufficientPoints, wantErr: ErrInsufficientPoints},
+		{name: "card deactivated before write", repoErr: txRepo.ErrCardNotActive, wantErr: ErrCardNotActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &callLog{}
+			audit := &fakeAuditRepo{}
+			service := newPointsService(log, newPointsCard(), &fakeTransactionRepo{err: tt.repoErr}, audit)
+
+			_, err := service.RedeemPoints(context.Background(), 1, manager, &models.RedeemPointsRequest{Points: 40})
+			require.ErrorIs(t, err, tt.wantErr)
+
+			assert.Equal(t, []string{"begin", "transaction.redeem", "rollback"}, log.calls)
+			assert.Empty(t, audit.events)
+		})
+	}
+
+	t.Run("not a points program", func(t *testing.T) {
+		log := &callLog{}
+		service := newPointsService(log, newPointsCard(), &fakeTransactionRepo{}, &fakeAuditRepo{})
+		discount := 10.0
+		config := service.configRepo.(*fakeConfigRepo).config
+		config.CardType = domain.CardTypeFixedDiscount
+		config.DiscountPercentage = &discount
+
+		_, err := service.RedeemPoints(context.Background(), 1, manager, &models.RedeemPointsRequest{Points: 40})
+		require.ErrorIs(t, err, ErrPointsNotSupported)
+
+		assert.Empty(t, log.calls)
+	})
+}
@@ -0,0 +1,93 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cardRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_card"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/qrtoken"
+)
+
+// VerifyCard проверяет QR-токен карты, предъявленный клиентом на кассе
+// Требует проверки прав: пользователь должен быть менеджером компании карты или суперпользователем
+// Возвращает актуальные статус карты и скидку, а не значения на момент выпуска токена
+func (s *Service) VerifyCard(ctx context.Context, actor models.Actor, req *models.VerifyCardRequest) (*models.VerifyCardResponse, error) {
+	// 1. Валидируем входные данные
+	if req.QRToken == "" {
+		return nil, fmt.Errorf("%w: qr_token is required", ErrInvalidInput)
+	}
+
+	// 2. Проверяем подпись и срок действия токена
+	claims, err := s.qrSigner.Verify(req.QRToken)
+	if err != nil {
+		if errors.Is(err, qrtoken.ErrTokenExpired) {
+			return nil, ErrQRTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQRToken, err)
+	}
+
+	// 3. Загружаем карту и сверяем её с данными токена
+	card, err := s.cardRepo.GetByID(ctx, claims.CardID)
+	if err != nil {
+		if errors.Is(err, cardRepo.ErrCardNotFound) {
+			return nil, ErrCardNotFound
+		}
+		return nil, fmt.Errorf("%w: VerifyCard - failed to get card: %v", ErrInternal, err)
+	}
+
+	if card.UserID != claims.UserID || card.CompanyID != claims.CompanyID {
+		return nil, fmt.Errorf("%w: token does not match card", ErrInvalidQRToken)
+	}
+
+	// 4. Проверяем, что кассир является менеджером компании карты
+	if _, err := s.checkManagerAccess(ctx, card.CompanyID, actor, "VerifyCard"); err != nil {
+		return nil, err
+	}
+
+	// 5. Получаем действующую конфигурацию программы
+	now := time.Now()
+	config, err := s.activeConfig(ctx, card.CompanyID, now, "VerifyCard")
+	if err != nil {
+		return nil, err
+	}
+
+	// 6. Скидка (с учётом «счастливых часов» и действующих акций) применяется только по активной карте включённой программы
+	cardResp := buildCardResponse(card, config)
+	effect, err := s.applyPromotions(ctx, cardResp, now, "VerifyCard")
+	if err != nil {
+		return nil, err
+	}
+	applicable := config.IsEnabled && card.EffectiveStatus(now).GrantsDiscount()
+
+	resp := &models.VerifyCardResponse{
+		DiscountApplicable: applicable,
+		ProgramEnabled:     config.IsEnabled,
+		TokenExpiresAt:     time.Unix(claims.ExpiresAt, 0).UTC(),
+		Card:               cardResp,
+	}
+	if applicable {
+		resp.DiscountPercentage = effect.DiscountPercentage
+	}
+
+	return resp, nil
+}
+
+// attachQRToken выпускает подписанный QR-токен для карты и добавляет его в ответ
+func (s *Service) attachQRToken(resp *models.LoyaltyCardResponse) error {
+	token, expiresAt, err := s.qrSigner.Issue(qrtoken.Claims{
+		CardID:    resp.CardID,
+		UserID:    resp.UserID,
+		CompanyID: resp.CompanyID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to issue qr token: %v", err)
+	}
+
+	resp.QRToken = token
+	resp.QRTokenExpiresAt = &expiresAt
+
+	return nil
+}
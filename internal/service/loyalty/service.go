@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
 	cardRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_card"
@@ -13,32 +14,58 @@ import (
 )
 
 type Service struct {
-	cardRepo     LoyaltyCardRepository
-	configRepo   LoyaltyConfigRepository
-	sellerClient SellerServiceClient
+	cardRepo        LoyaltyCardRepository
+	configRepo      LoyaltyConfigRepository
+	campaignRepo    LoyaltyCampaignRepository
+	happyHoursRepo  HappyHoursRepository
+	transactionRepo LoyaltyTransactionRepository
+	visitRepo       LoyaltyVisitRepository
+	auditRepo       AuditEventRepository
+	sellerClient    SellerServiceClient
+	qrSigner        QRTokenSigner
+	txManager       TransactionManager
+	logger          Logger
 }
 
 func NewService(
 	cardRepo LoyaltyCardRepository,
 	configRepo LoyaltyConfigRepository,
+	campaignRepo LoyaltyCampaignRepository,
+	happyHoursRepo HappyHoursRepository,
+	transactionRepo LoyaltyTransactionRepository,
+	visitRepo LoyaltyVisitRepository,
+	auditRepo AuditEventRepository,
 	sellerClient SellerServiceClient,
+	qrSigner QRTokenSigner,
+	txManager TransactionManager,
+	logger Logger,
 ) *Service {
 	return &Service{
-		cardRepo:     cardRepo,
-		configRepo:   configRepo,
-		sellerClient: sellerClient,
+		cardRepo:        cardRepo,
+		configRepo:      configRepo,
+		campaignRepo:    campaignRepo,
+		happyHoursRepo:  happyHoursRepo,
+		transactionRepo: transactionRepo,
+		visitRepo:       visitRepo,
+		auditRepo:       auditRepo,
+		sellerClient:    sellerClient,
+		qrSigner:        qrSigner,
+		txManager:       txManager,
+		logger:          logger,
 	}
 }
 
 // GetCard получает карту лояльности клиента в компании
-func (s *Service) GetCard(ctx context.Context, userID, companyID int64) (*models.LoyaltyCardResponse, error) {
+// Карту может получить её владелец, менеджер компании или суперпользователь
+func (s *Service) GetCard(ctx context.Context, actor models.Actor, userID, companyID int64) (*models.LoyaltyCardResponse, error) {
+	if _, err := s.checkCardAccess(ctx, actor, userID, companyID, "GetCard"); err != nil {
+		return nil, err
+	}
+
 	// 1. Сначала проверяем, что программа лояльности включена для компании
-	config, err := s.configRepo.GetByCompanyID(ctx, companyID)
+	config, err := s.activeConfig(ctx, companyID, time.Now(), "GetCard")
 	if err != nil {
-		if errors.Is(err, configRepo.ErrConfigNotFound) {
-			return nil, ErrConfigNotFound
-		}
-		return nil, fmt.Errorf("%w: GetCard - failed to get config: %v", ErrInternal, err)
+		return nil, err
 	}
 
 	if !config.IsEnabled {
@@ -54,18 +81,32 @@ func (s *Service) GetCard(ctx context.Context, userID, companyID int64) (*models
 		return nil, fmt.Errorf("%w: GetCard - repository error: %v", ErrInternal, err)
 	}
 
-	return models.FromDomainLoyaltyCard(card), nil
+	// 3. Рассчитываем скидку с учётом «счастливых часов» и действующих акций компании
+	resp := buildCardResponse(card, config)
+	if _, err := s.applyPromotions(ctx, resp, time.Now(), "GetCard"); err != nil {
+		return nil, err
+	}
+
+	// 4. Подписываем данные карты для QR-кода
+	if err := s.attachQRToken(resp); err != nil {
+		return nil, fmt.Errorf("%w: GetCard - %v", ErrInternal, err)
+	}
+
+	return resp, nil
 }
 
 // CreateCard создает новую карту лояльности для клиента
-func (s *Service) CreateCard(ctx context.Context, req *models.CreateLoyaltyCardRequest) (*models.LoyaltyCardResponse, error) {
-	// 1. Получаем конфигурацию программы лояльности компании
-	config, err := s.configRepo.GetByCompanyID(ctx, req.CompanyID)
+// Клиент выпускает карту себе, менеджер компании или суперпользователь - любому клиенту
+func (s *Service) CreateCard(ctx context.Context, actor models.Actor, req *models.CreateLoyaltyCardRequest) (*models.LoyaltyCardResponse, error) {
+	role, err := s.checkCardAccess(ctx, actor, req.UserID, req.CompanyID, "CreateCard")
 	if err != nil {
-		if errors.Is(err, configRepo.ErrConfigNotFound) {
-			return nil, ErrConfigNotFound
-		}
-		return nil, fmt.Errorf("%w: CreateCard - failed to get config: %v", ErrInternal, err)
+		return nil, err
+	}
+
+	// 1. Получаем действующую конфигурацию программы лояльности компании
+	config, err := s.activeConfig(ctx, req.CompanyID, time.Now(), "CreateCard")
+	if err != nil {
+		return nil, err
 	}
 
 	// 2. Проверяем, что программа лояльности включена
@@ -73,118 +114,321 @@ func (s *Service) CreateCard(ctx context.Context, req *models.CreateLoyaltyCardR
 		return nil, ErrConfigDisabled
 	}
 
-	// 3. Создаем карту с параметрами из конфигурации
+	// 3. Проверяем, что компания не удалена в SellerService: конфигурация удалённой компании
+	// остаётся включённой до прохода фоновой сверки компаний
+	if err := s.ensureCompanyExists(ctx, req.CompanyID); err != nil {
+		return nil, err
+	}
+
+	// 4. Создаем карту с параметрами из конфигурации
+	// Для прогрессивной скидки карта стартует с первого уровня (0 визитов),
+	// срок действия отсчитывается от момента выпуска
 	card := &domain.LoyaltyCard{
 		UserID:             req.UserID,
 		CompanyID:          req.CompanyID,
 		CardType:           config.CardType,
 		Status:             domain.CardStatusActive,
-		DiscountPercentage: *config.DiscountPercentage,
+		DiscountPercentage: config.BaseDiscount(),
+		ExpiresAt:          config.CardExpiresAt(time.Now()),
 	}
 
-	createdCard, err := s.cardRepo.Create(ctx, card)
+	// Карта и запись журнала сохраняются в одной транзакции
+	var createdCard *domain.LoyaltyCard
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		created, err := s.cardRepo.Create(ctx, card)
+		if err != nil {
+			if errors.Is(err, cardRepo.ErrCardAlreadyExists) {
+				return err
+			}
+			return fmt.Errorf("%w: CreateCard - repository error: %v", ErrInternal, err)
+		}
+		createdCard = created
+
+		return s.recordAudit(ctx, actor, role, cardAuditChange(domain.AuditActionCardCreated, nil, created))
+	})
 	if err != nil {
+		// Существующая карта проверяется после отката транзакции
 		if errors.Is(err, cardRepo.ErrCardAlreadyExists) {
-			return nil, ErrCardAlreadyExists
+			return nil, s.existingCardError(ctx, req.UserID, req.CompanyID)
 		}
-		return nil, fmt.Errorf("%w: CreateCard - repository error: %v", ErrInternal, err)
+		return nil, err
 	}
 
-	return models.FromDomainLoyaltyCard(createdCard), nil
+	// 5. Подписываем данные карты для QR-кода
+	resp := buildCardResponse(createdCard, config)
+	if err := s.attachQRToken(resp); err != nil {
+		return nil, fmt.Errorf("%w: CreateCard - %v", ErrInternal, err)
+	}
+
+	return resp, nil
 }
 
 // ConfigureLoyalty настраивает программу лояльности компании
-// Требует проверки прав: пользователь должен быть менеджером компании
-func (s *Service) ConfigureLoyalty(ctx context.Context, companyID, userID int64, req *models.ConfigureLoyaltyRequest) (*models.LoyaltyConfigResponse, error) {
+// Требует проверки прав: пользователь должен быть менеджером компании или суперпользователем
+func (s *Service) ConfigureLoyalty(ctx context.Context, companyID int64, actor models.Actor, req *models.ConfigureLoyaltyRequest) (*models.LoyaltyConfigResponse, error) {
 	// 1. Проверяем права доступа через SellerService
-	if err := s.checkManagerAccess(ctx, companyID, userID); err != nil {
+	role, err := s.checkManagerAccess(ctx, companyID, actor, "ConfigureLoyalty")
+	if err != nil {
 		return nil, err
 	}
 
-	// 2. Валидируем discount_percentage
-	if req.DiscountPercentage < 0 || req.DiscountPercentage > 100 {
-		return nil, fmt.Errorf("%w: discount percentage must be between 0 and 100", ErrInvalidInput)
+	// 2. Изменение с будущим effective_from сохраняется как запланированное
+	if req.EffectiveFrom != nil && req.EffectiveFrom.After(time.Now()) {
+		return s.scheduleConfig(ctx, companyID, actor, role, req)
 	}
 
-	// 3. Проверяем, существует ли уже конфигурация
-	existingConfig, err := s.configRepo.GetByCompanyID(ctx, companyID)
+	return s.applyConfig(ctx, companyID, actor, role, req, domain.AuditActionConfigUpdated)
+}
+
+// applyConfig сохраняет конфигурацию из запроса, обновляет скидку выпущенных карт и записывает изменение в журнал
+// updateAction - действие в журнале, если конфигурация уже существовала
+func (s *Service) applyConfig(
+	ctx context.Context,
+	companyID int64,
+	actor models.Actor,
+	role domain.ActorRole,
+	req *models.ConfigureLoyaltyRequest,
+	updateAction domain.AuditAction,
+) (*models.LoyaltyConfigResponse, error) {
+	var config *domain.LoyaltyConfig
+	var cardsUpdated *int64
+
+	// 3. Чтение текущей конфигурации, её изменение и обновление карт выполняются в одной транзакции
+	// под блокировкой компании, поэтому параллельные вызовы не создают конфигурацию дважды
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.configRepo.LockCompany(ctx, companyID); err != nil {
+			return fmt.Errorf("%w: ConfigureLoyalty - failed to lock company config: %v", ErrInternal, err)
+		}
+
+		// 4. Получаем текущую конфигурацию (nil - программа ещё не настроена)
+		// Наступившие запланированные изменения сначала переносятся, чтобы запрос применялся поверх них
+		existingConfig, _, err := s.activateDueSchedules(ctx, companyID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// 5. Собираем итоговые параметры программы и валидируем их
+		// Незаданные в запросе поля берутся из текущей конфигурации
+		candidate, err := mergeConfigRequest(existingConfig, req)
+		if err != nil {
+			return err
+		}
+
+		if err := candidate.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+
+		// 6. Сохраняем конфигурацию
+		config, err = s.saveConfig(ctx, companyID, existingConfig, candidate, req.IsEnabled, actor.UserID, role)
+		if err != nil {
+			return err
+		}
 
-	if err != nil && !errors.Is(err, configRepo.ErrConfigNotFound) {
-		return nil, fmt.Errorf("%w: ConfigureLoyalty - failed to check existing config: %v", ErrInternal, err)
+		// 7. Обновляем скидку выпущенных карт и записываем изменение в журнал
+		cardsUpdated, err = s.finishConfigChange(ctx, existingConfig, config, actor, role, updateAction)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var config *domain.LoyaltyConfig
+	resp := models.FromDomainLoyaltyConfig(config)
+	resp.CardsUpdated = cardsUpdated
+
+	return resp, nil
+}
 
-	// 4. Если конфигурация существует - обновляем
+// finishConfigChange обновляет скидку выпущенных карт согласно политике discount_update_policy
+// и записывает изменение конфигурации с previous на config в журнал
+// updateAction - действие в журнале, если конфигурация уже существовала (previous не nil)
+// Возвращает количество карт, получивших новую скидку (nil - карты не обновлялись)
+func (s *Service) finishConfigChange(
+	ctx context.Context,
+	previous, config *domain.LoyaltyConfig,
+	actor models.Actor,
+	role domain.ActorRole,
+	updateAction domain.AuditAction,
+) (*int64, error) {
+	var cardsUpdated *int64
+	if bulkInput := config.CardDiscountUpdate(previous); bulkInput != nil {
+		updated, err := s.cardRepo.UpdateDiscountForCompany(ctx, *bulkInput)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ConfigureLoyalty - failed to update cards discount: %v", ErrInternal, err)
+		}
+		cardsUpdated = &updated
+	}
+
+	change := configAuditChange(previous, config, cardsUpdated)
+	if previous != nil {
+		change.action = updateAction
+	}
+
+	if err := s.recordAudit(ctx, actor, role, change); err != nil {
+		return nil, err
+	}
+
+	return cardsUpdated, nil
+}
+
+// saveConfig обновляет существующую конфигурацию или создает новую из проверенного candidate
+func (s *Service) saveConfig(
+	ctx context.Context,
+	companyID int64,
+	existingConfig, candidate *domain.LoyaltyConfig,
+	reqIsEnabled *bool,
+	updatedBy int64,
+	updatedByRole domain.ActorRole,
+) (*domain.LoyaltyConfig, error) {
+	// Если конфигурация существует - обновляем
 	if existingConfig != nil {
 		// Дефолтное значение isEnabled - текущее значение из БД
 		isEnabled := existingConfig.IsEnabled
 		// Если пришло новое значение - используем его
-		if req.IsEnabled != nil {
-			isEnabled = *req.IsEnabled
+		if reqIsEnabled != nil {
+			isEnabled = *reqIsEnabled
 		}
 
 		updateInput := domain.UpdateLoyaltyConfigInput{
-			CompanyID:          companyID,
-			IsEnabled:          &isEnabled,
-			DiscountPercentage: &req.DiscountPercentage,
+			CompanyID:            companyID,
+			CardType:             &candidate.CardType,
+			IsEnabled:            &isEnabled,
+			DiscountPercentage:   candidate.DiscountPercentage,
+			ProgressiveConfig:    candidate.ProgressiveConfig,
+			PointsConfig:         candidate.PointsConfig,
+			CardValidityDays:     &candidate.CardValidityDays,
+			DiscountUpdatePolicy: &candidate.DiscountUpdatePolicy,
+			UpdatedBy:            updatedBy,
+			UpdatedByRole:        updatedByRole,
 		}
 
-		config, err = s.configRepo.Update(ctx, updateInput)
+		config, err := s.configRepo.Update(ctx, updateInput)
 		if err != nil {
 			return nil, fmt.Errorf("%w: ConfigureLoyalty - failed to update config: %v", ErrInternal, err)
 		}
-	} else {
-		// 5. Если конфигурации нет - создаем новую
-		// Дефолтное значение isEnabled = false, если не указано обратное
-		isEnabled := false
-		if req.IsEnabled != nil {
-			isEnabled = *req.IsEnabled
-		}
 
-		createInput := domain.CreateLoyaltyConfigInput{
-			CompanyID:          companyID,
-			IsEnabled:          isEnabled,
-			DiscountPercentage: req.DiscountPercentage,
-		}
+		return config, nil
+	}
 
-		config, err = s.configRepo.Create(ctx, createInput)
-		if err != nil {
-			if errors.Is(err, configRepo.ErrConfigAlreadyExists) {
-				return nil, ErrConfigAlreadyExists
-			}
-			return nil, fmt.Errorf("%w: ConfigureLoyalty - failed to create config: %v", ErrInternal, err)
+	// Если конфигурации нет - создаем новую
+	// Дефолтное значение isEnabled = false, если не указано обратное
+	isEnabled := false
+	if reqIsEnabled != nil {
+		isEnabled = *reqIsEnabled
+	}
+
+	createInput := domain.CreateLoyaltyConfigInput{
+		CompanyID:            companyID,
+		CardType:             candidate.CardType,
+		IsEnabled:            isEnabled,
+		DiscountPercentage:   candidate.DiscountPercentage,
+		ProgressiveConfig:    candidate.ProgressiveConfig,
+		PointsConfig:         candidate.PointsConfig,
+		CardValidityDays:     candidate.CardValidityDays,
+		DiscountUpdatePolicy: candidate.DiscountUpdatePolicy,
+		UpdatedBy:            updatedBy,
+		UpdatedByRole:        updatedByRole,
+	}
+
+	config, err := s.configRepo.Create(ctx, createInput)
+	if err != nil {
+		if errors.Is(err, configRepo.ErrConfigAlreadyExists) {
+			return nil, ErrConfigAlreadyExists
 		}
+		return nil, fmt.Errorf("%w: ConfigureLoyalty - failed to create config: %v", ErrInternal, err)
 	}
 
-	return models.FromDomainLoyaltyConfig(config), nil
+	return config, nil
+}
+
+// existingCardError определяет ошибку повторного создания карты:
+// заблокированную менеджером карту нельзя обойти, запросив новую
+func (s *Service) existingCardError(ctx context.Context, userID, companyID int64) error {
+	existing, err := s.cardRepo.GetByUserAndCompany(ctx, userID, companyID)
+	if err != nil {
+		return ErrCardAlreadyExists
+	}
+
+	if existing.Status == domain.CardStatusSuspended || existing.Status == domain.CardStatusDisabled {
+		return ErrCardBlocked
+	}
+
+	return ErrCardAlreadyExists
+}
+
+// checkCardAccess проверяет доступ к карте клиента ownerID в компании companyID:
+// владельцу доступ разрешён сразу, остальным - как менеджерам компании (см. checkManagerAccess)
+// Возвращает роль, в которой пользователь получил доступ
+func (s *Service) checkCardAccess(ctx context.Context, actor models.Actor, ownerID, companyID int64, action string) (domain.ActorRole, error) {
+	if !actor.IsService() && actor.UserID == ownerID {
+		return domain.ActorRoleCustomer, nil
+	}
+
+	return s.checkManagerAccess(ctx, companyID, actor, action)
 }
 
 // checkManagerAccess проверяет, является ли пользователь менеджером компании
-func (s *Service) checkManagerAccess(ctx context.Context, companyID, userID int64) error {
+// Суперпользователь проходит проверку без обращения к SellerService, каждое такое действие
+// логируется с названием операции action. Внутренний сервис допускается к любой компании:
+// его права ограничены scopes API-ключа при маршрутизации. Возвращает роль, в которой пользователь получил доступ
+func (s *Service) checkManagerAccess(ctx context.Context, companyID int64, actor models.Actor, action string) (domain.ActorRole, error) {
+	if actor.IsService() {
+		s.logger.Info("Service access: action=%s, service=%s, company_id=%d", action, actor.Service, companyID)
+		return domain.ActorRoleService, nil
+	}
+
+	if actor.IsSuperuser() {
+		s.logger.Warn("Superuser access: action=%s, user_id=%d, company_id=%d", action, actor.UserID, companyID)
+		return domain.ActorRoleSuperuser, nil
+	}
+
 	// Получаем данные компании из SellerService
 	company, err := s.sellerClient.GetCompany(ctx, companyID)
 	if err != nil {
 		// Проверяем типы ошибок от SellerService
 		if errors.Is(err, sellerClient.ErrCompanyNotFound) {
-			return ErrConfigNotFound // Компания не найдена = нельзя настроить лояльность
+			return "", ErrConfigNotFound // Компания не найдена = нельзя настроить лояльность
 		}
-		// Все остальные ошибки от SellerService оборачиваем с контекстом
-		return fmt.Errorf("%w: seller service error: %v", ErrSellerServiceUnavailable, err)
+		// Все остальные ошибки от SellerService оборачиваем с сохранением вида сбоя
+		return "", sellerServiceError(err)
 	}
 
 	// Проверяем, есть ли userID в списке менеджеров
 	isManager := false
 	for _, managerID := range company.ManagerIDs {
-		if managerID == userID {
+		if managerID == actor.UserID {
 			isManager = true
 			break
 		}
 	}
 
 	if !isManager {
-		return ErrAccessDenied
+		return "", ErrAccessDenied
+	}
+
+	return domain.ActorRoleManager, nil
+}
+
+// ensureCompanyExists проверяет, что компания существует в SellerService
+func (s *Service) ensureCompanyExists(ctx context.Context, companyID int64) error {
+	if _, err := s.sellerClient.GetCompany(ctx, companyID); err != nil {
+		if errors.Is(err, sellerClient.ErrCompanyNotFound) {
+			return ErrCompanyNotFound
+		}
+		return sellerServiceError(err)
 	}
 
 	return nil
 }
+
+// sellerServiceError сохраняет вид сбоя SellerService (таймаут, недоступность, некорректный ответ),
+// чтобы обработчик выбрал подходящий HTTP статус
+func sellerServiceError(err error) error {
+	switch {
+	case errors.Is(err, sellerClient.ErrTimeout):
+		return fmt.Errorf("%w: seller service error: %v", ErrSellerServiceTimeout, err)
+	case errors.Is(err, sellerClient.ErrInvalidResponse):
+		return fmt.Errorf("%w: seller service error: %v", ErrSellerServiceBadResponse, err)
+	default:
+		return fmt.Errorf("%w: seller service error: %v", ErrSellerServiceUnavailable, err)
+	}
+}
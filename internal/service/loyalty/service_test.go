@@ -0,0 +1,134 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+const (
+	testCompanyID = 1
+	testManagerID = 10
+)
+
+// newTestService собирает сервис на фейках с общим журналом вызовов
+// Менеджер testManagerID управляет компанией testCompanyID
+func newTestService(log *callLog, configs *fakeConfigRepo, cards *fakeCardRepo, audit *fakeAuditRepo) *Service {
+	configs.log, cards.log, audit.log = log, log, log
+
+	return &Service{
+		cardRepo:   cards,
+		configRepo: configs,
+		auditRepo:  audit,
+		sellerClient: &fakeSellerClient{companies: map[int64]*sellerservice.Company{
+			testCompanyID: {ID: testCompanyID, ManagerIDs: []int64{testManagerID}},
+		}},
+		txManager: &fakeTxManager{log: log},
+		logger:    nopLogger{},
+	}
+}
+
+func TestService_ConfigureLoyalty(t *testing.T) {
+	manager := models.Actor{UserID: testManagerID}
+	discount := 10.0
+	enabled := true
+
+	t.Run("creates config under company lock", func(t *testing.T) {
+		log := &callLog{}
+		configs, audit := &fakeConfigRepo{}, &fakeAuditRepo{}
+		service := newTestService(log, configs, &fakeCardRepo{}, audit)
+
+		resp, err := service.ConfigureLoyalty(context.Background(), testCompanyID, manager, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &discount,
+			IsEnabled:          &enabled,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, discount, resp.DiscountPercentage)
+		assert.True(t, resp.IsEnabled)
+		assert.Equal(t, []string{
+			"begin", "config.lock", "config.get", "config.create", "audit.config_created", "commit",
+		}, log.calls)
+
+		require.Len(t, audit.events, 1)
+		assert.Equal(t, domain.ActorRoleManager, audit.events[0].ActorRole)
+		require.NotNil(t, audit.events[0].ActorID)
+		assert.Equal(t, int64(testManagerID), *audit.events[0].ActorID)
+	})
+
+	t.Run("updates cards in the same transaction", func(t *testing.T) {
+		log := &callLog{}
+		previous := 5.0
+		configs := &fakeConfigRepo{config: &domain.LoyaltyConfig{
+			ID:                   1,
+			CompanyID:            testCompanyID,
+			CardType:             domain.CardTypeFixedDiscount,
+			IsEnabled:            true,
+			DiscountPercentage:   &previous,
+			DiscountUpdatePolicy: domain.DiscountUpdatePolicyApplyToAll,
+		}}
+		cards := &fakeCardRepo{cards: []*domain.LoyaltyCard{
+			{ID: 1, CompanyID: testCompanyID, CardType: domain.CardTypeFixedDiscount, DiscountPercentage: previous},
+			{ID: 2, CompanyID: testCompanyID, CardType: domain.CardTypeFixedDiscount, DiscountPercentage: previous},
+		}}
+		service := newTestService(log, configs, cards, &fakeAuditRepo{})
+
+		resp, err := service.ConfigureLoyalty(context.Background(), testCompanyID, manager, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &discount,
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, resp.CardsUpdated)
+		assert.Equal(t, int64(2), *resp.CardsUpdated)
+		assert.Equal(t, []string{
+			"begin", "config.lock", "config.get", "config.update", "card.update_discount", "audit.config_updated", "commit",
+		}, log.calls)
+	})
+
+	t.Run("audit failure rolls back", func(t *testing.T) {
+		log := &callLog{}
+		service := newTestService(log, &fakeConfigRepo{}, &fakeCardRepo{}, &fakeAuditRepo{err: errors.New("connection reset")})
+
+		_, err := service.ConfigureLoyalty(context.Background(), testCompanyID, manager, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &discount,
+		})
+		require.ErrorIs(t, err, ErrInternal)
+
+		assert.Equal(t, "rollback", log.calls[len(log.calls)-1])
+		assert.NotContains(t, log.calls, "commit")
+	})
+
+	t.Run("invalid config rolls back", func(t *testing.T) {
+		log := &callLog{}
+		invalid := 150.0
+		configs := &fakeConfigRepo{}
+		service := newTestService(log, configs, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.ConfigureLoyalty(context.Background(), testCompanyID, manager, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &invalid,
+		})
+		require.ErrorIs(t, err, ErrInvalidInput)
+
+		assert.Equal(t, []string{"begin", "config.lock", "config.get", "rollback"}, log.calls)
+		assert.Nil(t, configs.config)
+	})
+
+	t.Run("not a manager", func(t *testing.T) {
+		log := &callLog{}
+		service := newTestService(log, &fakeConfigRepo{}, &fakeCardRepo{}, &fakeAuditRepo{})
+
+		_, err := service.ConfigureLoyalty(context.Background(), testCompanyID, models.Actor{UserID: 99}, &models.ConfigureLoyaltyRequest{
+			DiscountPercentage: &discount,
+		})
+		require.ErrorIs(t, err, ErrAccessDenied)
+
+		assert.Empty(t, log.calls)
+	})
+}
@@ -0,0 +1,118 @@
+package loyalty
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// ListUserCards возвращает карты клиента по всем компаниям с курсорной пагинацией
+// Список доступен самому клиенту, суперпользователю и внутренним сервисам,
+// карты компаний с выключенной программой скрываются
+func (s *Service) ListUserCards(ctx context.Context, userID int64, actor models.Actor, req *models.ListUserCardsRequest) (*models.UserLoyaltyCardsResponse, error) {
+	switch {
+	case actor.IsService():
+		s.logger.Info("Service access: action=ListUserCards, service=%s, target_user_id=%d", actor.Service, userID)
+	case actor.UserID != userID:
+		if !actor.IsSuperuser() {
+			return nil, ErrAccessDenied
+		}
+		s.logger.Warn("Superuser access: action=ListUserCards, user_id=%d, target_user_id=%d", actor.UserID, userID)
+	}
+
+	input, err := listUserCardsInput(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Запрашиваем на одну карту больше, чтобы понять, есть ли следующая страница
+	pageSize := input.Limit
+	input.Limit++
+
+	cards, err := s.cardRepo.ListByUser(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListUserCards - failed to list cards: %v", ErrInternal, err)
+	}
+
+	var nextCursor *string
+	if len(cards) > pageSize {
+		cards = cards[:pageSize]
+		cursor := encodeIDCursor(cards[pageSize-1].ID)
+		nextCursor = &cursor
+	}
+
+	companyIDs := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		companyIDs = append(companyIDs, card.CompanyID)
+	}
+
+	configs, err := s.configRepo.GetByCompanyIDs(ctx, companyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListUserCards - failed to get configs: %v", ErrInternal, err)
+	}
+
+	configs, err = s.configsAt(ctx, configs, time.Now(), "ListUserCards")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.UserLoyaltyCardsResponse{
+		Cards:      make([]*models.UserLoyaltyCardResponse, 0, len(cards)),
+		NextCursor: nextCursor,
+	}
+	for _, card := range cards {
+		// Программу могли выключить между запросами - такую карту тоже скрываем
+		config, ok := configs[card.CompanyID]
+		if !ok || !config.IsEnabled {
+			continue
+		}
+
+		cardResp := buildCardResponse(card, config)
+		if err := s.attachQRToken(cardResp); err != nil {
+			return nil, fmt.Errorf("%w: ListUserCards - %v", ErrInternal, err)
+		}
+
+		resp.Cards = append(resp.Cards, &models.UserLoyaltyCardResponse{
+			LoyaltyCardResponse: cardResp,
+			Program:             models.NewPublicLoyaltyConfigView(config),
+		})
+	}
+
+	return resp, nil
+}
+
+// listUserCardsInput проверяет параметры списка карт и строит входные данные репозитория
+func listUserCardsInput(userID int64, req *models.ListUserCardsRequest) (domain.ListUserCardsInput, error) {
+	input := domain.ListUserCardsInput{
+		UserID: userID,
+		Limit:  models.DefaultUserCardsLimit,
+	}
+
+	if req.Limit < 0 || req.Limit > models.MaxUserCardsLimit {
+		return input, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, models.MaxUserCardsLimit)
+	}
+	if req.Limit > 0 {
+		input.Limit = req.Limit
+	}
+
+	if req.Status != nil {
+		status, err := parseCardStatus(*req.Status)
+		if err != nil {
+			return input, err
+		}
+		input.Status = &status
+	}
+
+	if req.Cursor != nil {
+		afterID, err := decodeIDCursor(*req.Cursor)
+		if err != nil {
+			return input, err
+		}
+		input.AfterID = afterID
+	}
+
+	return input, nil
+}
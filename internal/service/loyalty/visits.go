@@ -0,0 +1,117 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+	cardRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_card"
+	visitRepo "github.com/m04kA/SMC-LoyaltySystemService/internal/infra/storage/loyalty_visit"
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/service/loyalty/models"
+)
+
+// visitClockSkew допустимое расхождение часов клиента: visited_at не может быть позже now + visitClockSkew
+const visitClockSkew = 5 * time.Minute
+
+// RecordVisit записывает визит клиента по карте лояльности
+// Требует проверки прав: пользователь должен быть менеджером компании карты или суперпользователем
+// Для прогрессивной скидки счётчик визитов и уровень карты обновляются в одном запросе с записью визита
+func (s *Service) RecordVisit(ctx context.Context, cardID int64, actor models.Actor, req *models.RecordVisitRequest) (*models.VisitResponse, error) {
+	// 1. Валидируем входные данные
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("%w: amount must not be negative", ErrInvalidInput)
+	}
+
+	if req.ServiceID != nil && *req.ServiceID <= 0 {
+		return nil, fmt.Errorf("%w: service_id must be positive", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	visitedAt := now
+	if req.VisitedAt != nil {
+		if req.VisitedAt.After(now.Add(visitClockSkew)) {
+			return nil, fmt.Errorf("%w: visited_at must not be in the future", ErrInvalidInput)
+		}
+		visitedAt = *req.VisitedAt
+	}
+
+	// 2. Загружаем карту и проверяем права менеджера
+	card, err := s.cardRepo.GetByID(ctx, cardID)
+	if err != nil {
+		if errors.Is(err, cardRepo.ErrCardNotFound) {
+			return nil, ErrCardNotFound
+		}
+		return nil, fmt.Errorf("%w: RecordVisit - failed to get card: %v", ErrInternal, err)
+	}
+
+	role, err := s.checkManagerAccess(ctx, card.CompanyID, actor, "RecordVisit")
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Проверяем программу лояльности компании и статус карты
+	config, err := s.activeConfig(ctx, card.CompanyID, now, "RecordVisit")
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.IsEnabled {
+		return nil, ErrConfigDisabled
+	}
+
+	if !card.EffectiveStatus(now).GrantsDiscount() {
+		return nil, ErrCardNotActive
+	}
+
+	// 4. Записываем визит; для прогрессивной скидки передаём уровни для пересчёта скидки карты
+	input := domain.CreateLoyaltyVisitInput{
+		CardID:         card.ID,
+		Amount:         req.Amount,
+		ServiceID:      req.ServiceID,
+		VisitedAt:      visitedAt,
+		RecordedByRole: role,
+	}
+	input.RecordedBy, input.RecordedByService = actorAuthor(actor)
+	if config.CardType == domain.CardTypeProgressiveDiscount && config.ProgressiveConfig != nil {
+		input.Tiers = config.ProgressiveConfig.Tiers
+	}
+
+	// Визит и запись журнала изменений сохраняются в одной транзакции
+	var visit *domain.LoyaltyVisit
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		created, err := s.visitRepo.Create(ctx, input)
+		if err != nil {
+			if errors.Is(err, visitRepo.ErrCardNotFound) {
+				// Карта была деактивирована или истекла между чтением и записью
+				return ErrCardNotActive
+			}
+			return fmt.Errorf("%w: RecordVisit - repository error: %v", ErrInternal, err)
+		}
+		visit = created
+
+		// Счётчик до визита вычисляется из номера визита: прочитанная ранее карта могла устареть
+		before := *card
+		before.VisitsCount = created.VisitNumber - 1
+
+		return s.recordAudit(ctx, actor, role, cardAuditChange(domain.AuditActionVisitRecorded, &before, visitedCard(card, config, created)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Отражаем новый счётчик визитов в ответе
+	return models.FromDomainLoyaltyVisit(visit, buildCardResponse(visitedCard(card, config, visit), config)), nil
+}
+
+// visitedCard возвращает копию карты после визита: с новым счётчиком и, для прогрессивной скидки, пересчитанной скидкой
+func visitedCard(card *domain.LoyaltyCard, config *domain.LoyaltyConfig, visit *domain.LoyaltyVisit) *domain.LoyaltyCard {
+	updated := *card
+	updated.VisitsCount = visit.VisitNumber
+	if config.CardType == domain.CardTypeProgressiveDiscount && config.ProgressiveConfig != nil && len(config.ProgressiveConfig.Tiers) > 0 {
+		updated.DiscountPercentage = config.ProgressiveConfig.StatusFor(visit.VisitNumber).CurrentTier.DiscountPercentage
+	}
+
+	return &updated
+}
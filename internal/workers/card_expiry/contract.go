@@ -0,0 +1,20 @@
+package card_expiry
+
+import "context"
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	ExpireOverdueCards(ctx context.Context, limit int) (int64, error)
+}
+
+// Metrics интерфейс сбора метрик процесса (nil, если метрики выключены)
+type Metrics interface {
+	RecordCardsExpired(service string, count int64)
+	RecordCardExpirySweep(service, status string)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
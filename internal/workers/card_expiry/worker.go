@@ -0,0 +1,125 @@
+package card_expiry
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	sweepStatusSuccess = "success"
+	sweepStatusError   = "error"
+)
+
+// Config параметры фонового процесса
+type Config struct {
+	Interval    time.Duration
+	BatchSize   int
+	ServiceName string
+}
+
+// Worker периодически переводит карты с истёкшим сроком действия в статус expired
+type Worker struct {
+	service LoyaltyService
+	metrics Metrics
+	logger  Logger
+	cfg     Config
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewWorker создает фоновый процесс истечения карт
+// metrics может быть nil, если метрики выключены
+func NewWorker(service LoyaltyService, metrics Metrics, logger Logger, cfg Config) *Worker {
+	return &Worker{
+		service: service,
+		metrics: metrics,
+		logger:  logger,
+		cfg:     cfg,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start запускает процесс в отдельной горутине
+// Первый проход выполняется сразу, следующие - раз в cfg.Interval
+func (w *Worker) Start() {
+	go w.run()
+}
+
+// Stop останавливает процесс и ждёт завершения текущего прохода (не дольше, чем ctx)
+func (w *Worker) Stop(ctx context.Context) error {
+	close(w.stopCh)
+
+	select {
+	case <-w.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run() {
+	defer close(w.doneCh)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.sweep()
+
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep обрабатывает просроченные карты пачками по cfg.BatchSize,
+// пока не обработает все или не получит сигнал остановки
+func (w *Worker) sweep() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Прерываем запрос к БД, если сервис останавливается
+	go func() {
+		select {
+		case <-w.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var total int64
+	for {
+		expired, err := w.service.ExpireOverdueCards(ctx, w.cfg.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.Error("Card expiry sweep failed: expired_before_error=%d, error=%v", total, err)
+				w.recordSweep(sweepStatusError)
+			}
+			return
+		}
+
+		total += expired
+		if w.metrics != nil && expired > 0 {
+			w.metrics.RecordCardsExpired(w.cfg.ServiceName, expired)
+		}
+
+		if expired < int64(w.cfg.BatchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		w.logger.Info("Card expiry sweep finished: expired=%d", total)
+	}
+	w.recordSweep(sweepStatusSuccess)
+}
+
+func (w *Worker) recordSweep(status string) {
+	if w.metrics != nil {
+		w.metrics.RecordCardExpirySweep(w.cfg.ServiceName, status)
+	}
+}
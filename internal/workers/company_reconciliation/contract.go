@@ -0,0 +1,35 @@
+package company_reconciliation
+
+import (
+	"context"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+)
+
+// ConfigRepository интерфейс репозитория конфигураций программ лояльности
+type ConfigRepository interface {
+	ListEnabledCompanyIDs(ctx context.Context, afterCompanyID int64, limit int) ([]int64, error)
+}
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	DisableCompanyConfig(ctx context.Context, companyID int64) (bool, error)
+}
+
+// SellerServiceClient интерфейс клиента SellerService
+type SellerServiceClient interface {
+	GetCompany(ctx context.Context, companyID int64) (*sellerservice.Company, error)
+}
+
+// Metrics интерфейс сбора метрик процесса (nil, если метрики выключены)
+type Metrics interface {
+	RecordLoyaltyConfigsDisabled(service string, count int64)
+	RecordCompanyReconciliation(service, status string)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
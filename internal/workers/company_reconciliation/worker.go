@@ -0,0 +1,172 @@
+package company_reconciliation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/integrations/sellerservice"
+)
+
+const (
+	reconciliationStatusSuccess = "success"
+	reconciliationStatusError   = "error"
+)
+
+// Config параметры фонового процесса
+type Config struct {
+	Interval    time.Duration
+	BatchSize   int
+	ServiceName string
+}
+
+// Worker периодически выключает программы лояльности компаний, удалённых в SellerService
+type Worker struct {
+	repo    ConfigRepository
+	service LoyaltyService
+	seller  SellerServiceClient
+	metrics Metrics
+	logger  Logger
+	cfg     Config
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewWorker создает фоновый процесс сверки компаний
+// metrics может быть nil, если метрики выключены
+func NewWorker(repo ConfigRepository, service LoyaltyService, seller SellerServiceClient, metrics Metrics, logger Logger, cfg Config) *Worker {
+	return &Worker{
+		repo:    repo,
+		service: service,
+		seller:  seller,
+		metrics: metrics,
+		logger:  logger,
+		cfg:     cfg,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start запускает процесс в отдельной горутине
+// Первый проход выполняется сразу, следующие - раз в cfg.Interval
+func (w *Worker) Start() {
+	go w.run()
+}
+
+// Stop останавливает процесс и ждёт завершения текущего прохода (не дольше, чем ctx)
+func (w *Worker) Stop(ctx context.Context) error {
+	close(w.stopCh)
+
+	select {
+	case <-w.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run() {
+	defer close(w.doneCh)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.reconcile()
+
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile обходит компании с включённой программой пачками по cfg.BatchSize и выключает программу
+// компаний, которые SellerService считает не найденными. При недоступности SellerService проход
+// прерывается до следующего запуска, чтобы сбой не принять за удаление компаний
+func (w *Worker) reconcile() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Прерываем запросы, если сервис останавливается
+	go func() {
+		select {
+		case <-w.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var checked, disabled int64
+	var afterCompanyID int64
+	for {
+		companyIDs, err := w.repo.ListEnabledCompanyIDs(ctx, afterCompanyID, w.cfg.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.Error("Company reconciliation failed: checked=%d, disabled=%d, error=%v", checked, disabled, err)
+				w.recordReconciliation(reconciliationStatusError)
+			}
+			return
+		}
+
+		for _, companyID := range companyIDs {
+			ok, err := w.reconcileCompany(ctx, companyID)
+			if err != nil {
+				if ctx.Err() == nil {
+					w.logger.Error("Company reconciliation failed: company_id=%d, checked=%d, disabled=%d, error=%v", companyID, checked, disabled, err)
+					w.recordReconciliation(reconciliationStatusError)
+				}
+				return
+			}
+
+			checked++
+			if ok {
+				disabled++
+			}
+		}
+
+		if len(companyIDs) < w.cfg.BatchSize || ctx.Err() != nil {
+			break
+		}
+		afterCompanyID = companyIDs[len(companyIDs)-1]
+	}
+
+	if disabled > 0 {
+		w.logger.Info("Company reconciliation finished: checked=%d, disabled=%d", checked, disabled)
+	}
+	w.recordReconciliation(reconciliationStatusSuccess)
+}
+
+// reconcileCompany выключает программу компании, если SellerService её не находит
+// Возвращает true, если программа была выключена
+func (w *Worker) reconcileCompany(ctx context.Context, companyID int64) (bool, error) {
+	_, err := w.seller.GetCompany(ctx, companyID)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, sellerservice.ErrCompanyNotFound) {
+		return false, err
+	}
+
+	disabled, err := w.service.DisableCompanyConfig(ctx, companyID)
+	if err != nil {
+		return false, err
+	}
+
+	if disabled {
+		w.logger.Warn("Loyalty program disabled: company not found in SellerService: company_id=%d", companyID)
+		if w.metrics != nil {
+			w.metrics.RecordLoyaltyConfigsDisabled(w.cfg.ServiceName, 1)
+		}
+	}
+
+	return disabled, nil
+}
+
+func (w *Worker) recordReconciliation(status string) {
+	if w.metrics != nil {
+		w.metrics.RecordCompanyReconciliation(w.cfg.ServiceName, status)
+	}
+}
@@ -0,0 +1,26 @@
+package config_activator
+
+import (
+	"context"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+// LoyaltyService интерфейс сервиса лояльности
+type LoyaltyService interface {
+	ActivateDueConfigs(ctx context.Context, now time.Time) ([]*domain.LoyaltyConfigSchedule, error)
+}
+
+// Metrics интерфейс сбора метрик процесса (nil, если метрики выключены)
+type Metrics interface {
+	RecordLoyaltyConfigActivation(service string, delay float64)
+	RecordConfigActivatorRun(service, status string)
+}
+
+// Logger интерфейс для логирования
+type Logger interface {
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
@@ -0,0 +1,138 @@
+package config_activator
+
+import (
+	"context"
+	"time"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/internal/domain"
+)
+
+const (
+	runStatusSuccess = "success"
+	runStatusError   = "error"
+)
+
+// Config параметры фонового процесса
+type Config struct {
+	Interval    time.Duration
+	ServiceName string
+}
+
+// Worker периодически переносит наступившие запланированные изменения программ лояльности в конфигурации
+// До переноса сервис сам учитывает наступившее изменение при чтении конфигурации, поэтому
+// интервал влияет только на то, когда обновятся скидки выпущенных карт и история версий
+type Worker struct {
+	service LoyaltyService
+	metrics Metrics
+	logger  Logger
+	cfg     Config
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewWorker создает фоновый процесс переноса запланированных изменений
+// metrics может быть nil, если метрики выключены
+func NewWorker(service LoyaltyService, metrics Metrics, logger Logger, cfg Config) *Worker {
+	return &Worker{
+		service: service,
+		metrics: metrics,
+		logger:  logger,
+		cfg:     cfg,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start запускает процесс в отдельной горутине
+// Первый проход выполняется сразу, следующие - раз в cfg.Interval
+func (w *Worker) Start() {
+	go w.run()
+}
+
+// Stop останавливает процесс и ждёт завершения текущего прохода (не дольше, чем ctx)
+func (w *Worker) Stop(ctx context.Context) error {
+	close(w.stopCh)
+
+	select {
+	case <-w.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run() {
+	defer close(w.doneCh)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.activate()
+
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// activate переносит наступившие изменения по одной компании за вызов сервиса,
+// пока они не закончатся или не придёт сигнал остановки
+func (w *Worker) activate() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Прерываем запросы к БД, если сервис останавливается
+	go func() {
+		select {
+		case <-w.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var total int
+	for ctx.Err() == nil {
+		now := time.Now()
+		resolved, err := w.service.ActivateDueConfigs(ctx, now)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.Error("Config activation failed: activated_before_error=%d, error=%v", total, err)
+				w.recordRun(runStatusError)
+			}
+			return
+		}
+
+		if len(resolved) == 0 {
+			break
+		}
+
+		for _, schedule := range resolved {
+			// Несовместимое изменение отменено сервисом, в конфигурацию оно не перенесено
+			if schedule.Status != domain.ConfigScheduleStatusApplied {
+				continue
+			}
+
+			total++
+			w.logger.Info("Scheduled config activated: company_id=%d, schedule_id=%d, effective_from=%s",
+				schedule.CompanyID, schedule.ID, schedule.EffectiveFrom.Format(time.RFC3339))
+			if w.metrics != nil {
+				w.metrics.RecordLoyaltyConfigActivation(w.cfg.ServiceName, now.Sub(schedule.EffectiveFrom).Seconds())
+			}
+		}
+	}
+
+	if total > 0 {
+		w.logger.Info("Config activation finished: activated=%d", total)
+	}
+	w.recordRun(runStatusSuccess)
+}
+
+func (w *Worker) recordRun(status string) {
+	if w.metrics != nil {
+		w.metrics.RecordConfigActivatorRun(w.cfg.ServiceName, status)
+	}
+}
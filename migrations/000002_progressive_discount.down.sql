@@ -0,0 +1 @@
+ALTER TABLE loyalty_cards DROP COLUMN IF EXISTS visits_count;
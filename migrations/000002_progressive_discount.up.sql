@@ -0,0 +1,3 @@
+-- Счётчик визитов для расчёта уровня прогрессивной скидки
+ALTER TABLE loyalty_cards
+    ADD COLUMN visits_count INTEGER NOT NULL DEFAULT 0 CHECK (visits_count >= 0);
@@ -0,0 +1,9 @@
+-- Удаляем триггер и функцию
+DROP TRIGGER IF EXISTS loyalty_transactions_append_only ON loyalty_transactions;
+DROP FUNCTION IF EXISTS prevent_loyalty_transactions_modification();
+
+-- Удаляем таблицу журнала
+DROP TABLE IF EXISTS loyalty_transactions;
+
+-- Удаляем баланс баллов
+ALTER TABLE loyalty_cards DROP COLUMN IF EXISTS points_balance;
@@ -0,0 +1,30 @@
+-- Баланс баллов карты (накопительная система)
+ALTER TABLE loyalty_cards
+    ADD COLUMN points_balance BIGINT NOT NULL DEFAULT 0 CHECK (points_balance >= 0);
+
+-- Журнал начислений и списаний баллов (append-only)
+CREATE TABLE loyalty_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    card_id BIGINT NOT NULL REFERENCES loyalty_cards(id),
+    type VARCHAR(20) NOT NULL,
+    points BIGINT NOT NULL CHECK (points > 0),
+    amount DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    balance_after BIGINT NOT NULL CHECK (balance_after >= 0),
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT loyalty_transactions_valid_type CHECK (type IN ('accrual', 'redemption'))
+);
+
+-- Индексы для loyalty_transactions
+CREATE INDEX idx_loyalty_transactions_card_created ON loyalty_transactions(card_id, created_at);
+
+-- Запрещаем изменение и удаление записей журнала
+CREATE OR REPLACE FUNCTION prevent_loyalty_transactions_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'loyalty_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER loyalty_transactions_append_only BEFORE UPDATE OR DELETE ON loyalty_transactions
+    FOR EACH ROW EXECUTE FUNCTION prevent_loyalty_transactions_modification();
@@ -0,0 +1,14 @@
+-- Таблица визитов клиентов по картам лояльности
+CREATE TABLE loyalty_visits (
+    id BIGSERIAL PRIMARY KEY,
+    card_id BIGINT NOT NULL REFERENCES loyalty_cards(id),
+    amount DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    service_id BIGINT,
+    visited_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    visit_number INTEGER NOT NULL CHECK (visit_number > 0),
+    recorded_by BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Индексы для loyalty_visits
+CREATE INDEX idx_loyalty_visits_card_visited ON loyalty_visits(card_id, visited_at);
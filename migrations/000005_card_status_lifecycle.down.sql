@@ -0,0 +1,6 @@
+ALTER TABLE loyalty_cards DROP CONSTRAINT IF EXISTS loyalty_cards_valid_status;
+
+ALTER TABLE loyalty_cards
+    DROP COLUMN IF EXISTS status_changed_by,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status_reason;
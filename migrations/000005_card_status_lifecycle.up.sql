@@ -0,0 +1,9 @@
+-- Причина и автор последней смены статуса карты
+ALTER TABLE loyalty_cards
+    ADD COLUMN status_reason TEXT,
+    ADD COLUMN status_changed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN status_changed_by BIGINT;
+
+-- Допустимые статусы карты
+ALTER TABLE loyalty_cards
+    ADD CONSTRAINT loyalty_cards_valid_status CHECK (status IN ('active', 'suspended', 'disabled', 'expired'));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_loyalty_cards_expires_at;
+
+ALTER TABLE loyalty_cards DROP COLUMN IF EXISTS expires_at;
+
+ALTER TABLE loyalty_configs DROP COLUMN IF EXISTS card_validity_days;
@@ -0,0 +1,11 @@
+-- Срок действия карт (в днях с момента выпуска), NULL - карты бессрочные
+ALTER TABLE loyalty_configs
+    ADD COLUMN card_validity_days INTEGER CHECK (card_validity_days > 0);
+
+-- Дата истечения карты, NULL - карта бессрочная
+ALTER TABLE loyalty_cards
+    ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Индекс для фонового перевода просроченных карт в expired
+CREATE INDEX idx_loyalty_cards_expires_at ON loyalty_cards(expires_at)
+    WHERE expires_at IS NOT NULL AND status IN ('active', 'suspended');
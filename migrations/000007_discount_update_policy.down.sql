@@ -0,0 +1,3 @@
+ALTER TABLE loyalty_configs
+    DROP CONSTRAINT IF EXISTS loyalty_configs_valid_discount_update_policy,
+    DROP COLUMN IF EXISTS discount_update_policy;
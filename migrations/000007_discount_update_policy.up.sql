@@ -0,0 +1,5 @@
+-- Политика применения изменённой скидки к уже выпущенным картам
+ALTER TABLE loyalty_configs
+    ADD COLUMN discount_update_policy VARCHAR(32) NOT NULL DEFAULT 'new_cards_only',
+    ADD CONSTRAINT loyalty_configs_valid_discount_update_policy
+        CHECK (discount_update_policy IN ('apply_to_all', 'new_cards_only', 'never_lower'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_loyalty_cards_company_points;
+DROP INDEX IF EXISTS idx_loyalty_cards_company_visits;
+DROP INDEX IF EXISTS idx_loyalty_cards_company_created;
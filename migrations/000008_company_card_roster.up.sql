@@ -0,0 +1,4 @@
+-- Индексы для keyset-пагинации списка карт компании по поддерживаемым полям сортировки
+CREATE INDEX idx_loyalty_cards_company_created ON loyalty_cards(company_id, created_at, id);
+CREATE INDEX idx_loyalty_cards_company_visits ON loyalty_cards(company_id, visits_count, id);
+CREATE INDEX idx_loyalty_cards_company_points ON loyalty_cards(company_id, points_balance, id);
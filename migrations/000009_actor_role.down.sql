@@ -0,0 +1,9 @@
+ALTER TABLE loyalty_configs
+    DROP COLUMN IF EXISTS updated_by_role,
+    DROP COLUMN IF EXISTS updated_by;
+
+ALTER TABLE loyalty_visits DROP COLUMN IF EXISTS recorded_by_role;
+
+ALTER TABLE loyalty_transactions DROP COLUMN IF EXISTS created_by_role;
+
+ALTER TABLE loyalty_cards DROP COLUMN IF EXISTS status_changed_by_role;
@@ -0,0 +1,15 @@
+-- Роль, в которой пользователь выполнил действие (customer, manager, superuser)
+-- Позволяет отличить действия суперпользователя от действий менеджеров компании
+ALTER TABLE loyalty_cards
+    ADD COLUMN status_changed_by_role VARCHAR(32);
+
+ALTER TABLE loyalty_transactions
+    ADD COLUMN created_by_role VARCHAR(32);
+
+ALTER TABLE loyalty_visits
+    ADD COLUMN recorded_by_role VARCHAR(32);
+
+-- Автор последнего изменения конфигурации программы
+ALTER TABLE loyalty_configs
+    ADD COLUMN updated_by BIGINT,
+    ADD COLUMN updated_by_role VARCHAR(32);
@@ -0,0 +1,20 @@
+-- Журнал изменений программ лояльности и карт
+-- Событие записывается в одной транзакции с изменением: before/after - состояние сущности до и после,
+-- changes - только изменённые поля ({"поле": {"before": ..., "after": ...}})
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    company_id BIGINT NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor_id BIGINT,
+    actor_role VARCHAR(32) NOT NULL,
+    actor_service VARCHAR(100),
+    before JSONB,
+    after JSONB NOT NULL,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- История компании читается от новых событий к старым
+CREATE INDEX idx_audit_events_company ON audit_events(company_id, id DESC);
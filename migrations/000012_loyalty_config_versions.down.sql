@@ -0,0 +1,8 @@
+-- Удаляем триггеры и функции
+DROP TRIGGER IF EXISTS loyalty_configs_record_version ON loyalty_configs;
+DROP FUNCTION IF EXISTS record_loyalty_config_version();
+DROP TRIGGER IF EXISTS loyalty_config_versions_append_only ON loyalty_config_versions;
+DROP FUNCTION IF EXISTS prevent_loyalty_config_versions_modification();
+
+-- Удаляем таблицу версий
+DROP TABLE IF EXISTS loyalty_config_versions;
@@ -0,0 +1,70 @@
+-- История версий конфигураций программ лояльности (append-only)
+-- Версия записывается триггером при каждом создании и изменении loyalty_configs, в той же транзакции
+CREATE TABLE loyalty_config_versions (
+    id BIGSERIAL PRIMARY KEY,
+    config_id BIGINT NOT NULL REFERENCES loyalty_configs(id),
+    company_id BIGINT NOT NULL,
+    version INTEGER NOT NULL CHECK (version > 0),
+    card_type VARCHAR(50) NOT NULL,
+    is_enabled BOOLEAN NOT NULL,
+    discount_percentage DECIMAL(5,2),
+    progressive_config JSONB,
+    points_config JSONB,
+    card_validity_days INTEGER,
+    discount_update_policy VARCHAR(32) NOT NULL,
+    created_by BIGINT,
+    created_by_role VARCHAR(32),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT loyalty_config_versions_unique_company_version UNIQUE (company_id, version)
+);
+
+-- Текущие конфигурации становятся первой версией
+INSERT INTO loyalty_config_versions (
+    config_id, company_id, version, card_type, is_enabled, discount_percentage, progressive_config, points_config,
+    card_validity_days, discount_update_policy, created_by, created_by_role, created_at
+)
+SELECT
+    id, company_id, 1, card_type, is_enabled, discount_percentage, progressive_config, points_config,
+    card_validity_days, discount_update_policy, updated_by, updated_by_role, updated_at
+FROM loyalty_configs;
+
+-- Номер версии выдаётся по порядку внутри компании
+-- Изменения одной компании сериализуются блокировкой строки loyalty_configs, поэтому MAX + 1 не конфликтует
+CREATE OR REPLACE FUNCTION record_loyalty_config_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO loyalty_config_versions (
+        config_id, company_id, version, card_type, is_enabled, discount_percentage, progressive_config, points_config,
+        card_validity_days, discount_update_policy, created_by, created_by_role
+    )
+    VALUES (
+        NEW.id,
+        NEW.company_id,
+        COALESCE((SELECT MAX(version) FROM loyalty_config_versions WHERE company_id = NEW.company_id), 0) + 1,
+        NEW.card_type,
+        NEW.is_enabled,
+        NEW.discount_percentage,
+        NEW.progressive_config,
+        NEW.points_config,
+        NEW.card_validity_days,
+        NEW.discount_update_policy,
+        NEW.updated_by,
+        NEW.updated_by_role
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER loyalty_configs_record_version AFTER INSERT OR UPDATE ON loyalty_configs
+    FOR EACH ROW EXECUTE FUNCTION record_loyalty_config_version();
+
+-- Запрещаем изменение и удаление версий
+CREATE OR REPLACE FUNCTION prevent_loyalty_config_versions_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'loyalty_config_versions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER loyalty_config_versions_append_only BEFORE UPDATE OR DELETE ON loyalty_config_versions
+    FOR EACH ROW EXECUTE FUNCTION prevent_loyalty_config_versions_modification();
@@ -0,0 +1,32 @@
+-- Запланированные изменения программ лояльности
+-- Хранят только параметры из запроса (NULL - параметр не меняется): изменение накладывается на конфигурацию,
+-- действующую в момент наступления effective_from. До переноса фоновым процессом в loyalty_configs
+-- сервис сам подставляет наступившее изменение при чтении конфигурации
+CREATE TABLE loyalty_config_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    company_id BIGINT NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    card_type VARCHAR(50),
+    is_enabled BOOLEAN,
+    discount_percentage DECIMAL(5,2) CHECK (discount_percentage >= 0 AND discount_percentage <= 100),
+    progressive_config JSONB,
+    points_config JSONB,
+    -- 0 - снять ограничение срока действия карт
+    card_validity_days INTEGER CHECK (card_validity_days >= 0),
+    discount_update_policy VARCHAR(32),
+    created_by BIGINT NOT NULL,
+    created_by_role VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT loyalty_config_schedules_valid_status CHECK (status IN ('pending', 'applied', 'canceled')),
+    CONSTRAINT loyalty_config_schedules_valid_card_type CHECK (card_type IN ('fixed_discount', 'progressive_discount', 'points_based'))
+);
+
+-- У компании не может быть двух ожидающих изменений на один момент
+CREATE UNIQUE INDEX idx_loyalty_config_schedules_pending_company
+    ON loyalty_config_schedules(company_id, effective_from) WHERE status = 'pending';
+
+-- Индекс для фонового переноса наступивших изменений
+CREATE INDEX idx_loyalty_config_schedules_pending_due
+    ON loyalty_config_schedules(effective_from) WHERE status = 'pending';
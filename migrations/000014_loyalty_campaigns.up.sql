@@ -0,0 +1,31 @@
+-- Промо-акции компаний, действующие поверх программы лояльности
+-- Акция действует в [starts_at, ends_at), внутри периода - только в дни weekdays (ISO 8601, пусто - каждый день)
+-- и в интервале window_start-window_end (NULL - весь день) по местному времени timezone
+CREATE TABLE loyalty_campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    company_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    weekdays SMALLINT[] NOT NULL DEFAULT '{}',
+    window_start TIME,
+    window_end TIME,
+    timezone VARCHAR(64) NOT NULL,
+    discount_percentage DECIMAL(5,2) NOT NULL DEFAULT 0 CHECK (discount_percentage >= 0 AND discount_percentage <= 100),
+    points_multiplier DECIMAL(5,2) NOT NULL DEFAULT 1 CHECK (points_multiplier >= 1),
+    stacking_rule VARCHAR(32) NOT NULL,
+    max_discount_percentage DECIMAL(5,2) CHECK (max_discount_percentage > 0 AND max_discount_percentage <= 100),
+    created_by BIGINT NOT NULL,
+    created_by_role VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT loyalty_campaigns_valid_period CHECK (ends_at > starts_at),
+    CONSTRAINT loyalty_campaigns_valid_window CHECK (
+        (window_start IS NULL AND window_end IS NULL)
+        OR (window_start IS NOT NULL AND window_end IS NOT NULL AND window_start < window_end)
+    ),
+    CONSTRAINT loyalty_campaigns_valid_stacking_rule CHECK (stacking_rule IN ('max', 'additive', 'capped')),
+    CONSTRAINT loyalty_campaigns_capped_limit CHECK ((stacking_rule = 'capped') = (max_discount_percentage IS NOT NULL))
+);
+
+-- Действующие акции компании выбираются по окончанию периода
+CREATE INDEX idx_loyalty_campaigns_company_ends ON loyalty_campaigns(company_id, ends_at);
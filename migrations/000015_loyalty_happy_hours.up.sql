@@ -0,0 +1,12 @@
+-- «Счастливые часы» компаний: интервалы по дням недели с дополнительной скидкой по картам
+-- windows - массив {weekday, start, end, discount_percentage}, время задано по местному времени timezone
+CREATE TABLE loyalty_happy_hours (
+    id BIGSERIAL PRIMARY KEY,
+    company_id BIGINT NOT NULL UNIQUE,
+    timezone VARCHAR(64) NOT NULL,
+    windows JSONB NOT NULL DEFAULT '[]',
+    updated_by BIGINT NOT NULL,
+    updated_by_role VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
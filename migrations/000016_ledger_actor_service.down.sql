@@ -0,0 +1,10 @@
+UPDATE loyalty_visits SET recorded_by = 0 WHERE recorded_by IS NULL;
+UPDATE loyalty_transactions SET created_by = 0 WHERE created_by IS NULL;
+
+ALTER TABLE loyalty_visits
+    DROP COLUMN IF EXISTS recorded_by_service,
+    ALTER COLUMN recorded_by SET NOT NULL;
+
+ALTER TABLE loyalty_transactions
+    DROP COLUMN IF EXISTS created_by_service,
+    ALTER COLUMN created_by SET NOT NULL;
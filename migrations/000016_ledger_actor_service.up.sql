@@ -0,0 +1,13 @@
+-- Операции с баллами и визиты, записанные внутренним сервисом по API-ключу, хранят имя сервиса
+-- вместо ID пользователя (created_by / recorded_by для них NULL)
+ALTER TABLE loyalty_transactions
+    ALTER COLUMN created_by DROP NOT NULL,
+    ADD COLUMN created_by_service VARCHAR(100);
+
+ALTER TABLE loyalty_visits
+    ALTER COLUMN recorded_by DROP NOT NULL,
+    ADD COLUMN recorded_by_service VARCHAR(100);
+
+-- Ранее такие записи сохранялись с автором 0; имя сервиса для них неизвестно
+UPDATE loyalty_transactions SET created_by = NULL WHERE created_by_role = 'service';
+UPDATE loyalty_visits SET recorded_by = NULL WHERE recorded_by_role = 'service';
@@ -0,0 +1,52 @@
+// Package apikey выпускает API-ключи внутренних сервисов и вычисляет их хеши для хранения
+//
+// Формат ключа: "smc_" + base64url(32 случайных байта). В БД хранится только SHA-256 хеш,
+// а начало ключа (Prefix) используется для поиска ключа при отзыве и в логах
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	keyPrefix = "smc_"
+
+	// prefixLength длина видимой части ключа, сохраняемой в открытом виде
+	prefixLength = len(keyPrefix) + 8
+
+	secretBytes = 32
+)
+
+// ErrMalformedKey возвращается, когда строка не похожа на API-ключ
+var ErrMalformedKey = errors.New("apikey: malformed key")
+
+// Generate выпускает новый случайный ключ
+func Generate() (string, error) {
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// Prefix возвращает видимую часть ключа
+func Prefix(key string) (string, error) {
+	if !strings.HasPrefix(key, keyPrefix) || len(key) <= prefixLength {
+		return "", ErrMalformedKey
+	}
+
+	return key[:prefixLength], nil
+}
+
+// Hash возвращает SHA-256 хеш ключа в hex
+// Ключи содержат 256 бит случайности, поэтому медленное хеширование (bcrypt и т.п.) не требуется
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,36 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key, err := Generate()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "smc_"))
+
+	other, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	prefix, err := Prefix(key)
+	require.NoError(t, err)
+	assert.Len(t, prefix, 12)
+	assert.True(t, strings.HasPrefix(key, prefix))
+}
+
+func TestPrefix_Malformed(t *testing.T) {
+	for _, key := range []string{"", "smc_", "smc_short", "xyz_0123456789abcdef"} {
+		_, err := Prefix(key)
+		assert.ErrorIs(t, err, ErrMalformedKey, key)
+	}
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Hash("hello"))
+	assert.NotEqual(t, Hash("smc_a"), Hash("smc_b"))
+}
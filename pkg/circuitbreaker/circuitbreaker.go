@@ -0,0 +1,166 @@
+// Package circuitbreaker реализует автоматический выключатель для вызовов внешних сервисов
+//
+// После FailureThreshold ошибок подряд выключатель размыкается и сразу отклоняет вызовы.
+// Через OpenTimeout он пропускает один пробный вызов (half-open): успех замыкает выключатель,
+// ошибка снова размыкает его на OpenTimeout
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen возвращается, когда выключатель разомкнут и вызов не выполняется
+var ErrOpen = errors.New("circuitbreaker: circuit open")
+
+// State состояние выключателя
+type State int
+
+const (
+	// StateClosed вызовы выполняются, ошибки подсчитываются
+	StateClosed State = iota
+	// StateHalfOpen пропускается один пробный вызов
+	StateHalfOpen
+	// StateOpen вызовы отклоняются без обращения к сервису
+	StateOpen
+)
+
+// String возвращает название состояния для логов
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config параметры выключателя
+type Config struct {
+	// FailureThreshold количество ошибок подряд, после которого выключатель размыкается
+	FailureThreshold int
+	// OpenTimeout время, на которое выключатель размыкается перед пробным вызовом
+	OpenTimeout time.Duration
+	// OnStateChange вызывается при смене состояния под блокировкой выключателя (может быть nil)
+	OnStateChange func(from, to State)
+}
+
+// Breaker автоматический выключатель, безопасен для конкурентного использования
+type Breaker struct {
+	cfg Config
+	now func() time.Time
+
+	mu             sync.Mutex
+	state          State
+	failures       int
+	openedAt       time.Time
+	probing        bool
+	probeStartedAt time.Time
+}
+
+// New создает замкнутый выключатель
+func New(cfg Config) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+
+	return &Breaker{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Allow сообщает, можно ли выполнить вызов. После разрешённого вызова нужно сообщить
+// результат через Success, Failure или Ignore
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return ErrOpen
+		}
+		b.setState(StateHalfOpen)
+	case StateHalfOpen:
+		// Пробный вызов, результат которого так и не сообщили, не блокирует выключатель навсегда
+		if b.probing && now.Sub(b.probeStartedAt) < b.cfg.OpenTimeout {
+			return ErrOpen
+		}
+	default:
+		return nil
+	}
+
+	b.probing = true
+	b.probeStartedAt = now
+
+	return nil
+}
+
+// Success сообщает об успешном вызове
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != StateClosed {
+		b.setState(StateClosed)
+	}
+}
+
+// Failure сообщает об ошибке вызова
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	switch b.state {
+	case StateHalfOpen:
+		b.open()
+	case StateClosed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+// Ignore сообщает, что результат вызова не говорит о состоянии сервиса (например, вызов отменён клиентом)
+func (b *Breaker) Ignore() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State возвращает текущее состояние выключателя
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) open() {
+	b.failures = 0
+	b.openedAt = b.now()
+	b.setState(StateOpen)
+}
+
+func (b *Breaker) setState(state State) {
+	from := b.state
+	b.state = state
+
+	if b.cfg.OnStateChange != nil && from != state {
+		b.cfg.OnStateChange(from, state)
+	}
+}
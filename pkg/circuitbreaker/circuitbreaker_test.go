@@ -0,0 +1,119 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transition struct {
+	from, to State
+}
+
+func newTestBreaker(now *time.Time, transitions *[]transition) *Breaker {
+	b := New(Config{
+		FailureThreshold: 3,
+		OpenTimeout:      10 * time.Second,
+		OnStateChange: func(from, to State) {
+			*transitions = append(*transitions, transition{from, to})
+		},
+	})
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var transitions []transition
+	b := newTestBreaker(&now, &transitions)
+
+	// Успешный вызов сбрасывает счётчик ошибок
+	for _, fail := range []bool{true, true, false, true, true} {
+		require.NoError(t, b.Allow())
+		if fail {
+			b.Failure()
+		} else {
+			b.Success()
+		}
+	}
+	assert.Equal(t, StateClosed, b.State())
+
+	require.NoError(t, b.Allow())
+	b.Failure()
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+	assert.Equal(t, []transition{{StateClosed, StateOpen}}, transitions)
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var transitions []transition
+	b := newTestBreaker(&now, &transitions)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Allow())
+		b.Failure()
+	}
+	require.Equal(t, StateOpen, b.State())
+
+	// Неудачный пробный вызов снова размыкает выключатель
+	now = now.Add(10 * time.Second)
+	require.NoError(t, b.Allow())
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen, "only one probe at a time")
+	b.Failure()
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	// Успешный пробный вызов замыкает выключатель
+	now = now.Add(10 * time.Second)
+	require.NoError(t, b.Allow())
+	b.Success()
+	assert.Equal(t, StateClosed, b.State())
+	assert.NoError(t, b.Allow())
+
+	assert.Equal(t, []transition{
+		{StateClosed, StateOpen},
+		{StateOpen, StateHalfOpen},
+		{StateHalfOpen, StateOpen},
+		{StateOpen, StateHalfOpen},
+		{StateHalfOpen, StateClosed},
+	}, transitions)
+}
+
+func TestBreaker_IgnoredProbeReleasesSlot(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var transitions []transition
+	b := newTestBreaker(&now, &transitions)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Allow())
+		b.Failure()
+	}
+
+	now = now.Add(10 * time.Second)
+	require.NoError(t, b.Allow())
+	b.Ignore()
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.NoError(t, b.Allow())
+}
+
+func TestBreaker_AbandonedProbeExpires(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var transitions []transition
+	b := newTestBreaker(&now, &transitions)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Allow())
+		b.Failure()
+	}
+
+	now = now.Add(10 * time.Second)
+	require.NoError(t, b.Allow())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	now = now.Add(10 * time.Second)
+	assert.NoError(t, b.Allow())
+}
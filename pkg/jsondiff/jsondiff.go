@@ -0,0 +1,97 @@
+// Package jsondiff сравнивает JSON-объекты по полям верхнего уровня
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// null значение отсутствующего поля
+var null = json.RawMessage("null")
+
+// Change значение поля до и после изменения
+type Change struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Diff возвращает поля верхнего уровня, значения которых в before и after различаются
+// Пустой before (nil или null) считается объектом без полей. Отсутствующее поле сравнивается как null,
+// поля из ignore не сравниваются
+func Diff(before, after json.RawMessage, ignore ...string) (map[string]Change, error) {
+	beforeFields, err := decodeObject(before)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff: before: %w", err)
+	}
+
+	afterFields, err := decodeObject(after)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff: after: %w", err)
+	}
+
+	for _, field := range ignore {
+		delete(beforeFields, field)
+		delete(afterFields, field)
+	}
+
+	changes := make(map[string]Change)
+	for field := range union(beforeFields, afterFields) {
+		b, ok := beforeFields[field]
+		if !ok {
+			b = null
+		}
+		a, ok := afterFields[field]
+		if !ok {
+			a = null
+		}
+
+		equal, err := equalJSON(b, a)
+		if err != nil {
+			return nil, fmt.Errorf("jsondiff: field %q: %w", field, err)
+		}
+		if !equal {
+			changes[field] = Change{Before: b, After: a}
+		}
+	}
+
+	return changes, nil
+}
+
+func decodeObject(data json.RawMessage) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(data) == 0 || bytes.Equal(bytes.TrimSpace(data), null) {
+		return fields, nil
+	}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func union(a, b map[string]json.RawMessage) map[string]struct{} {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	return keys
+}
+
+// equalJSON сравнивает значения без учёта форматирования и порядка ключей
+func equalJSON(a, b json.RawMessage) (bool, error) {
+	var av, bv interface{}
+	if err := json.Unmarshal(a, &av); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(b, &bv); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(av, bv), nil
+}
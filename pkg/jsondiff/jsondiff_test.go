@@ -0,0 +1,44 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_ChangedFields(t *testing.T) {
+	before := json.RawMessage(`{"is_enabled": true, "discount_percentage": 10, "tiers": [1, 2], "updated_at": "a", "removed": 1}`)
+	after := json.RawMessage(`{"tiers":[1,2],"discount_percentage":15,"is_enabled":true,"updated_at":"b","added":"x"}`)
+
+	changes, err := Diff(before, after, "updated_at")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Change{
+		"discount_percentage": {Before: json.RawMessage(`10`), After: json.RawMessage(`15`)},
+		"removed":             {Before: json.RawMessage(`1`), After: json.RawMessage(`null`)},
+		"added":               {Before: json.RawMessage(`null`), After: json.RawMessage(`"x"`)},
+	}, changes)
+}
+
+func TestDiff_Created(t *testing.T) {
+	changes, err := Diff(nil, json.RawMessage(`{"status":"active","visits_count":0}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Change{
+		"status":       {Before: json.RawMessage(`null`), After: json.RawMessage(`"active"`)},
+		"visits_count": {Before: json.RawMessage(`null`), After: json.RawMessage(`0`)},
+	}, changes)
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	changes, err := Diff(json.RawMessage(`{"a":{"x":1,"y":2}}`), json.RawMessage(`{"a":{"y":2,"x":1}}`))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiff_InvalidJSON(t *testing.T) {
+	_, err := Diff(json.RawMessage(`[1]`), json.RawMessage(`{}`))
+	assert.Error(t, err)
+}
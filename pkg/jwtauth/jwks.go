@@ -0,0 +1,89 @@
+package jwtauth
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// ErrNoKeys возвращается, когда в JWKS нет ни одного RSA-ключа для проверки подписи
+var ErrNoKeys = errors.New("jwtauth: no RSA signing keys in JWKS")
+
+// jwk публичный ключ в формате RFC 7517; поля, не относящиеся к RSA, не разбираются
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+// LoadJWKS читает JWKS из локального файла
+func LoadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwtauth: read JWKS file: %w", err)
+	}
+
+	return ParseJWKS(data)
+}
+
+// ParseJWKS разбирает JWKS и возвращает RSA-ключи подписи, ключ карты - kid
+// Ключи шифрования (use=enc) и ключи других алгоритмов пропускаются
+func ParseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwtauth: decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.KeyType != "RSA" || key.Use == "enc" || (key.Algorithm != "" && key.Algorithm != AlgorithmRS256) {
+			continue
+		}
+
+		publicKey, err := key.rsaPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwtauth: key %q: %w", key.KeyID, err)
+		}
+
+		if _, exists := keys[key.KeyID]; exists {
+			return nil, fmt.Errorf("jwtauth: duplicate key id %q", key.KeyID)
+		}
+		keys[key.KeyID] = publicKey
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	return keys, nil
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := encoding.DecodeString(k.N)
+	if err != nil || len(n) == 0 {
+		return nil, errors.New("invalid modulus")
+	}
+
+	e, err := encoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	exponent := 0
+	for _, b := range e {
+		exponent = exponent<<8 | int(b)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: exponent,
+	}, nil
+}
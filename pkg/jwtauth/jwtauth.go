@@ -0,0 +1,313 @@
+// Package jwtauth проверяет подписанные JWT (RFC 7519) и извлекает из них пользователя и его роль
+//
+// Поддерживаются HS256 с общим секретом и RS256 с публичными ключами из JWKS
+package jwtauth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AlgorithmHS256 HMAC-SHA256 с общим секретом
+	AlgorithmHS256 = "HS256"
+	// AlgorithmRS256 RSASSA-PKCS1-v1_5 с SHA-256 и публичными ключами из JWKS
+	AlgorithmRS256 = "RS256"
+
+	defaultUserIDClaim = "sub"
+	defaultRoleClaim   = "role"
+)
+
+var (
+	// ErrMalformedToken возвращается, когда токен не удаётся разобрать
+	ErrMalformedToken = errors.New("jwtauth: malformed token")
+
+	// ErrUnsupportedAlgorithm возвращается, когда алгоритм токена не совпадает с настроенным
+	ErrUnsupportedAlgorithm = errors.New("jwtauth: unsupported algorithm")
+
+	// ErrUnknownKey возвращается, когда в JWKS нет ключа с kid из заголовка токена
+	ErrUnknownKey = errors.New("jwtauth: unknown signing key")
+
+	// ErrInvalidSignature возвращается, когда подпись токена не совпадает
+	ErrInvalidSignature = errors.New("jwtauth: invalid signature")
+
+	// ErrTokenExpired возвращается, когда срок действия токена истёк
+	ErrTokenExpired = errors.New("jwtauth: token expired")
+
+	// ErrTokenNotYetValid возвращается, когда срок действия токена ещё не наступил (nbf)
+	ErrTokenNotYetValid = errors.New("jwtauth: token not yet valid")
+
+	// ErrInvalidClaims возвращается, когда обязательные claims отсутствуют или не совпадают с ожидаемыми
+	ErrInvalidClaims = errors.New("jwtauth: invalid claims")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Claims данные пользователя, извлечённые из токена
+type Claims struct {
+	UserID    int64
+	Role      string // пустая строка - роль в токене не указана
+	ExpiresAt time.Time
+}
+
+// Options параметры проверки claims
+type Options struct {
+	Issuer      string // пустая строка - iss не проверяется
+	Audience    string // пустая строка - aud не проверяется
+	UserIDClaim string // по умолчанию sub
+	RoleClaim   string // по умолчанию role
+	Leeway      time.Duration
+}
+
+// Verifier проверяет токены одного алгоритма
+type Verifier struct {
+	algorithm string
+	secret    []byte
+	keys      map[string]*rsa.PublicKey
+	opts      Options
+	now       func() time.Time
+}
+
+// NewHS256Verifier создает Verifier для токенов, подписанных общим секретом
+func NewHS256Verifier(secret string, opts Options) *Verifier {
+	return newVerifier(AlgorithmHS256, opts, func(v *Verifier) {
+		v.secret = []byte(secret)
+	})
+}
+
+// NewRS256Verifier создает Verifier для токенов, подписанных RSA-ключами; ключ - kid из JWKS
+func NewRS256Verifier(keys map[string]*rsa.PublicKey, opts Options) *Verifier {
+	return newVerifier(AlgorithmRS256, opts, func(v *Verifier) {
+		v.keys = keys
+	})
+}
+
+func newVerifier(algorithm string, opts Options, init func(v *Verifier)) *Verifier {
+	if opts.UserIDClaim == "" {
+		opts.UserIDClaim = defaultUserIDClaim
+	}
+	if opts.RoleClaim == "" {
+		opts.RoleClaim = defaultRoleClaim
+	}
+
+	v := &Verifier{
+		algorithm: algorithm,
+		opts:      opts,
+		now:       time.Now,
+	}
+	init(v)
+
+	return v
+}
+
+// header заголовок JWT
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// Verify проверяет подпись, срок действия и claims токена и возвращает данные пользователя
+// Токен без exp не принимается
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, ErrMalformedToken
+	}
+
+	rawHeader, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := json.Unmarshal(rawHeader, &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	// Алгоритм задаётся конфигурацией, а не токеном: защищает от alg=none и подмены RS256 на HS256
+	if h.Algorithm != v.algorithm {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := v.verifySignature(h, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	return v.validateClaims(raw)
+}
+
+func (v *Verifier) verifySignature(h header, signingInput string, signature []byte) error {
+	switch v.algorithm {
+	case AlgorithmHS256:
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signingInput))
+		// Сравнение за постоянное время, чтобы не раскрывать подпись через тайминги
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return ErrInvalidSignature
+		}
+	case AlgorithmRS256:
+		key, err := v.rsaKey(h.KeyID)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+
+	return nil
+}
+
+// rsaKey ищет ключ по kid; токен без kid допускается, только если в JWKS ровно один ключ
+func (v *Verifier) rsaKey(keyID string) (*rsa.PublicKey, error) {
+	if keyID == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+
+	key, ok := v.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+func (v *Verifier) validateClaims(raw map[string]interface{}) (*Claims, error) {
+	now := v.now()
+
+	exp, ok := numericDate(raw["exp"])
+	if !ok {
+		return nil, ErrInvalidClaims
+	}
+	if !now.Before(exp.Add(v.opts.Leeway)) {
+		return nil, ErrTokenExpired
+	}
+
+	if value, present := raw["nbf"]; present {
+		nbf, ok := numericDate(value)
+		if !ok {
+			return nil, ErrInvalidClaims
+		}
+		if now.Add(v.opts.Leeway).Before(nbf) {
+			return nil, ErrTokenNotYetValid
+		}
+	}
+
+	if v.opts.Issuer != "" {
+		if iss, _ := raw["iss"].(string); iss != v.opts.Issuer {
+			return nil, ErrInvalidClaims
+		}
+	}
+
+	if v.opts.Audience != "" && !containsAudience(raw["aud"], v.opts.Audience) {
+		return nil, ErrInvalidClaims
+	}
+
+	userID, ok := userIDClaim(raw[v.opts.UserIDClaim])
+	if !ok {
+		return nil, ErrInvalidClaims
+	}
+
+	claims := &Claims{
+		UserID:    userID,
+		ExpiresAt: exp.UTC(),
+	}
+
+	if value, present := raw[v.opts.RoleClaim]; present {
+		role, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidClaims
+		}
+		claims.Role = role
+	}
+
+	return claims, nil
+}
+
+// numericDate разбирает NumericDate (секунды с начала эпохи, допускается дробная часть)
+func numericDate(value interface{}) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	if seconds, err := number.Int64(); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, int64(seconds*float64(time.Second))), true
+}
+
+// userIDClaim принимает ID пользователя как числом, так и строкой (sub по RFC 7519 - строка)
+func userIDClaim(value interface{}) (int64, bool) {
+	var userID int64
+	var err error
+
+	switch v := value.(type) {
+	case json.Number:
+		userID, err = v.Int64()
+	case string:
+		userID, err = strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, false
+	}
+
+	if err != nil || userID <= 0 {
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// containsAudience проверяет aud, заданный строкой или массивом строк
+func containsAudience(value interface{}, audience string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,201 @@
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret-at-least-32-characters-long"
+
+var testNow = time.Unix(1_700_000_000, 0)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return encoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret string, header, claims map[string]interface{}) string {
+	t.Helper()
+	signingInput := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + encoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, header, claims map[string]interface{}) string {
+	t.Helper()
+	signingInput := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + encoding.EncodeToString(signature)
+}
+
+func newTestHS256Verifier(opts Options) *Verifier {
+	v := NewHS256Verifier(testSecret, opts)
+	v.now = func() time.Time { return testNow }
+	return v
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":  "42",
+		"role": "manager",
+		"exp":  testNow.Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifier_HS256(t *testing.T) {
+	v := newTestHS256Verifier(Options{})
+
+	claims, err := v.Verify(signHS256(t, testSecret, map[string]interface{}{"alg": "HS256", "typ": "JWT"}, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, Claims{UserID: 42, Role: "manager", ExpiresAt: testNow.Add(time.Hour).UTC()}, *claims)
+}
+
+func TestVerifier_NumericUserIDAndCustomClaims(t *testing.T) {
+	v := newTestHS256Verifier(Options{UserIDClaim: "user_id", RoleClaim: "user_role"})
+
+	claims, err := v.Verify(signHS256(t, testSecret, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{
+		"user_id": 7,
+		"exp":     testNow.Add(time.Minute).Unix(),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), claims.UserID)
+	assert.Empty(t, claims.Role)
+}
+
+func TestVerifier_Expired(t *testing.T) {
+	header := map[string]interface{}{"alg": "HS256"}
+	claims := validClaims()
+	claims["exp"] = testNow.Unix()
+
+	_, err := newTestHS256Verifier(Options{}).Verify(signHS256(t, testSecret, header, claims))
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	// Допуск на рассинхронизацию часов
+	_, err = newTestHS256Verifier(Options{Leeway: time.Minute}).Verify(signHS256(t, testSecret, header, claims))
+	assert.NoError(t, err)
+}
+
+func TestVerifier_NotYetValid(t *testing.T) {
+	claims := validClaims()
+	claims["nbf"] = testNow.Add(time.Minute).Unix()
+
+	_, err := newTestHS256Verifier(Options{}).Verify(signHS256(t, testSecret, map[string]interface{}{"alg": "HS256"}, claims))
+	assert.ErrorIs(t, err, ErrTokenNotYetValid)
+}
+
+func TestVerifier_InvalidSignature(t *testing.T) {
+	_, err := newTestHS256Verifier(Options{}).Verify(signHS256(t, "another-secret-at-least-32-characters", map[string]interface{}{"alg": "HS256"}, validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifier_RejectsOtherAlgorithms(t *testing.T) {
+	v := newTestHS256Verifier(Options{})
+
+	unsigned := encodeSegment(t, map[string]interface{}{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + "."
+	_, err := v.Verify(unsigned)
+	assert.ErrorIs(t, err, ErrMalformedToken)
+
+	_, err = v.Verify(unsigned + "c2ln")
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+
+	_, err = v.Verify(signHS256(t, testSecret, map[string]interface{}{"alg": "HS512"}, validClaims()))
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestVerifier_IssuerAndAudience(t *testing.T) {
+	v := newTestHS256Verifier(Options{Issuer: "auth", Audience: "loyalty"})
+	header := map[string]interface{}{"alg": "HS256"}
+
+	claims := validClaims()
+	claims["iss"] = "auth"
+	claims["aud"] = []string{"billing", "loyalty"}
+	_, err := v.Verify(signHS256(t, testSecret, header, claims))
+	assert.NoError(t, err)
+
+	claims["aud"] = "billing"
+	_, err = v.Verify(signHS256(t, testSecret, header, claims))
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+
+	claims["aud"] = "loyalty"
+	claims["iss"] = "other"
+	_, err = v.Verify(signHS256(t, testSecret, header, claims))
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+}
+
+func TestVerifier_MissingClaims(t *testing.T) {
+	v := newTestHS256Verifier(Options{})
+	header := map[string]interface{}{"alg": "HS256"}
+
+	claims := validClaims()
+	delete(claims, "exp")
+	_, err := v.Verify(signHS256(t, testSecret, header, claims))
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+
+	claims = validClaims()
+	claims["sub"] = "user-42"
+	_, err = v.Verify(signHS256(t, testSecret, header, claims))
+	assert.ErrorIs(t, err, ErrInvalidClaims)
+}
+
+func TestVerifier_RS256WithJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "main",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   encoding.EncodeToString(key.N.Bytes()),
+				"e":   encoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			},
+			{"kty": "EC", "kid": "ec", "crv": "P-256"},
+		},
+	})
+	require.NoError(t, err)
+
+	keys, err := ParseJWKS(jwks)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	v := NewRS256Verifier(keys, Options{})
+	v.now = func() time.Time { return testNow }
+
+	claims, err := v.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256", "kid": "main"}, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), claims.UserID)
+
+	_, err = v.Verify(signRS256(t, otherKey, map[string]interface{}{"alg": "RS256", "kid": "main"}, validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = v.Verify(signRS256(t, key, map[string]interface{}{"alg": "RS256", "kid": "rotated"}, validClaims()))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	// Подмена алгоритма: HS256 с публичным ключом в качестве секрета
+	_, err = v.Verify(signHS256(t, string(key.N.Bytes()), map[string]interface{}{"alg": "HS256", "kid": "main"}, validClaims()))
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestParseJWKS_NoKeys(t *testing.T) {
+	_, err := ParseJWKS([]byte(`{"keys":[{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"}]}`))
+	assert.ErrorIs(t, err, ErrNoKeys)
+}
@@ -19,6 +19,29 @@ type Metrics struct {
 	DBConnectionsActive prometheus.Gauge
 	DBConnectionsIdle   prometheus.Gauge
 	DBConnectionsMax    prometheus.Gauge
+
+	// Card expiry метрики
+	CardsExpiredTotal     *prometheus.CounterVec
+	CardExpirySweepsTotal *prometheus.CounterVec
+
+	// Company reconciliation метрики
+	LoyaltyConfigsDisabledTotal *prometheus.CounterVec
+	CompanyReconciliationsTotal *prometheus.CounterVec
+
+	// Scheduled config activation метрики
+	LoyaltyConfigActivationsTotal *prometheus.CounterVec
+	LoyaltyConfigActivationDelay  *prometheus.HistogramVec
+	ConfigActivatorRunsTotal      *prometheus.CounterVec
+
+	// Исходящие запросы к внешним сервисам
+	OutboundRequestsTotal   *prometheus.CounterVec
+	OutboundRequestDuration *prometheus.HistogramVec
+	OutboundRetriesTotal    *prometheus.CounterVec
+	CircuitBreakerState     *prometheus.GaugeVec
+
+	// Кэш ответов внешних сервисов
+	CacheRequestsTotal *prometheus.CounterVec
+	CacheEntries       *prometheus.GaugeVec
 }
 
 // New создаёт новый экземпляр метрик с автоматической регистрацией в Prometheus
@@ -105,6 +128,117 @@ func New(serviceName string) *Metrics {
 				},
 			},
 		),
+
+		// Card expiry метрики
+		CardsExpiredTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "loyalty_cards_expired_total",
+				Help: "Total number of loyalty cards moved to expired status",
+			},
+			[]string{"service"},
+		),
+
+		CardExpirySweepsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "loyalty_card_expiry_sweeps_total",
+				Help: "Total number of card expiry sweeps",
+			},
+			[]string{"service", "status"},
+		),
+
+		// Company reconciliation метрики
+		LoyaltyConfigsDisabledTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "loyalty_configs_disabled_total",
+				Help: "Total number of loyalty programs disabled because the company was not found in SellerService",
+			},
+			[]string{"service"},
+		),
+
+		CompanyReconciliationsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "loyalty_company_reconciliations_total",
+				Help: "Total number of company reconciliation passes",
+			},
+			[]string{"service", "status"},
+		),
+
+		// Scheduled config activation метрики
+		LoyaltyConfigActivationsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "loyalty_config_activations_total",
+				Help: "Total number of scheduled loyalty config changes that took effect",
+			},
+			[]string{"service"},
+		),
+
+		LoyaltyConfigActivationDelay: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "loyalty_config_activation_delay_seconds",
+				Help:    "Delay between effective_from of a scheduled loyalty config change and its activation",
+				Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+			},
+			[]string{"service"},
+		),
+
+		ConfigActivatorRunsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "loyalty_config_activator_runs_total",
+				Help: "Total number of scheduled config activator passes",
+			},
+			[]string{"service", "status"},
+		),
+
+		// Исходящие запросы к внешним сервисам
+		OutboundRequestsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "outbound_requests_total",
+				Help: "Total number of outbound requests to external services",
+			},
+			[]string{"service", "target", "operation", "status"},
+		),
+
+		OutboundRequestDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "outbound_request_duration_seconds",
+				Help:    "Outbound request duration in seconds",
+				Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"service", "target", "operation"},
+		),
+
+		OutboundRetriesTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "outbound_retries_total",
+				Help: "Total number of retried outbound requests",
+			},
+			[]string{"service", "target", "operation"},
+		),
+
+		CircuitBreakerState: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "circuit_breaker_state",
+				Help: "Circuit breaker state for external services (0 - closed, 1 - half-open, 2 - open)",
+			},
+			[]string{"service", "target"},
+		),
+
+		// Кэш ответов внешних сервисов
+		CacheRequestsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "cache_requests_total",
+				Help: "Total number of cache lookups by result (hit, negative_hit, miss)",
+			},
+			[]string{"service", "cache", "result"},
+		),
+
+		CacheEntries: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "cache_entries",
+				Help: "Current number of entries in the cache",
+			},
+			[]string{"service", "cache"},
+		),
 	}
 
 	return m
@@ -138,3 +272,61 @@ func (m *Metrics) UpdateDBConnectionStats(active, idle, max int) {
 	m.DBConnectionsIdle.Set(float64(idle))
 	m.DBConnectionsMax.Set(float64(max))
 }
+
+// RecordCardsExpired записывает количество карт, переведённых в expired
+func (m *Metrics) RecordCardsExpired(service string, count int64) {
+	m.CardsExpiredTotal.WithLabelValues(service).Add(float64(count))
+}
+
+// RecordCardExpirySweep записывает метрику прохода фонового процесса истечения карт
+func (m *Metrics) RecordCardExpirySweep(service, status string) {
+	m.CardExpirySweepsTotal.WithLabelValues(service, status).Inc()
+}
+
+// RecordLoyaltyConfigsDisabled записывает количество программ, выключенных для удалённых компаний
+func (m *Metrics) RecordLoyaltyConfigsDisabled(service string, count int64) {
+	m.LoyaltyConfigsDisabledTotal.WithLabelValues(service).Add(float64(count))
+}
+
+// RecordCompanyReconciliation записывает метрику прохода фонового процесса сверки компаний
+func (m *Metrics) RecordCompanyReconciliation(service, status string) {
+	m.CompanyReconciliationsTotal.WithLabelValues(service, status).Inc()
+}
+
+// RecordLoyaltyConfigActivation записывает вступление в силу запланированного изменения программы
+// delay - время от effective_from до переноса изменения в конфигурацию
+func (m *Metrics) RecordLoyaltyConfigActivation(service string, delay float64) {
+	m.LoyaltyConfigActivationsTotal.WithLabelValues(service).Inc()
+	m.LoyaltyConfigActivationDelay.WithLabelValues(service).Observe(delay)
+}
+
+// RecordConfigActivatorRun записывает метрику прохода фонового процесса переноса запланированных изменений
+func (m *Metrics) RecordConfigActivatorRun(service, status string) {
+	m.ConfigActivatorRunsTotal.WithLabelValues(service, status).Inc()
+}
+
+// RecordOutboundRequest записывает метрики попытки запроса к внешнему сервису
+func (m *Metrics) RecordOutboundRequest(service, target, operation, status string, duration float64) {
+	m.OutboundRequestsTotal.WithLabelValues(service, target, operation, status).Inc()
+	m.OutboundRequestDuration.WithLabelValues(service, target, operation).Observe(duration)
+}
+
+// RecordOutboundRetry записывает метрику повторного запроса к внешнему сервису
+func (m *Metrics) RecordOutboundRetry(service, target, operation string) {
+	m.OutboundRetriesTotal.WithLabelValues(service, target, operation).Inc()
+}
+
+// SetCircuitBreakerState обновляет состояние автоматического выключателя внешнего сервиса
+func (m *Metrics) SetCircuitBreakerState(service, target string, state int) {
+	m.CircuitBreakerState.WithLabelValues(service, target).Set(float64(state))
+}
+
+// RecordCacheRequest записывает метрику обращения к кэшу
+func (m *Metrics) RecordCacheRequest(service, cache, result string) {
+	m.CacheRequestsTotal.WithLabelValues(service, cache, result).Inc()
+}
+
+// SetCacheEntries обновляет количество записей в кэше
+func (m *Metrics) SetCacheEntries(service, cache string, count int) {
+	m.CacheEntries.WithLabelValues(service, cache).Set(float64(count))
+}
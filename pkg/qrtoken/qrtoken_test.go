@@ -0,0 +1,67 @@
+package qrtoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(secret string, now time.Time) *Signer {
+	s := NewSigner(secret, 5*time.Minute)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestSigner_IssueVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := newTestSigner("secret", now)
+
+	token, expiresAt, err := s.Issue(Claims{CardID: 1, UserID: 2, CompanyID: 3})
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(5*time.Minute).Unix(), expiresAt.Unix())
+
+	claims, err := s.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, Claims{CardID: 1, UserID: 2, CompanyID: 3, IssuedAt: now.Unix(), ExpiresAt: expiresAt.Unix()}, *claims)
+}
+
+func TestSigner_VerifyExpired(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	token, _, err := newTestSigner("secret", now).Issue(Claims{CardID: 1})
+	require.NoError(t, err)
+
+	_, err = newTestSigner("secret", now.Add(5*time.Minute)).Verify(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestSigner_VerifyTampered(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := newTestSigner("secret", now)
+
+	token, _, err := s.Issue(Claims{CardID: 1})
+	require.NoError(t, err)
+
+	forged, _, err := s.Issue(Claims{CardID: 2})
+	require.NoError(t, err)
+
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(token, ".")
+
+	_, err = s.Verify(payload + "." + signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = newTestSigner("other", now).Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigner_VerifyMalformed(t *testing.T) {
+	s := newTestSigner("secret", time.Unix(1_700_000_000, 0))
+
+	for _, token := range []string{"", "abc", "abc.", ".abc", "abc.!!!"} {
+		_, err := s.Verify(token)
+		assert.ErrorIs(t, err, ErrMalformedToken, token)
+	}
+}
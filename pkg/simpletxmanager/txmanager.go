@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
+	"github.com/m04kA/SMC-LoyaltySystemService/pkg/dbmetrics"
 )
 
 // TransactionManager простой менеджер транзакций без метрик
@@ -18,6 +20,11 @@ func NewTransactionManager(db *sql.DB) *TransactionManager {
 	}
 }
 
+// Do выполняет функцию внутри транзакции с уровнем изоляции по умолчанию
+func (tm *TransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return tm.DoWithOptions(ctx, nil, fn)
+}
+
 // DoSerializable выполняет функцию внутри транзакции с уровнем изоляции Serializable
 // Если функция завершается без ошибки, транзакция фиксируется (commit)
 // Если функция возвращает ошибку, транзакция откатывается (rollback)
@@ -29,6 +36,11 @@ func (tm *TransactionManager) DoSerializable(ctx context.Context, fn func(ctx co
 
 // DoWithOptions выполняет функцию внутри транзакции с указанными опциями
 func (tm *TransactionManager) DoWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	// Если уже в транзакции, переиспользуем её
+	if dbmetrics.IsInTransaction(ctx) {
+		return fn(ctx)
+	}
+
 	// Начинаем новую транзакцию
 	tx, err := tm.db.BeginTx(ctx, opts)
 	if err != nil {
@@ -44,9 +56,11 @@ func (tm *TransactionManager) DoWithOptions(ctx context.Context, opts *sql.TxOpt
 		}
 	}()
 
+	// Добавляем транзакцию в контекст, чтобы репозитории получили её через dbmetrics.GetExecutor
+	txCtx := dbmetrics.WithTx(ctx, &dbmetrics.SqlTxWrapper{Tx: tx})
+
 	// Выполняем функцию внутри транзакции
-	// Передаём контекст, который использует транзакцию
-	fnErr := fn(ctx)
+	fnErr := fn(txCtx)
 
 	if fnErr != nil {
 		// При ошибке откатываем транзакцию
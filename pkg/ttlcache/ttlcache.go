@@ -0,0 +1,111 @@
+// Package ttlcache реализует потокобезопасный in-memory кэш с ограниченным временем жизни записей
+//
+// Размер кэша ограничен: при переполнении вытесняется запись, к которой дольше всего не обращались (LRU).
+// Просроченные записи удаляются при обращении к ним или при вытеснении
+package ttlcache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache кэш с TTL записей и ограничением размера, безопасен для конкурентного использования
+type Cache[K comparable, V any] struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu    sync.Mutex
+	items map[K]*list.Element
+	// order записи в порядке обращения: в начале - самые свежие
+	order *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// New создает пустой кэш не более чем на maxEntries записей (maxEntries <= 0 - без ограничения)
+func New[K comparable, V any](maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		items:      make(map[K]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get возвращает значение по ключу, если запись есть и не просрочена
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+
+	elem, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := elem.Value.(*entry[K, V])
+	if !c.now().Before(e.expiresAt) {
+		c.removeElement(elem)
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return e.value, true
+}
+
+// Set сохраняет значение на время ttl, заменяя существующую запись
+// При переполнении вытесняется самая давняя по обращению запись
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Delete удаляет запись по ключу
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Len возвращает количество записей, включая ещё не удалённые просроченные
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache[K, V]) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,82 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(maxEntries int, now *time.Time) *Cache[string, int] {
+	c := New[string, int](maxEntries)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestCache_GetSet(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newTestCache(0, &now)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1, time.Minute)
+	v, ok := c.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// Повторный Set заменяет значение и продлевает TTL
+	now = now.Add(50 * time.Second)
+	c.Set("a", 2, time.Minute)
+	now = now.Add(50 * time.Second)
+	v, ok = c.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_Expiration(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newTestCache(0, &now)
+
+	c.Set("a", 1, 10*time.Second)
+	c.Set("b", 2, 0)
+
+	now = now.Add(9 * time.Second)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("b")
+	assert.False(t, ok, "zero ttl is not cached")
+
+	now = now.Add(time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len(), "expired entry is removed on access")
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newTestCache(2, &now)
+
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, time.Minute)
+
+	// Обращение к "a" делает вытесняемой запись "b"
+	_, ok := c.Get("a")
+	require.True(t, ok)
+
+	c.Set("c", 3, time.Minute)
+	assert.Equal(t, 2, c.Len())
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+}